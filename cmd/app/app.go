@@ -7,15 +7,22 @@ import (
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/background"
 	"github.com/ahleongzc/leetcode-live-backend/internal/consumer"
+	"github.com/ahleongzc/leetcode-live-backend/internal/service"
+
+	"github.com/rs/zerolog"
 )
 
 type Application struct {
 	HTTPServer *HTTPServer
 	RPCServer  *RPCServer
 
-	reviewConsumer *consumer.ReviewConsumer
-	housekeeper    background.HouseKeeper
-	workerPool     background.WorkerPool
+	reviewConsumer    *consumer.ReviewConsumer
+	studyPlanConsumer *consumer.StudyPlanConsumer
+	housekeeper       background.HouseKeeper
+	workerPool        background.WorkerPool
+
+	cannedPhraseService service.CannedPhraseService
+	logger              *zerolog.Logger
 
 	wg *sync.WaitGroup
 }
@@ -25,16 +32,24 @@ func NewApplication(
 	rpcServer *RPCServer,
 
 	reviewConsumer *consumer.ReviewConsumer,
+	studyPlanConsumer *consumer.StudyPlanConsumer,
 	housekeeper background.HouseKeeper,
 	workerPool background.WorkerPool,
+
+	cannedPhraseService service.CannedPhraseService,
+	logger *zerolog.Logger,
 ) *Application {
 	return &Application{
 		HTTPServer: httpServer,
 		RPCServer:  rpcServer,
 
-		housekeeper:    housekeeper,
-		reviewConsumer: reviewConsumer,
-		workerPool:     workerPool,
+		housekeeper:       housekeeper,
+		reviewConsumer:    reviewConsumer,
+		studyPlanConsumer: studyPlanConsumer,
+		workerPool:        workerPool,
+
+		cannedPhraseService: cannedPhraseService,
+		logger:              logger,
 
 		wg: &sync.WaitGroup{},
 	}
@@ -46,4 +61,16 @@ func (a *Application) StartHouseKeeping(ctx context.Context, interval time.Durat
 
 func (a *Application) StartConsumers(ctx context.Context, workerCount uint) {
 	go a.reviewConsumer.ConsumeAndProcess(ctx, workerCount)
+	go a.studyPlanConsumer.ConsumeAndProcess(ctx, workerCount)
+}
+
+// Runs in the background so that the servers do not wait for the TTS, the phrases are synthesized on first use if this fails
+func (a *Application) PresynthesizeCannedPhrases(ctx context.Context) {
+	go func() {
+		if err := a.cannedPhraseService.Presynthesize(ctx); err != nil {
+			a.logger.Error().
+				Err(err).
+				Msg("unable to presynthesize canned phrases")
+		}
+	}()
 }
@@ -0,0 +1,277 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	EXPORT_INTENT_SAMPLES_COMMAND   string = "export-intent-samples"
+	RE_REVIEW_COMMAND               string = "re-review"
+	MIGRATE_AUDIO_LOCATIONS_COMMAND string = "migrate-audio-locations"
+	REGRESS_COMMAND                 string = "regress"
+	GRANT_ROLE_COMMAND              string = "grant-role"
+)
+
+type CLI struct {
+	logger              *zerolog.Logger
+	intentSampleService service.IntentSampleService
+	reviewService       service.ReviewService
+	transcriptManager   service.TranscriptManager
+	regressionService   service.RegressionService
+	userService         service.UserService
+}
+
+func NewCLI(
+	logger *zerolog.Logger,
+	intentSampleService service.IntentSampleService,
+	reviewService service.ReviewService,
+	transcriptManager service.TranscriptManager,
+	regressionService service.RegressionService,
+	userService service.UserService,
+) *CLI {
+	return &CLI{
+		logger:              logger,
+		intentSampleService: intentSampleService,
+		reviewService:       reviewService,
+		transcriptManager:   transcriptManager,
+		regressionService:   regressionService,
+		userService:         userService,
+	}
+}
+
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	commands := []string{
+		EXPORT_INTENT_SAMPLES_COMMAND,
+		RE_REVIEW_COMMAND,
+		MIGRATE_AUDIO_LOCATIONS_COMMAND,
+		REGRESS_COMMAND,
+		GRANT_ROLE_COMMAND,
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("missing command, available commands are %s: %w", strings.Join(commands, ", "), common.ErrInvalidArgument)
+	}
+
+	switch args[0] {
+	case EXPORT_INTENT_SAMPLES_COMMAND:
+		return c.exportIntentSamples(ctx, args[1:])
+	case RE_REVIEW_COMMAND:
+		return c.reReview(ctx, args[1:])
+	case MIGRATE_AUDIO_LOCATIONS_COMMAND:
+		return c.migrateAudioLocations(ctx)
+	case REGRESS_COMMAND:
+		return c.regress(ctx, args[1:])
+	case GRANT_ROLE_COMMAND:
+		return c.grantRole(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %s, available commands are %s: %w", args[0], strings.Join(commands, ", "), common.ErrInvalidArgument)
+	}
+}
+
+// Reviewer and admin access can only be granted from here, the HTTP API never changes roles
+func (c *CLI) grantRole(ctx context.Context, args []string) error {
+	flagSet := flag.NewFlagSet(GRANT_ROLE_COMMAND, flag.ContinueOnError)
+	email := flagSet.String("email", "", "email of the user to grant the role to")
+	role := flagSet.String("role", "", "role to grant, one of candidate, reviewer or admin")
+
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w", err, common.ErrInvalidArgument)
+	}
+
+	if *email == "" || *role == "" {
+		return fmt.Errorf("both -email and -role must be set: %w", common.ErrInvalidArgument)
+	}
+
+	if err := c.userService.GrantRole(ctx, *email, entity.UserRole(*role)); err != nil {
+		return err
+	}
+
+	c.logger.Info().
+		Str("email", *email).
+		Str("role", *role).
+		Msg("granted role")
+
+	return nil
+}
+
+// Appends the labeled intent samples to the raw training data, run `make train` afterwards to retrain the model
+func (c *CLI) exportIntentSamples(ctx context.Context, args []string) error {
+	flagSet := flag.NewFlagSet(EXPORT_INTENT_SAMPLES_COMMAND, flag.ContinueOnError)
+	output := flagSet.String("output", config.INTENT_SAMPLE_DEFAULT_EXPORT_PATH, "training data file to append the labeled samples to")
+
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w", err, common.ErrInvalidArgument)
+	}
+
+	file, err := os.OpenFile(*output, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open %s, %s: %w", *output, err, common.ErrInternalServerError)
+	}
+	defer file.Close()
+
+	if err := c.ensureTrailingNewline(file); err != nil {
+		return err
+	}
+
+	count, err := c.intentSampleService.ExportLabeledSamples(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info().
+		Uint("exported_count", count).
+		Str("output", *output).
+		Msg("exported labeled intent samples")
+
+	return nil
+}
+
+// Every sample is a line of its own, so the first one must not be glued onto the last line of the existing data
+func (c *CLI) ensureTrailingNewline(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to stat %s, %s: %w", file.Name(), err, common.ErrInternalServerError)
+	}
+
+	if info.Size() == 0 {
+		return nil
+	}
+
+	lastByte := make([]byte, 1)
+	if _, err := file.ReadAt(lastByte, info.Size()-1); err != nil {
+		return fmt.Errorf("unable to read %s, %s: %w", file.Name(), err, common.ErrInternalServerError)
+	}
+
+	if lastByte[0] == '\n' {
+		return nil
+	}
+
+	if _, err := file.WriteString("\n"); err != nil {
+		return fmt.Errorf("unable to write to %s, %s: %w", file.Name(), err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// Enqueues re-reviews for the review consumer of the running application to pick up
+func (c *CLI) reReview(ctx context.Context, args []string) error {
+	flagSet := flag.NewFlagSet(RE_REVIEW_COMMAND, flag.ContinueOnError)
+	interviewID := flagSet.String("interview", "", "UUID of the interview to re-review")
+	questionID := flagSet.String("question", "", "external ID of the question to re-review every attempt of")
+
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w", err, common.ErrInvalidArgument)
+	}
+
+	if (*interviewID == "") == (*questionID == "") {
+		return fmt.Errorf("exactly one of -interview or -question must be set: %w", common.ErrInvalidArgument)
+	}
+
+	if *interviewID != "" {
+		if err := c.reviewService.EnqueueReReview(ctx, *interviewID); err != nil {
+			return err
+		}
+
+		c.logger.Info().
+			Str("interview_id", *interviewID).
+			Msg("enqueued re-review")
+
+		return nil
+	}
+
+	count, err := c.reviewService.EnqueueReReviewsForQuestion(ctx, *questionID)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info().
+		Uint("enqueued_count", count).
+		Str("question_id", *questionID).
+		Msg("enqueued re-reviews")
+
+	return nil
+}
+
+// Backfills the object location of the transcripts that were saved with a presigned URL only
+func (c *CLI) migrateAudioLocations(ctx context.Context) error {
+	count, err := c.transcriptManager.MigrateAudioLocations(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info().
+		Uint("migrated_count", count).
+		Msg("migrated audio locations")
+
+	return nil
+}
+
+// Replays the stored interviews against another model or prompt version and writes the judged comparison as JSON
+func (c *CLI) regress(ctx context.Context, args []string) error {
+	flagSet := flag.NewFlagSet(REGRESS_COMMAND, flag.ContinueOnError)
+	interviewIDs := flagSet.String("interview", "", "comma separated UUIDs of the interviews to replay")
+	questionID := flagSet.String("question", "", "external ID of the question to replay the latest ended interviews of")
+	limit := flagSet.Uint("limit", config.REGRESSION_DEFAULT_INTERVIEW_LIMIT, "maximum number of interviews of the question to replay")
+	llmModel := flagSet.String("model", "", "LLM model to replay with, the default model when empty")
+	promptVersion := flagSet.String("prompt-version", "", "prompt version to replay with, the latest version when empty")
+	judgeModel := flagSet.String("judge-model", "", "LLM model of the judge, the default model when empty")
+	output := flagSet.String("output", config.REGRESSION_DEFAULT_REPORT_PATH, "file to write the report to")
+
+	if err := flagSet.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w", err, common.ErrInvalidArgument)
+	}
+
+	if (*interviewIDs == "") == (*questionID == "") {
+		return fmt.Errorf("exactly one of -interview or -question must be set: %w", common.ErrInvalidArgument)
+	}
+
+	regressionConfig := model.NewRegressionConfig().
+		SetQuestionID(*questionID).
+		SetLimit(*limit).
+		SetModel(*llmModel).
+		SetPromptVersion(*promptVersion).
+		SetJudgeModel(*judgeModel)
+
+	if *interviewIDs != "" {
+		regressionConfig.SetInterviewIDs(strings.Split(*interviewIDs, ","))
+	}
+
+	report, err := c.regressionService.Run(ctx, regressionConfig)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal the regression report, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	if err := os.WriteFile(*output, content, 0644); err != nil {
+		return fmt.Errorf("unable to write %s, %s: %w", *output, err, common.ErrInternalServerError)
+	}
+
+	c.logger.Info().
+		Uint("interview_count", report.InterviewCount).
+		Uint("reply_count", report.ReplyCount).
+		Uint("failed_count", report.FailedCount).
+		Uint("replayed_wins", report.ReplayedWins).
+		Uint("original_wins", report.OriginalWins).
+		Uint("ties", report.Ties).
+		Str("output", *output).
+		Msg("replayed interviews")
+
+	return nil
+}
@@ -21,9 +21,12 @@ type HTTPServer struct {
 	httpServerConfig *config.HTTPServerConfig
 
 	authHandler      *httphandler.AuthHandler
+	adminHandler     *httphandler.AdminHandler
+	reviewerHandler  *httphandler.ReviewerHandler
 	userHandler      *httphandler.UserHandler
 	healthHandler    *httphandler.HealthHandler
 	interviewHandler *httphandler.InterviewHandler
+	fileHandler      *httphandler.FileHandler
 }
 
 func NewHTTPServer(
@@ -32,9 +35,12 @@ func NewHTTPServer(
 	httpServerConfig *config.HTTPServerConfig,
 
 	authHandler *httphandler.AuthHandler,
+	adminHandler *httphandler.AdminHandler,
+	reviewerHandler *httphandler.ReviewerHandler,
 	userHandler *httphandler.UserHandler,
 	healthHandler *httphandler.HealthHandler,
 	interviewHandler *httphandler.InterviewHandler,
+	fileHandler *httphandler.FileHandler,
 ) *HTTPServer {
 	srv := &http.Server{
 		Addr:         httpServerConfig.Address,
@@ -48,9 +54,12 @@ func NewHTTPServer(
 		logger:           logger,
 		middleware:       middleware,
 		authHandler:      authHandler,
+		adminHandler:     adminHandler,
+		reviewerHandler:  reviewerHandler,
 		userHandler:      userHandler,
 		healthHandler:    healthHandler,
 		interviewHandler: interviewHandler,
+		fileHandler:      fileHandler,
 		httpServerConfig: httpServerConfig,
 	}
 }
@@ -92,11 +101,13 @@ func (hs *HTTPServer) setUpHandlers() http.Handler {
 
 	mux.HandleFunc("POST /v1/auth/login", hs.authHandler.Login)
 	mux.HandleFunc("POST /v1/user/register", hs.userHandler.Register)
+	mux.HandleFunc("GET "+config.SIGNED_FILE_URL_PATH+"/{bucket}/{key...}", hs.fileHandler.ServeSignedFile)
 	// ---
 
 	// --- These routes require X-Session-Token to be in the headers
 	protected := alice.New(hs.middleware.Authenticate, hs.middleware.SetUserID, hs.middleware.SetSessionTokenInResponseHeader)
 	mux.Handle("GET /v1/user", protected.ThenFunc(hs.userHandler.GetUserProfile))
+	mux.Handle("GET /v1/user/study-plan", protected.ThenFunc(hs.userHandler.GetStudyPlan))
 	mux.Handle("POST /v1/auth/logout", protected.ThenFunc(hs.authHandler.Logout))
 
 	mux.Handle("POST /v1/interview/set-up-new", protected.ThenFunc(hs.interviewHandler.SetUpNewInterview))
@@ -106,6 +117,44 @@ func (hs *HTTPServer) setUpHandlers() http.Handler {
 	mux.Handle("GET /v1/interview/ongoing", protected.ThenFunc(hs.interviewHandler.GetOngoingInterview))
 	mux.Handle("GET /v1/interview/history", protected.ThenFunc(hs.interviewHandler.GetInterviewHistory))
 	mux.Handle("GET /v1/interview/unfinished", protected.ThenFunc(hs.interviewHandler.GetUnfinishedInterview))
+	mux.Handle("GET /v1/interview/{id}", protected.ThenFunc(hs.interviewHandler.GetInterviewReplay))
+	mux.Handle("GET /v1/interview/{id}/transcript", protected.ThenFunc(hs.interviewHandler.ExportTranscript))
+	// Audio elements cannot send X-Session-Token, so a signed URL is accepted in place of the session
+	streamTranscriptAudio := protected.ThenFunc(hs.interviewHandler.StreamTranscriptAudio)
+	mux.HandleFunc("GET /v1/transcript/{id}/audio", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has(config.SIGNED_FILE_URL_SIGNATURE_QUERY_KEY) {
+			hs.interviewHandler.StreamSignedTranscriptAudio(w, r)
+			return
+		}
+		streamTranscriptAudio.ServeHTTP(w, r)
+	})
+	// ---
+
+	// --- These routes require the user to be an admin on top of X-Session-Token
+	admin := protected.Append(hs.middleware.RequireAdmin)
+	mux.Handle("GET /v1/admin/intent-sample/low-confidence", admin.ThenFunc(hs.adminHandler.ListLowConfidenceIntentSamples))
+	mux.Handle("POST /v1/admin/intent-sample/{id}/label", admin.ThenFunc(hs.adminHandler.LabelIntentSample))
+
+	mux.Handle("POST /v1/admin/interview/{id}/re-review", admin.ThenFunc(hs.adminHandler.ReReviewInterview))
+	mux.Handle("POST /v1/admin/question/{id}/re-review", admin.ThenFunc(hs.adminHandler.ReReviewQuestion))
+	mux.Handle("PUT /v1/admin/question/{id}/reference-solution", admin.ThenFunc(hs.adminHandler.SetQuestionReferenceSolution))
+	mux.Handle("GET /v1/admin/interview/{id}/review-versions", admin.ThenFunc(hs.adminHandler.ListReviewVersions))
+	mux.Handle("PUT /v1/admin/interview/{id}/review-version", admin.ThenFunc(hs.adminHandler.SelectDisplayedReviewVersion))
+	mux.Handle("POST /v1/admin/experiment", admin.ThenFunc(hs.adminHandler.CreateExperiment))
+	mux.Handle("POST /v1/admin/experiment/{id}/activate", admin.ThenFunc(hs.adminHandler.ActivateExperiment))
+	mux.Handle("POST /v1/admin/experiment/{id}/deactivate", admin.ThenFunc(hs.adminHandler.DeactivateExperiment))
+	mux.Handle("GET /v1/admin/experiment/{id}/report", admin.ThenFunc(hs.adminHandler.GetExperimentReport))
+	// ---
+
+	// --- These routes require the user to be a reviewer or an admin on top of X-Session-Token
+	reviewer := protected.Append(hs.middleware.RequireReviewer)
+	mux.Handle("GET /v1/reviewer/interview/{id}/review-versions", reviewer.ThenFunc(hs.reviewerHandler.ListReviewVersions))
+	mux.Handle("POST /v1/reviewer/interview/{id}/review-override", reviewer.ThenFunc(hs.reviewerHandler.OverrideReview))
+	mux.Handle("GET /v1/reviewer/interview/{id}/audit-log", reviewer.ThenFunc(hs.reviewerHandler.ListAuditLogs))
+
+	mux.Handle("GET /v1/reviewer/interview/{id}/annotation", reviewer.ThenFunc(hs.reviewerHandler.ListAnnotations))
+	mux.Handle("POST /v1/reviewer/interview/{id}/transcript/{transcript_id}/annotation", reviewer.ThenFunc(hs.reviewerHandler.AnnotateTranscript))
+	mux.Handle("DELETE /v1/reviewer/annotation/{id}", reviewer.ThenFunc(hs.reviewerHandler.DeleteAnnotation))
 	// ---
 
 	return alice.New(
@@ -33,6 +33,7 @@ func main() {
 
 	app.StartHouseKeeping(ctx, config.HOUSEKEEPING_INTERVAL)
 	app.StartConsumers(ctx, config.CONSUMER_POOL_SIZE)
+	app.PresynthesizeCannedPhrases(ctx)
 
 	<-errChan
 
@@ -29,7 +29,8 @@ const (
 	MESSAGE_QUEUE_RESEND_DELAY_SEC_KEY    string = "MESSAGE_QUEUE_RESEND_DELAY_SEC"
 
 	// Queue Names
-	REVIEW_QUEUE string = "review"
+	REVIEW_QUEUE     string = "review"
+	STUDY_PLAN_QUEUE string = "study_plan"
 
 	// Database
 	DB_DSN_KEY               string = "DB_DSN"
@@ -38,12 +39,16 @@ const (
 	DB_MAX_IDLE_TIME_SEC_KEY string = "DB_MAX_IDLE_TIME_SEC"
 
 	// Object Storage
-	OBJECT_STORAGE_ACCESS_KEY     string        = "OBJECT_STORAGE_ACCESS_KEY"
-	OBJECT_STORAGE_SECRET_KEY     string        = "OBJECT_STORAGE_SECRET_KEY"
-	OBJECT_STORAGE_ENDPOINT_KEY   string        = "OBJECT_STORAGE_ENDPOINT"
-	OBJECT_STORAGE_BUCKET_KEY     string        = "OBJECT_STORAGE_BUCKET"
-	OBJECT_STORAGE_REGION_KEY     string        = "OBJECT_STORAGE_REGION"
-	PRESIGNED_URL_EXPIRY_DURATION time.Duration = 15 * time.Minute
+	OBJECT_STORAGE_PROVIDER_KEY        string        = "OBJECT_STORAGE_PROVIDER"
+	OBJECT_STORAGE_LOCAL_DIRECTORY_KEY string        = "OBJECT_STORAGE_LOCAL_DIRECTORY"
+	OBJECT_STORAGE_SIGNING_SECRET_KEY  string        = "OBJECT_STORAGE_SIGNING_SECRET"
+	OBJECT_STORAGE_PUBLIC_BASE_URL_KEY string        = "OBJECT_STORAGE_PUBLIC_BASE_URL"
+	OBJECT_STORAGE_ACCESS_KEY          string        = "OBJECT_STORAGE_ACCESS_KEY"
+	OBJECT_STORAGE_SECRET_KEY          string        = "OBJECT_STORAGE_SECRET_KEY"
+	OBJECT_STORAGE_ENDPOINT_KEY        string        = "OBJECT_STORAGE_ENDPOINT"
+	OBJECT_STORAGE_BUCKET_KEY          string        = "OBJECT_STORAGE_BUCKET"
+	OBJECT_STORAGE_REGION_KEY          string        = "OBJECT_STORAGE_REGION"
+	PRESIGNED_URL_EXPIRY_DURATION      time.Duration = 15 * time.Minute
 
 	// AI Providers
 	OLLAMA string = "ollama"
@@ -54,6 +59,17 @@ const (
 	LLM_MODEL_KEY    string = "LLM_MODEL"
 	LLM_BASE_URL_KEY string = "LLM_BASE_URL"
 	LLM_API_KEY      string = "LLM_API_KEY"
+	// Comma separated, the review samples are spread across these models in a round robin manner
+	LLM_REVIEW_MODELS_KEY       string = "LLM_REVIEW_MODELS"
+	LLM_REVIEW_SAMPLE_COUNT_KEY string = "LLM_REVIEW_SAMPLE_COUNT"
+	// Comma separated model=tokens pairs
+	LLM_CONTEXT_BUDGETS_KEY        string = "LLM_CONTEXT_BUDGETS"
+	LLM_DEFAULT_CONTEXT_BUDGET_KEY string = "LLM_DEFAULT_CONTEXT_BUDGET"
+
+	// Prompt injection
+	INJECTION_POLICY_KEY             string = "INJECTION_POLICY"
+	INJECTION_CLASSIFIER_ENABLED_KEY string = "INJECTION_CLASSIFIER_ENABLED"
+	INJECTION_CLASSIFIER_MODEL_KEY   string = "INJECTION_CLASSIFIER_MODEL"
 
 	// TTS
 	TTS_PROVIDER_KEY string = "TTS_PROVIDER"
@@ -17,4 +17,5 @@ var (
 	ErrNotConnected        = errors.New("not connected to a server")
 	ErrAlreadyClosed       = errors.New("already closed: not connected to the server")
 	ErrShutdown            = errors.New("client is shutting down")
+	ErrMalformedLLMOutput  = errors.New("malformed llm output")
 )
@@ -30,10 +30,84 @@ const (
 	PAGINATION_DEFAULT_OFFSET uint = 0
 	PAGINATION_DEFAULT_LIMIT  uint = 10
 	PAGINATION_MAX_LIMIT      uint = 20
+
+	// Intent classification
+	INTENT_SAMPLE_DEFAULT_MAX_CONFIDENCE float64 = 80
+	INTENT_SAMPLE_DEFAULT_EXPORT_PATH    string  = "./scripts/data.txt"
+	// Out of 100, the interviewer only replies when the classifier is more confident than this
+	INTENT_CLASSIFICATION_DEFAULT_THRESHOLD float64 = 70
+
+	// LLM
+	LLM_MAX_REPAIR_ATTEMPTS uint = 2
+
+	// Context window
+	// In tokens, left out of the context budget for the prompt of the request and the reply
+	CONTEXT_WINDOW_REPLY_RESERVE int = 512
+	// These many latest turns are always sent as they are, even when they go over the budget
+	CONTEXT_WINDOW_MIN_RECENT_TURNS int = 4
+
+	// Review
+	REVIEW_MAX_FEEDBACK_ITEMS uint = 8
+
+	// Solution leak guard
+	GUARD_MAX_REGENERATIONS uint = 1
+	// A reply with more lines of code than this leaks the solution even without a code block
+	GUARD_MAX_CODE_LINES int = 1
+	// A reply that lists more steps than this leaks the algorithm
+	GUARD_MAX_ALGORITHM_STEPS int = 3
+	// A hint can go through a few steps in prose, a reply with more sentences than this that open with a sequence word leaks the algorithm
+	GUARD_MAX_PROSE_ALGORITHM_STEPS int = 4
+	// In words, the length of the sequences that the reply and the reference solution are compared by
+	GUARD_REFERENCE_SHINGLE_SIZE int = 4
+	// Out of 1, the share of the word sequences of the reply that also appear in the reference solution
+	GUARD_REFERENCE_OVERLAP_THRESHOLD float64 = 0.3
+	// Said instead when nothing of the reply is left after softening it
+	GUARD_FALLBACK_REPLY string = "Let's not jump to the full solution yet. What do you think the next step should be?"
+
+	// Prompt injection
+	// Out of 100, the classifier has to be more confident than this for the content to count as an injection
+	INJECTION_CLASSIFIER_THRESHOLD float64 = 80
+
+	// Regression
+	REGRESSION_DEFAULT_INTERVIEW_LIMIT uint   = 20
+	REGRESSION_DEFAULT_REPORT_PATH     string = "./regression_report.json"
+	// These many turns before the reply are shown to the judge
+	REGRESSION_JUDGE_CONTEXT_TURNS int = 6
+
+	// Study plan
+	STUDY_PLAN_HISTORY_LIMIT       uint = 10
+	STUDY_PLAN_MAX_WEAK_TOPICS     uint = 3
+	STUDY_PLAN_MAX_RECOMMENDATIONS uint = 5
+	STUDY_PLAN_MAX_EXERCISES       uint = 5
+	// The question bank offers these many questions for each weak topic
+	STUDY_PLAN_QUESTIONS_PER_WEAK_TOPIC uint = 3
+
+	// Object storage
+	SIGNED_FILE_URL_PATH                string = "/v1/file"
+	SIGNED_FILE_URL_EXPIRES_QUERY_KEY   string = "expires"
+	SIGNED_FILE_URL_SIGNATURE_QUERY_KEY string = "signature"
+
+	// TTS cache
+	TTS_CACHE_LRU_CAPACITY int    = 1000
+	TTS_CACHE_KEY_PREFIX   string = "tts_cache"
+
+	// Transcript audio
+	TRANSCRIPT_AUDIO_URL_PATH_FORMAT string = "/v1/transcript/%s/audio"
+	// The audio of a transcript never changes, so the browser can keep it for a long time
+	TRANSCRIPT_AUDIO_CACHE_MAX_AGE time.Duration = 24 * time.Hour
+
+	// Migration
+	AUDIO_LOCATION_MIGRATION_BATCH_SIZE uint = 500
+
+	// Transcript export
+	TRANSCRIPT_EXPORT_LAST_CUE_DURATION time.Duration = 5 * time.Second
+	TRANSCRIPT_EXPORT_MIN_CUE_DURATION  time.Duration = 1 * time.Second
 )
 
 var (
 	PROD_TRUSTED_ORIGINS = map[string]struct{}{
 		"https://leetcode.com": {},
 	}
+	// The interviewer warns the candidate when these many minutes are left
+	INTERVIEW_TIME_WARNING_MINUTES = []uint{5, 1}
 )
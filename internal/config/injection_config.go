@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+// InjectionPolicy decides what happens when the candidate tries to inject instructions into the LLM
+type InjectionPolicy string
+
+const (
+	// The interviewer refuses to act on the content, and a question description is rejected
+	REFUSE_INJECTION_POLICY InjectionPolicy = "refuse"
+	// Same as refuse, but the interviewer also tells the candidate that the attempt is noted
+	WARN_INJECTION_POLICY InjectionPolicy = "warn"
+	// The interview goes on as usual with the content delimited, and the review is flagged
+	FLAG_INJECTION_POLICY InjectionPolicy = "flag"
+)
+
+const (
+	INJECTION_DEFAULT_POLICY InjectionPolicy = WARN_INJECTION_POLICY
+)
+
+type InjectionConfig struct {
+	Policy InjectionPolicy
+	// The heuristics always run, the classifier is an extra LLM call for the content that they let through
+	ClassifierEnabled bool
+	// Empty means the default model
+	ClassifierModel string
+}
+
+func LoadInjectionConfig() (*InjectionConfig, error) {
+	policy := InjectionPolicy(util.GetEnvOr(common.INJECTION_POLICY_KEY, string(INJECTION_DEFAULT_POLICY)))
+	classifierModel := util.GetEnvOr(common.INJECTION_CLASSIFIER_MODEL_KEY, "")
+
+	classifierEnabled, err := strconv.ParseBool(util.GetEnvOr(common.INJECTION_CLASSIFIER_ENABLED_KEY, "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid injection classifier enabled flag, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	switch policy {
+	case REFUSE_INJECTION_POLICY, WARN_INJECTION_POLICY, FLAG_INJECTION_POLICY:
+	default:
+		return nil, fmt.Errorf("invalid injection policy %s, expected one of refuse, warn or flag: %w", policy, common.ErrInternalServerError)
+	}
+
+	return &InjectionConfig{
+		Policy:            policy,
+		ClassifierEnabled: classifierEnabled,
+		ClassifierModel:   classifierModel,
+	}, nil
+}
@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"strconv"
+	"strings"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
@@ -11,6 +14,19 @@ const (
 	LLM_DEV_PROVIDER string = common.OLLAMA
 	LLM_DEV_MODEL    string = "gemma3:1b"
 	LLM_DEV_BASE_URL string = "http://localhost:11434"
+
+	LLM_DEFAULT_REVIEW_SAMPLE_COUNT uint = 1
+	LLM_MAX_REVIEW_SAMPLE_COUNT     uint = 7
+
+	// In tokens, used for the models that are not in the context budgets
+	LLM_DEFAULT_CONTEXT_BUDGET uint = 8192
+)
+
+var (
+	// The dev model is served by Ollama with a small context window by default
+	LLM_DEFAULT_CONTEXT_BUDGETS = map[string]uint{
+		LLM_DEV_MODEL: 2048,
+	}
 )
 
 type LLMConfig struct {
@@ -18,6 +34,33 @@ type LLMConfig struct {
 	Model    string
 	BaseURL  string
 	APIKey   string
+	// More than one sample turns on self-consistency reviews
+	ReviewSampleCount uint
+	ReviewModels      []string
+	// In tokens per model, the prompt of every request has to fit within it
+	ContextBudgets       map[string]uint
+	DefaultContextBudget uint
+}
+
+func (l *LLMConfig) GetContextBudget(llmModel string) uint {
+	if l == nil {
+		return LLM_DEFAULT_CONTEXT_BUDGET
+	}
+	if budget, ok := l.ContextBudgets[llmModel]; ok {
+		return budget
+	}
+	return l.DefaultContextBudget
+}
+
+// Falls back to the default model when no review models are configured
+func (l *LLMConfig) GetReviewModel(sampleIndex uint) string {
+	if l == nil {
+		return ""
+	}
+	if len(l.ReviewModels) == 0 {
+		return l.Model
+	}
+	return l.ReviewModels[sampleIndex%uint(len(l.ReviewModels))]
 }
 
 func LoadLLMConfig() (*LLMConfig, error) {
@@ -25,6 +68,31 @@ func LoadLLMConfig() (*LLMConfig, error) {
 	model := util.GetEnvOr(common.LLM_MODEL_KEY, LLM_DEV_MODEL)
 	baseURL := util.GetEnvOr(common.LLM_BASE_URL_KEY, LLM_DEV_BASE_URL)
 	apiKey := util.GetEnvOr(common.LLM_API_KEY, "")
+	reviewSampleCount := util.GetEnvUIntOr(common.LLM_REVIEW_SAMPLE_COUNT_KEY, LLM_DEFAULT_REVIEW_SAMPLE_COUNT)
+
+	reviewModels := make([]string, 0)
+	for _, reviewModel := range strings.Split(util.GetEnvOr(common.LLM_REVIEW_MODELS_KEY, ""), ",") {
+		if reviewModel = strings.TrimSpace(reviewModel); reviewModel != "" {
+			reviewModels = append(reviewModels, reviewModel)
+		}
+	}
+
+	defaultContextBudget := util.GetEnvUIntOr(common.LLM_DEFAULT_CONTEXT_BUDGET_KEY, LLM_DEFAULT_CONTEXT_BUDGET)
+
+	// Comma separated model=tokens pairs, they override the defaults
+	contextBudgets := make(map[string]uint)
+	maps.Copy(contextBudgets, LLM_DEFAULT_CONTEXT_BUDGETS)
+	for _, pair := range strings.Split(util.GetEnvOr(common.LLM_CONTEXT_BUDGETS_KEY, ""), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		budgetModel, budgetString, found := strings.Cut(pair, "=")
+		budget, err := strconv.ParseUint(strings.TrimSpace(budgetString), 10, 64)
+		if !found || err != nil {
+			return nil, fmt.Errorf("invalid llm context budget %s, expected model=tokens: %w", pair, common.ErrInternalServerError)
+		}
+		contextBudgets[strings.TrimSpace(budgetModel)] = uint(budget)
+	}
 
 	if provider == "" || model == "" || baseURL == "" {
 		return nil, fmt.Errorf("missing llm config, provider=%s model=%s baseURL=%s: %w", provider, model, baseURL, common.ErrInternalServerError)
@@ -34,10 +102,28 @@ func LoadLLMConfig() (*LLMConfig, error) {
 		return nil, fmt.Errorf("missing api key for provider=%s: %w", provider, common.ErrInternalServerError)
 	}
 
+	if reviewSampleCount == 0 || reviewSampleCount > LLM_MAX_REVIEW_SAMPLE_COUNT {
+		return nil, fmt.Errorf("review sample count must be between 1 and %d, got %d: %w", LLM_MAX_REVIEW_SAMPLE_COUNT, reviewSampleCount, common.ErrInternalServerError)
+	}
+
+	// Every prompt has to leave room for the reply within the budget
+	if defaultContextBudget <= uint(CONTEXT_WINDOW_REPLY_RESERVE) {
+		return nil, fmt.Errorf("default llm context budget must be greater than %d tokens, got %d: %w", CONTEXT_WINDOW_REPLY_RESERVE, defaultContextBudget, common.ErrInternalServerError)
+	}
+	for budgetModel, budget := range contextBudgets {
+		if budget <= uint(CONTEXT_WINDOW_REPLY_RESERVE) {
+			return nil, fmt.Errorf("llm context budget of %s must be greater than %d tokens, got %d: %w", budgetModel, CONTEXT_WINDOW_REPLY_RESERVE, budget, common.ErrInternalServerError)
+		}
+	}
+
 	return &LLMConfig{
-		Provider: provider,
-		Model:    model,
-		BaseURL:  baseURL,
-		APIKey:   apiKey,
+		Provider:             provider,
+		Model:                model,
+		BaseURL:              baseURL,
+		APIKey:               apiKey,
+		ReviewSampleCount:    reviewSampleCount,
+		ReviewModels:         reviewModels,
+		ContextBudgets:       contextBudgets,
+		DefaultContextBudget: defaultContextBudget,
 	}, nil
 }
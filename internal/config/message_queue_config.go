@@ -45,6 +45,7 @@ func LoadMessageQueueConfig() (*MessageQueueConfig, error) {
 	// Define the queues here
 	queues := []string{
 		common.REVIEW_QUEUE,
+		common.STUDY_PLAN_QUEUE,
 	}
 
 	return &MessageQueueConfig{
@@ -7,35 +7,71 @@ import (
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
 )
 
+const (
+	R2_OBJECT_STORAGE_PROVIDER string = "r2"
+	// The local and memory providers serve the files through the HTTP server with signed URLs
+	LOCAL_OBJECT_STORAGE_PROVIDER  string = "local"
+	MEMORY_OBJECT_STORAGE_PROVIDER string = "memory"
+)
+
 type ObjectStorageConfig struct {
+	Provider   string
 	BucketName string
 	SecretKey  string
 	AccessKey  string
 	Endpoint   string
 	Region     string
+	// Only used by the local provider
+	LocalDirectory string
+	// Sign the transcript audio URLs of every provider, and the file URLs of the local and memory providers
+	SigningSecret string
+	PublicBaseURL string
 }
 
 func LoadObjectStorageConfig() (*ObjectStorageConfig, error) {
+	provider := util.GetEnvOr(common.OBJECT_STORAGE_PROVIDER_KEY, R2_OBJECT_STORAGE_PROVIDER)
 	bucketName := util.GetEnvOr(common.OBJECT_STORAGE_BUCKET_KEY, "")
 	secretKey := util.GetEnvOr(common.OBJECT_STORAGE_SECRET_KEY, "")
 	accessKey := util.GetEnvOr(common.OBJECT_STORAGE_ACCESS_KEY, "")
 	endpoint := util.GetEnvOr(common.OBJECT_STORAGE_ENDPOINT_KEY, "")
 	region := util.GetEnvOr(common.OBJECT_STORAGE_REGION_KEY, "")
+	localDirectory := util.GetEnvOr(common.OBJECT_STORAGE_LOCAL_DIRECTORY_KEY, "tmp/object_storage")
+	signingSecret := util.GetEnvOr(common.OBJECT_STORAGE_SIGNING_SECRET_KEY, "")
+	publicBaseURL := util.GetEnvOr(common.OBJECT_STORAGE_PUBLIC_BASE_URL_KEY, "")
+
+	// The transcript audio is streamed through the HTTP server whatever the provider is
+	if signingSecret == "" || publicBaseURL == "" {
+		return nil, fmt.Errorf("missing object storage config for provider=%s, signingSecret and publicBaseURL are required: %w",
+			provider, common.ErrInternalServerError)
+	}
 
-	if bucketName == "" ||
-		secretKey == "" ||
-		accessKey == "" ||
-		endpoint == "" ||
-		region == "" {
-		return nil, fmt.Errorf("missing object storage config, secretKey=%s bucketName=%s accessKey=%s endpoint=%s region=%s: %w",
-			secretKey, bucketName, accessKey, endpoint, region, common.ErrInternalServerError)
+	switch provider {
+	case R2_OBJECT_STORAGE_PROVIDER:
+		if bucketName == "" ||
+			secretKey == "" ||
+			accessKey == "" ||
+			endpoint == "" ||
+			region == "" {
+			return nil, fmt.Errorf("missing object storage config, secretKey=%s bucketName=%s accessKey=%s endpoint=%s region=%s: %w",
+				secretKey, bucketName, accessKey, endpoint, region, common.ErrInternalServerError)
+		}
+	case LOCAL_OBJECT_STORAGE_PROVIDER, MEMORY_OBJECT_STORAGE_PROVIDER:
+		if bucketName == "" {
+			bucketName = provider
+		}
+	default:
+		return nil, fmt.Errorf("unsupported object storage provider %s: %w", provider, common.ErrInternalServerError)
 	}
 
 	return &ObjectStorageConfig{
-		BucketName: bucketName,
-		SecretKey:  secretKey,
-		AccessKey:  accessKey,
-		Endpoint:   endpoint,
-		Region:     region,
+		Provider:       provider,
+		BucketName:     bucketName,
+		SecretKey:      secretKey,
+		AccessKey:      accessKey,
+		Endpoint:       endpoint,
+		Region:         region,
+		LocalDirectory: localDirectory,
+		SigningSecret:  signingSecret,
+		PublicBaseURL:  publicBaseURL,
 	}, nil
 }
@@ -3,6 +3,7 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"runtime/debug"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
@@ -14,20 +15,23 @@ import (
 
 func NewReviewConsumer(
 	reviewService service.ReviewService,
+	studyPlanService service.StudyPlanService,
 	consumerRepo repo.MessageQueueConsumerRepo,
 	logger *zerolog.Logger,
 ) *ReviewConsumer {
 	return &ReviewConsumer{
-		reviewService: reviewService,
-		consumerRepo:  consumerRepo,
-		logger:        logger,
+		reviewService:    reviewService,
+		studyPlanService: studyPlanService,
+		consumerRepo:     consumerRepo,
+		logger:           logger,
 	}
 }
 
 type ReviewConsumer struct {
-	reviewService service.ReviewService
-	consumerRepo  repo.MessageQueueConsumerRepo
-	logger        *zerolog.Logger
+	reviewService    service.ReviewService
+	studyPlanService service.StudyPlanService
+	consumerRepo     repo.MessageQueueConsumerRepo
+	logger           *zerolog.Logger
 }
 
 func (r *ReviewConsumer) ConsumeAndProcess(ctx context.Context, workerCount uint) {
@@ -83,11 +87,28 @@ func (r *ReviewConsumer) processMessage(ctx context.Context, delivery *model.Del
 		return
 	}
 
-	if err := r.reviewService.ReviewInterviewPerformance(ctx, reviewMessage.InterviewID); err != nil {
+	review := r.reviewService.ReviewInterviewPerformance
+	if reviewMessage.ReReview {
+		review = r.reviewService.ReReviewInterviewPerformance
+	}
+
+	if err := review(ctx, reviewMessage.InterviewID); err != nil {
 		r.logger.Error().Err(err).Msg("unable to review interview performance")
-		delivery.Nack(true)
+		// The LLM has already been given chances to repair its output, requeuing will not help
+		requeue := !errors.Is(err, common.ErrMalformedLLMOutput)
+		delivery.Nack(requeue)
 		return
 	}
 
 	delivery.Ack()
+
+	// A re-review does not change the review that is displayed, so the study plan stays the same
+	if reviewMessage.ReReview {
+		return
+	}
+
+	// The review is already saved, failing to update the study plan should not get it reviewed again
+	if err := r.studyPlanService.EnqueueStudyPlanUpdate(ctx, reviewMessage.InterviewID); err != nil {
+		r.logger.Error().Err(err).Msg("unable to enqueue study plan update")
+	}
 }
@@ -0,0 +1,96 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime/debug"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/service"
+	"github.com/rs/zerolog"
+)
+
+func NewStudyPlanConsumer(
+	studyPlanService service.StudyPlanService,
+	consumerRepo repo.MessageQueueConsumerRepo,
+	logger *zerolog.Logger,
+) *StudyPlanConsumer {
+	return &StudyPlanConsumer{
+		studyPlanService: studyPlanService,
+		consumerRepo:     consumerRepo,
+		logger:           logger,
+	}
+}
+
+type StudyPlanConsumer struct {
+	studyPlanService service.StudyPlanService
+	consumerRepo     repo.MessageQueueConsumerRepo
+	logger           *zerolog.Logger
+}
+
+func (s *StudyPlanConsumer) ConsumeAndProcess(ctx context.Context, workerCount uint) {
+	deliveryChan, err := s.consumerRepo.StartConsuming(ctx, common.STUDY_PLAN_QUEUE)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := range workerCount {
+		go func() {
+			defer func() {
+				if err := recover(); err != nil {
+					stackTrace := debug.Stack()
+					s.logger.Error().
+						Interface("panic", err).
+						Bytes("stack_trace", stackTrace).
+						Uint("worker_number", i).
+						Msg("panic recovered in study plan consumer")
+				}
+			}()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delivery := <-deliveryChan:
+					s.processMessage(ctx, delivery)
+				}
+			}
+		}()
+	}
+}
+
+func (s *StudyPlanConsumer) processMessage(ctx context.Context, delivery *model.Delivery) {
+	defer func() {
+		if err := recover(); err != nil {
+			stackTrace := debug.Stack()
+			s.logger.Error().
+				Interface("panic", err).
+				Bytes("stack_trace", stackTrace).
+				Msg("panic recovered when consuming messages")
+
+			if err := delivery.Nack(true); err != nil {
+				s.logger.Error().Err(err).Msg("failed to nack message after panic")
+			}
+		}
+	}()
+
+	studyPlanMessage := &model.StudyPlanMessage{}
+	if err := json.Unmarshal(delivery.Body, studyPlanMessage); err != nil {
+		s.logger.Error().Err(err).Msg("unable to marshal study plan message")
+		delivery.Nack(true)
+		return
+	}
+
+	if err := s.studyPlanService.UpdateStudyPlan(ctx, studyPlanMessage.UserID); err != nil {
+		s.logger.Error().Err(err).Msg("unable to update study plan")
+		// The LLM has already been given chances to repair its output, requeuing will not help
+		requeue := !errors.Is(err, common.ErrMalformedLLMOutput)
+		delivery.Nack(requeue)
+		return
+	}
+
+	delivery.Ack()
+}
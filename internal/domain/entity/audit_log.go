@@ -0,0 +1,91 @@
+package entity
+
+type AuditAction string
+
+const (
+	OVERRIDE_REVIEW_ACTION       AuditAction = "override_review"
+	SELECT_REVIEW_VERSION_ACTION AuditAction = "select_review_version"
+	CREATE_ANNOTATION_ACTION     AuditAction = "create_annotation"
+	DELETE_ANNOTATION_ACTION     AuditAction = "delete_annotation"
+)
+
+type AuditEntityType string
+
+const (
+	REVIEW_AUDIT_ENTITY                AuditEntityType = "review"
+	TRANSCRIPT_ANNOTATION_AUDIT_ENTITY AuditEntityType = "transcript_annotation"
+)
+
+// AuditLog is append only, Before and After are snapshots of the fields that were changed
+type AuditLog struct {
+	Base
+	ActorUserID uint `gorm:"index"`
+	InterviewID uint `gorm:"index"`
+	Action      AuditAction
+	EntityType  AuditEntityType
+	EntityID    uint
+	Before      map[string]any `gorm:"serializer:json"`
+	After       map[string]any `gorm:"serializer:json"`
+	Reason      string
+}
+
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+func (a *AuditLog) SetActorUserID(actorUserID uint) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.ActorUserID = actorUserID
+	return a
+}
+
+func (a *AuditLog) SetInterviewID(interviewID uint) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.InterviewID = interviewID
+	return a
+}
+
+func (a *AuditLog) SetAction(action AuditAction) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.Action = action
+	return a
+}
+
+func (a *AuditLog) SetEntity(entityType AuditEntityType, entityID uint) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.EntityType = entityType
+	a.EntityID = entityID
+	return a
+}
+
+func (a *AuditLog) SetBefore(before map[string]any) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.Before = before
+	return a
+}
+
+func (a *AuditLog) SetAfter(after map[string]any) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.After = after
+	return a
+}
+
+func (a *AuditLog) SetReason(reason string) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.Reason = reason
+	return a
+}
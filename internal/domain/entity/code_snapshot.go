@@ -0,0 +1,33 @@
+package entity
+
+// CodeSnapshot is the candidate's code at a point in the interview, a new snapshot is only
+// created when the code has changed since the previous one
+type CodeSnapshot struct {
+	Base
+	InterviewID uint `gorm:"index"`
+	Code        string
+}
+
+func NewCodeSnapshot() *CodeSnapshot {
+	return &CodeSnapshot{}
+}
+
+func (c *CodeSnapshot) SetInterviewID(interviewID uint) *CodeSnapshot {
+	if c == nil {
+		return nil
+	}
+	c.InterviewID = interviewID
+	return c
+}
+
+func (c *CodeSnapshot) SetCode(code string) *CodeSnapshot {
+	if c == nil {
+		return nil
+	}
+	c.Code = code
+	return c
+}
+
+func (c *CodeSnapshot) Exists() bool {
+	return c != nil
+}
@@ -0,0 +1,49 @@
+package entity
+
+// Experiment splits the new interviews across its variants, only the latest active experiment assigns variants
+type Experiment struct {
+	Base
+	Name        string `gorm:"uniqueIndex"`
+	Description string
+	Active      bool
+}
+
+func NewExperiment() *Experiment {
+	return &Experiment{}
+}
+
+func (e *Experiment) SetName(name string) *Experiment {
+	if e == nil {
+		return nil
+	}
+	e.Name = name
+	return e
+}
+
+func (e *Experiment) SetDescription(description string) *Experiment {
+	if e == nil {
+		return nil
+	}
+	e.Description = description
+	return e
+}
+
+func (e *Experiment) Activate() *Experiment {
+	if e == nil {
+		return nil
+	}
+	e.Active = true
+	return e
+}
+
+func (e *Experiment) Deactivate() *Experiment {
+	if e == nil {
+		return nil
+	}
+	e.Active = false
+	return e
+}
+
+func (e *Experiment) Exists() bool {
+	return e != nil
+}
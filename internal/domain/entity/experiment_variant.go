@@ -0,0 +1,93 @@
+package entity
+
+// ExperimentVariant changes how the interviewer behaves, the fields that are left empty keep the defaults
+type ExperimentVariant struct {
+	Base
+	ExperimentID uint `gorm:"index"`
+	Name         string
+	// Relative to the other variants of the same experiment
+	Weight uint
+	// The version of the interviewer prompt templates, the templates without this version keep their default
+	PromptVersion string
+	// The LLM model of the interviewer replies
+	Model string
+	// Out of 100, the interviewer only replies when the classifier is more confident than this
+	ClassifierThreshold *float64
+}
+
+func NewExperimentVariant() *ExperimentVariant {
+	return &ExperimentVariant{}
+}
+
+func (e *ExperimentVariant) SetExperimentID(experimentID uint) *ExperimentVariant {
+	if e == nil {
+		return nil
+	}
+	e.ExperimentID = experimentID
+	return e
+}
+
+func (e *ExperimentVariant) SetName(name string) *ExperimentVariant {
+	if e == nil {
+		return nil
+	}
+	e.Name = name
+	return e
+}
+
+func (e *ExperimentVariant) SetWeight(weight uint) *ExperimentVariant {
+	if e == nil {
+		return nil
+	}
+	e.Weight = weight
+	return e
+}
+
+func (e *ExperimentVariant) SetPromptVersion(promptVersion string) *ExperimentVariant {
+	if e == nil {
+		return nil
+	}
+	e.PromptVersion = promptVersion
+	return e
+}
+
+func (e *ExperimentVariant) SetModel(model string) *ExperimentVariant {
+	if e == nil {
+		return nil
+	}
+	e.Model = model
+	return e
+}
+
+func (e *ExperimentVariant) SetClassifierThreshold(classifierThreshold *float64) *ExperimentVariant {
+	if e == nil {
+		return nil
+	}
+	e.ClassifierThreshold = classifierThreshold
+	return e
+}
+
+func (e *ExperimentVariant) GetPromptVersion() string {
+	if e == nil {
+		return ""
+	}
+	return e.PromptVersion
+}
+
+func (e *ExperimentVariant) GetModel() string {
+	if e == nil {
+		return ""
+	}
+	return e.Model
+}
+
+func (e *ExperimentVariant) GetClassifierThreshold(defaultThreshold float64) float64 {
+	if e == nil || e.ClassifierThreshold == nil {
+		return defaultThreshold
+	}
+	return *e.ClassifierThreshold
+}
+
+func (e *ExperimentVariant) Exists() bool {
+	return e != nil
+}
@@ -0,0 +1,79 @@
+package entity
+
+type FeedbackCategory string
+
+const (
+	STRENGTH_FEEDBACK    FeedbackCategory = "strength"
+	IMPROVEMENT_FEEDBACK FeedbackCategory = "improvement"
+)
+
+var FEEDBACK_CATEGORIES = []FeedbackCategory{
+	STRENGTH_FEEDBACK,
+	IMPROVEMENT_FEEDBACK,
+}
+
+type EvidenceType string
+
+const (
+	TRANSCRIPT_EVIDENCE    EvidenceType = "transcript"
+	CODE_SNAPSHOT_EVIDENCE EvidenceType = "code_snapshot"
+)
+
+// FeedbackEvidence points at a transcript turn or a code snapshot by its UUID,
+// the offset is from the start of the interview so that the replay can seek to it
+type FeedbackEvidence struct {
+	Type        EvidenceType `json:"type"`
+	ID          string       `json:"id"`
+	TimestampMS int64        `json:"timestamp_ms"`
+	OffsetMS    int64        `json:"offset_ms"`
+}
+
+type FeedbackItem struct {
+	Base
+	ReviewID uint `gorm:"index"`
+	Category FeedbackCategory
+	Summary  string
+	Evidence []*FeedbackEvidence `gorm:"serializer:json"`
+}
+
+func NewFeedbackItem() *FeedbackItem {
+	return &FeedbackItem{
+		Evidence: make([]*FeedbackEvidence, 0),
+	}
+}
+
+func (f *FeedbackItem) SetReviewID(reviewID uint) *FeedbackItem {
+	if f == nil {
+		return nil
+	}
+	f.ReviewID = reviewID
+	return f
+}
+
+func (f *FeedbackItem) SetCategory(category FeedbackCategory) *FeedbackItem {
+	if f == nil {
+		return nil
+	}
+	f.Category = category
+	return f
+}
+
+func (f *FeedbackItem) SetSummary(summary string) *FeedbackItem {
+	if f == nil {
+		return nil
+	}
+	f.Summary = summary
+	return f
+}
+
+func (f *FeedbackItem) AddEvidence(evidence *FeedbackEvidence) *FeedbackItem {
+	if f == nil || evidence == nil {
+		return f
+	}
+	f.Evidence = append(f.Evidence, evidence)
+	return f
+}
+
+func (f *FeedbackItem) Exists() bool {
+	return f != nil
+}
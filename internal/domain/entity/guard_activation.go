@@ -0,0 +1,137 @@
+package entity
+
+// GuardType is the kind of check that was tripped
+type GuardType string
+
+const (
+	SOLUTION_LEAK_GUARD    GuardType = "solution_leak"
+	PROMPT_INJECTION_GUARD GuardType = "prompt_injection"
+)
+
+// GuardSource is where the content that was checked came from
+type GuardSource string
+
+const (
+	INTERVIEWER_REPLY_GUARD_SOURCE    GuardSource = "interviewer_reply"
+	CANDIDATE_SPEECH_GUARD_SOURCE     GuardSource = "candidate_speech"
+	QUESTION_DESCRIPTION_GUARD_SOURCE GuardSource = "question_description"
+)
+
+type GuardReason string
+
+const (
+	CODE_BLOCK_GUARD_REASON        GuardReason = "code_block"
+	ALGORITHM_GUARD_REASON         GuardReason = "algorithm"
+	REFERENCE_OVERLAP_GUARD_REASON GuardReason = "reference_overlap"
+	HEURISTIC_GUARD_REASON         GuardReason = "heuristic"
+	CLASSIFIER_GUARD_REASON        GuardReason = "classifier"
+)
+
+// GuardAction is what was done with the content that tripped the guard
+type GuardAction string
+
+const (
+	REGENERATED_GUARD_ACTION GuardAction = "regenerated"
+	SOFTENED_GUARD_ACTION    GuardAction = "softened"
+	REFUSED_GUARD_ACTION     GuardAction = "refused"
+	WARNED_GUARD_ACTION      GuardAction = "warned"
+	FLAGGED_GUARD_ACTION     GuardAction = "flagged"
+)
+
+// GuardActivation is kept for analysis every time a guard trips
+type GuardActivation struct {
+	Base
+	// 0 when the interview was never set up, such as for a rejected question description
+	InterviewID uint      `gorm:"index"`
+	Type        GuardType `gorm:"index"`
+	Source      GuardSource
+	// The reason of the first attempt that tripped the guard
+	Reason GuardReason
+	Action GuardAction
+	// Only set by the checks that have a score, from 0 to 1
+	Score *float64
+	// The number of replies that were generated, including the first one
+	Attempts        uint
+	OriginalContent string
+	FinalContent    string
+}
+
+func NewGuardActivation() *GuardActivation {
+	return &GuardActivation{}
+}
+
+func (g *GuardActivation) SetInterviewID(interviewID uint) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.InterviewID = interviewID
+	return g
+}
+
+func (g *GuardActivation) SetType(guardType GuardType) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.Type = guardType
+	return g
+}
+
+func (g *GuardActivation) SetSource(source GuardSource) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.Source = source
+	return g
+}
+
+func (g *GuardActivation) SetReason(reason GuardReason) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.Reason = reason
+	return g
+}
+
+func (g *GuardActivation) SetAction(action GuardAction) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.Action = action
+	return g
+}
+
+func (g *GuardActivation) SetScore(score float64) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.Score = &score
+	return g
+}
+
+func (g *GuardActivation) SetAttempts(attempts uint) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.Attempts = attempts
+	return g
+}
+
+func (g *GuardActivation) SetOriginalContent(originalContent string) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.OriginalContent = originalContent
+	return g
+}
+
+func (g *GuardActivation) SetFinalContent(finalContent string) *GuardActivation {
+	if g == nil {
+		return nil
+	}
+	g.FinalContent = finalContent
+	return g
+}
+
+func (g *GuardActivation) Exists() bool {
+	return g != nil
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+// IntentSample is a candidate sentence that went through intent classification,
+// it is kept so that it can be labeled and fed back into the training set
+type IntentSample struct {
+	Base
+	InterviewID         uint `gorm:"index"`
+	Content             string
+	PredictedIntent     model.Intent
+	Confidence          float64
+	LabeledIntent       *model.Intent
+	LabelerUserID       *uint
+	LabeledTimestampMS  *int64
+	ExportedTimestampMS *int64
+}
+
+func NewIntentSample() *IntentSample {
+	return &IntentSample{}
+}
+
+func (i *IntentSample) SetInterviewID(interviewID uint) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.InterviewID = interviewID
+	return i
+}
+
+func (i *IntentSample) SetContent(content string) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.Content = content
+	return i
+}
+
+func (i *IntentSample) SetPredictedIntent(intent model.Intent) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.PredictedIntent = intent
+	return i
+}
+
+// Confidence is out of 100, same as the threshold used when handling the intent
+func (i *IntentSample) SetConfidence(confidence float64) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.Confidence = confidence
+	return i
+}
+
+// Labeling with the predicted intent is a confirmation, otherwise it is a relabel
+func (i *IntentSample) Label(intent model.Intent, labelerUserID uint) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.LabeledIntent = util.ToPtr(intent)
+	i.LabelerUserID = util.ToPtr(labelerUserID)
+	i.LabeledTimestampMS = util.ToPtr(time.Now().UnixMilli())
+	return i
+}
+
+func (i *IntentSample) MarkExported() *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.ExportedTimestampMS = util.ToPtr(time.Now().UnixMilli())
+	return i
+}
+
+func (i *IntentSample) IsLabeled() bool {
+	if i == nil {
+		return false
+	}
+	return i.LabeledIntent != nil
+}
+
+func (i *IntentSample) GetLabeledIntent() model.Intent {
+	if i == nil {
+		return ""
+	}
+	return util.FromPtr(i.LabeledIntent)
+}
+
+func (i *IntentSample) Exists() bool {
+	return i != nil
+}
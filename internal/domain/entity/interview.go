@@ -6,10 +6,32 @@ import (
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
 )
 
+type InterviewType string
+
+const (
+	CODING_INTERVIEW_TYPE InterviewType = "coding"
+)
+
+// InterviewMode decides how the candidate and the interviewer talk to each other
+type InterviewMode string
+
+const (
+	VOICE_INTERVIEW_MODE InterviewMode = "voice"
+	// The candidate types the messages and the interviewer replies without audio
+	TEXT_INTERVIEW_MODE InterviewMode = "text"
+)
+
+func IsValidInterviewMode(mode InterviewMode) bool {
+	return mode == VOICE_INTERVIEW_MODE || mode == TEXT_INTERVIEW_MODE
+}
+
 type Interview struct {
 	Base
 	UserID               uint
 	QuestionID           uint
+	Type                 InterviewType `gorm:"default:coding"`
+	Mode                 InterviewMode `gorm:"default:voice"`
+	ExperimentVariantID  *uint         `gorm:"index"`
 	Code                 string
 	StartTimestampMS     *int64
 	ReviewID             *uint
@@ -41,6 +63,34 @@ func (i *Interview) GetTimeRemainingS() uint {
 	return i.AllocatedDurationS - i.ElapsedTimeS - uint(util.MillisToSeconds(time.Now().UnixMilli()-i.UpdateTimestampMS))
 }
 
+// Interviews that were created before the types were added are coding interviews
+func (i *Interview) GetType() InterviewType {
+	if i == nil || i.Type == "" {
+		return CODING_INTERVIEW_TYPE
+	}
+	return i.Type
+}
+
+// Interviews that were created before the modes were added are voice interviews
+func (i *Interview) GetMode() InterviewMode {
+	if i == nil || i.Mode == "" {
+		return VOICE_INTERVIEW_MODE
+	}
+	return i.Mode
+}
+
+func (i *Interview) IsTextMode() bool {
+	return i.GetMode() == TEXT_INTERVIEW_MODE
+}
+
+func (i *Interview) SetMode(mode InterviewMode) *Interview {
+	if i == nil {
+		return nil
+	}
+	i.Mode = mode
+	return i
+}
+
 func (i *Interview) TimesUp() bool {
 	if i == nil {
 		return true
@@ -57,6 +107,14 @@ func (i *Interview) SetAllocatedDurationS(durationSeconds uint) *Interview {
 	return i
 }
 
+func (i *Interview) SetExperimentVariant(experimentVariant *ExperimentVariant) *Interview {
+	if i == nil || !experimentVariant.Exists() {
+		return i
+	}
+	i.ExperimentVariantID = util.ToPtr(experimentVariant.ID)
+	return i
+}
+
 func (i *Interview) IncrementSetupCount() *Interview {
 	if i == nil {
 		return nil
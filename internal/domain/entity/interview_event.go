@@ -0,0 +1,42 @@
+package entity
+
+type InterviewEventType string
+
+const (
+	START_INTERVIEW_EVENT   InterviewEventType = "start"
+	PAUSE_INTERVIEW_EVENT   InterviewEventType = "pause"
+	RESUME_INTERVIEW_EVENT  InterviewEventType = "resume"
+	END_INTERVIEW_EVENT     InterviewEventType = "end"
+	ABANDON_INTERVIEW_EVENT InterviewEventType = "abandon"
+)
+
+// InterviewEvent records the lifecycle changes of an interview so that it can be replayed
+type InterviewEvent struct {
+	Base
+	InterviewID uint `gorm:"index"`
+	Type        InterviewEventType
+}
+
+func NewInterviewEvent() *InterviewEvent {
+	return &InterviewEvent{}
+}
+
+func (i *InterviewEvent) SetInterviewID(interviewID uint) *InterviewEvent {
+	if i == nil {
+		return nil
+	}
+	i.InterviewID = interviewID
+	return i
+}
+
+func (i *InterviewEvent) SetType(eventType InterviewEventType) *InterviewEvent {
+	if i == nil {
+		return nil
+	}
+	i.Type = eventType
+	return i
+}
+
+func (i *InterviewEvent) Exists() bool {
+	return i != nil
+}
@@ -0,0 +1,76 @@
+package entity
+
+// PromptTemplate overrides the embedded template of the same name, the most specific active one is used
+type PromptTemplate struct {
+	Base
+	Name    string `gorm:"index"`
+	Version string
+	// A text/template with model.PromptVariables as its data
+	Content string
+	// Nil applies to every question
+	QuestionID *uint
+	// Nil applies to every interview type
+	InterviewType *InterviewType
+	Active        bool
+}
+
+func NewPromptTemplate() *PromptTemplate {
+	return &PromptTemplate{}
+}
+
+func (p *PromptTemplate) SetName(name string) *PromptTemplate {
+	if p == nil {
+		return nil
+	}
+	p.Name = name
+	return p
+}
+
+func (p *PromptTemplate) SetVersion(version string) *PromptTemplate {
+	if p == nil {
+		return nil
+	}
+	p.Version = version
+	return p
+}
+
+func (p *PromptTemplate) SetContent(content string) *PromptTemplate {
+	if p == nil {
+		return nil
+	}
+	p.Content = content
+	return p
+}
+
+// Higher is more specific, an override for a question wins over one for an interview type
+func (p *PromptTemplate) GetSpecificity() int {
+	if p == nil {
+		return -1
+	}
+	specificity := 0
+	if p.QuestionID != nil {
+		specificity += 2
+	}
+	if p.InterviewType != nil {
+		specificity += 1
+	}
+	return specificity
+}
+
+// Matches when every scope that is set on the template is the same as the one given
+func (p *PromptTemplate) Matches(questionID uint, interviewType InterviewType) bool {
+	if p == nil {
+		return false
+	}
+	if p.QuestionID != nil && *p.QuestionID != questionID {
+		return false
+	}
+	if p.InterviewType != nil && *p.InterviewType != interviewType {
+		return false
+	}
+	return true
+}
+
+func (p *PromptTemplate) Exists() bool {
+	return p != nil
+}
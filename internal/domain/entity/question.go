@@ -4,6 +4,8 @@ type Question struct {
 	Base
 	ExternalID  string `gorm:"index"`
 	Description string
+	// Private, it is only compared against the replies of the interviewer and never sent to the LLM or the candidate
+	ReferenceSolution string
 }
 
 func NewQuestion() *Question {
@@ -14,7 +16,7 @@ func (q *Question) SetExternalID(externalID string) *Question {
 	if q == nil {
 		return nil
 	}
-	q.SetExternalID(externalID)
+	q.ExternalID = externalID
 	return q
 }
 
@@ -22,10 +24,26 @@ func (q *Question) SetDescription(description string) *Question {
 	if q == nil {
 		return nil
 	}
-	q.SetDescription(description)
+	q.Description = description
 	return q
 }
 
+func (q *Question) SetReferenceSolution(referenceSolution string) *Question {
+	if q == nil {
+		return nil
+	}
+	q.ReferenceSolution = referenceSolution
+	return q
+}
+
+// Nil safe so that the callers do not have to check whether the question was found
+func (q *Question) GetReferenceSolution() string {
+	if q == nil {
+		return ""
+	}
+	return q.ReferenceSolution
+}
+
 func (q *Question) Exists() bool {
 	return q != nil
 }
@@ -1,16 +1,173 @@
 package entity
 
+import (
+	"math"
+	"time"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type ReviewSource string
+
+const (
+	AI_REVIEW_SOURCE    ReviewSource = "ai"
+	HUMAN_REVIEW_SOURCE ReviewSource = "human"
+)
+
+// ReviewStatus is derived from the review, it is not stored
+type ReviewStatus string
+
+const (
+	PENDING_REVIEW_STATUS  ReviewStatus = "pending"
+	REVIEWED_REVIEW_STATUS ReviewStatus = "reviewed"
+	// The LLM kept producing malformed reviews and nothing retries it, an admin has to re-review the interview and
+	// display the new version, or a reviewer has to override the review
+	FAILED_REVIEW_STATUS ReviewStatus = "failed"
+)
+
+type ReviewConfidence string
+
+const (
+	HIGH_REVIEW_CONFIDENCE   ReviewConfidence = "high"
+	MEDIUM_REVIEW_CONFIDENCE ReviewConfidence = "medium"
+	LOW_REVIEW_CONFIDENCE    ReviewConfidence = "low"
+)
+
+// Standard deviations of the sampled scores, out of 100
+const (
+	HIGH_REVIEW_CONFIDENCE_MAX_STD_DEV   float64 = 5
+	MEDIUM_REVIEW_CONFIDENCE_MAX_STD_DEV float64 = 12
+)
+
+// An interview can have several versions of its review, Interview.ReviewID points at the version shown to the candidate
 type Review struct {
 	Base
-	Score    uint
-	Passed   bool
-	Feedback string
+	InterviewID   uint `gorm:"index"`
+	Version       uint
+	Source        ReviewSource `gorm:"default:ai"`
+	Model         string
+	PromptVersion string
+	// Only set for human reviews, which are overrides of BaseReviewID
+	ReviewerUserID *uint
+	BaseReviewID   *uint
+	Justification  string
+	// Nil until the interview has been reviewed
+	ReviewedTimestampMS *int64
+	Score               uint
+	Passed              bool
+	Feedback            string
+	// Variance of the scores across the independent review samples, only meaningful when SampleCount > 1
+	ScoreVariance float64
+	SampleCount   uint
+	// Whether the interview had a prompt injection that the policy let through, the score deserves a closer look
+	Flagged bool
+	// Set when the review could not be completed, it is cleared once a later attempt succeeds
+	Failed bool
 }
 
 func NewReview() *Review {
 	return &Review{}
 }
 
+func (r *Review) SetInterviewID(interviewID uint) *Review {
+	if r == nil {
+		return nil
+	}
+	r.InterviewID = interviewID
+	return r
+}
+
+func (r *Review) SetVersion(version uint) *Review {
+	if r == nil {
+		return nil
+	}
+	r.Version = version
+	return r
+}
+
+func (r *Review) SetSource(source ReviewSource) *Review {
+	if r == nil {
+		return nil
+	}
+	r.Source = source
+	return r
+}
+
+// Override turns the review into a human override of the base review
+func (r *Review) Override(baseReview *Review, reviewerUserID uint, justification string) *Review {
+	if r == nil || baseReview == nil {
+		return nil
+	}
+	r.Source = HUMAN_REVIEW_SOURCE
+	r.BaseReviewID = util.ToPtr(baseReview.ID)
+	r.ReviewerUserID = util.ToPtr(reviewerUserID)
+	r.Justification = justification
+	r.InterviewID = baseReview.InterviewID
+	r.Score = baseReview.Score
+	r.Passed = baseReview.Passed
+	r.Feedback = baseReview.Feedback
+	return r
+}
+
+func (r *Review) IsHumanReview() bool {
+	if r == nil {
+		return false
+	}
+	return r.Source == HUMAN_REVIEW_SOURCE
+}
+
+func (r *Review) SetModel(model string) *Review {
+	if r == nil {
+		return nil
+	}
+	r.Model = model
+	return r
+}
+
+func (r *Review) SetPromptVersion(promptVersion string) *Review {
+	if r == nil {
+		return nil
+	}
+	r.PromptVersion = promptVersion
+	return r
+}
+
+func (r *Review) MarkReviewed() *Review {
+	if r == nil {
+		return nil
+	}
+	r.ReviewedTimestampMS = util.ToPtr(time.Now().UnixMilli())
+	r.Failed = false
+	return r
+}
+
+func (r *Review) MarkFailed() *Review {
+	if r == nil {
+		return nil
+	}
+	r.Failed = true
+	r.Feedback = "The interview could not be reviewed automatically, it is waiting for a reviewer."
+	return r
+}
+
+func (r *Review) GetStatus() ReviewStatus {
+	switch {
+	case r.IsReviewed():
+		return REVIEWED_REVIEW_STATUS
+	case r != nil && r.Failed:
+		return FAILED_REVIEW_STATUS
+	default:
+		return PENDING_REVIEW_STATUS
+	}
+}
+
+func (r *Review) IsReviewed() bool {
+	if r == nil {
+		return false
+	}
+	return r.ReviewedTimestampMS != nil
+}
+
 func (r *Review) SetScore(score uint) *Review {
 	if r == nil {
 		return nil
@@ -35,6 +192,49 @@ func (r *Review) SetFeedback(feedback string) *Review {
 	return r
 }
 
+func (r *Review) SetScoreVariance(scoreVariance float64) *Review {
+	if r == nil {
+		return nil
+	}
+	r.ScoreVariance = scoreVariance
+	return r
+}
+
+func (r *Review) SetSampleCount(sampleCount uint) *Review {
+	if r == nil {
+		return nil
+	}
+	r.SampleCount = sampleCount
+	return r
+}
+
+func (r *Review) SetFlagged(flagged bool) *Review {
+	if r == nil {
+		return nil
+	}
+	r.Flagged = flagged
+	return r
+}
+
+func (r *Review) IsSelfConsistent() bool {
+	if r == nil {
+		return false
+	}
+	return r.SampleCount > 1
+}
+
+func (r *Review) GetConfidence() ReviewConfidence {
+	stdDev := math.Sqrt(r.ScoreVariance)
+	switch {
+	case stdDev <= HIGH_REVIEW_CONFIDENCE_MAX_STD_DEV:
+		return HIGH_REVIEW_CONFIDENCE
+	case stdDev <= MEDIUM_REVIEW_CONFIDENCE_MAX_STD_DEV:
+		return MEDIUM_REVIEW_CONFIDENCE
+	default:
+		return LOW_REVIEW_CONFIDENCE
+	}
+}
+
 func (r *Review) Exists() bool {
 	return r != nil
 }
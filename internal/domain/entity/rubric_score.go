@@ -0,0 +1,82 @@
+package entity
+
+type RubricDimension string
+
+const (
+	PROBLEM_SOLVING     RubricDimension = "problem_solving"
+	COMMUNICATION       RubricDimension = "communication"
+	CODE_CORRECTNESS    RubricDimension = "code_correctness"
+	COMPLEXITY_ANALYSIS RubricDimension = "complexity_analysis"
+	TESTING             RubricDimension = "testing"
+	HANDLING_HINTS      RubricDimension = "handling_hints"
+
+	RUBRIC_MAX_SCORE uint = 10
+)
+
+// The order here is the order the rubric is displayed to the candidate
+var RUBRIC_DIMENSIONS = []RubricDimension{
+	PROBLEM_SOLVING,
+	COMMUNICATION,
+	CODE_CORRECTNESS,
+	COMPLEXITY_ANALYSIS,
+	TESTING,
+	HANDLING_HINTS,
+}
+
+func (r RubricDimension) IsValid() bool {
+	for _, dimension := range RUBRIC_DIMENSIONS {
+		if r == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+type RubricScore struct {
+	Base
+	ReviewID      uint `gorm:"index"`
+	Dimension     RubricDimension
+	Score         uint
+	Justification string
+}
+
+func NewRubricScore() *RubricScore {
+	return &RubricScore{}
+}
+
+func (r *RubricScore) SetReviewID(reviewID uint) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.ReviewID = reviewID
+	return r
+}
+
+func (r *RubricScore) SetDimension(dimension RubricDimension) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.Dimension = dimension
+	return r
+}
+
+// Score is capped at RUBRIC_MAX_SCORE
+func (r *RubricScore) SetScore(score uint) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.Score = min(score, RUBRIC_MAX_SCORE)
+	return r
+}
+
+func (r *RubricScore) SetJustification(justification string) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.Justification = justification
+	return r
+}
+
+func (r *RubricScore) Exists() bool {
+	return r != nil
+}
@@ -0,0 +1,103 @@
+package entity
+
+type StudyPlanTopic struct {
+	Dimension    RubricDimension `json:"dimension"`
+	AverageScore float64         `json:"average_score"`
+	Reason       string          `json:"reason"`
+}
+
+type StudyPlanQuestion struct {
+	QuestionID uint   `json:"question_id"`
+	ExternalID string `json:"external_id"`
+	Reason     string `json:"reason"`
+}
+
+// StudyPlan is regenerated from the latest reviewed interviews of the user, there is only one per user
+type StudyPlan struct {
+	Base
+	UserID               uint `gorm:"uniqueIndex"`
+	Summary              string
+	WeakTopics           []*StudyPlanTopic    `gorm:"serializer:json"`
+	RecommendedQuestions []*StudyPlanQuestion `gorm:"serializer:json"`
+	Exercises            []string             `gorm:"serializer:json"`
+	Model                string
+	PromptVersion        string
+	// The number of reviewed interviews that the plan is based on
+	ReviewCount uint
+}
+
+func NewStudyPlan() *StudyPlan {
+	return &StudyPlan{
+		WeakTopics:           make([]*StudyPlanTopic, 0),
+		RecommendedQuestions: make([]*StudyPlanQuestion, 0),
+		Exercises:            make([]string, 0),
+	}
+}
+
+func (s *StudyPlan) SetUserID(userID uint) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.UserID = userID
+	return s
+}
+
+func (s *StudyPlan) SetSummary(summary string) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.Summary = summary
+	return s
+}
+
+func (s *StudyPlan) SetWeakTopics(weakTopics []*StudyPlanTopic) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.WeakTopics = weakTopics
+	return s
+}
+
+func (s *StudyPlan) SetRecommendedQuestions(recommendedQuestions []*StudyPlanQuestion) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.RecommendedQuestions = recommendedQuestions
+	return s
+}
+
+func (s *StudyPlan) SetExercises(exercises []string) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.Exercises = exercises
+	return s
+}
+
+func (s *StudyPlan) SetModel(model string) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.Model = model
+	return s
+}
+
+func (s *StudyPlan) SetPromptVersion(promptVersion string) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.PromptVersion = promptVersion
+	return s
+}
+
+func (s *StudyPlan) SetReviewCount(reviewCount uint) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.ReviewCount = reviewCount
+	return s
+}
+
+func (s *StudyPlan) Exists() bool {
+	return s != nil
+}
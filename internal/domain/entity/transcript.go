@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"fmt"
+
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
 )
@@ -11,6 +13,8 @@ const (
 	SYSTEM    Role = "system"
 	USER      Role = "user"
 	ASSISTANT Role = "assistant"
+	// A rolling summary of the turns before it, it stands in for them in the LLM context
+	SUMMARY Role = "summary"
 )
 
 type Intent string
@@ -28,16 +32,61 @@ type Transcript struct {
 	Content     string
 	InterviewID uint
 	Intent      Intent
-	URL         *string
+	// Out of 100, only set on candidate transcripts that were classified
+	IntentConfidence float64
+	// The audio of interviewer transcripts, a URL has to be presigned from it on every read
+	ObjectBucket *string
+	ObjectKey    *string
+	// Deprecated: this is a presigned URL that has already expired, it is only kept for transcripts
+	// that have not been migrated to ObjectKey yet
+	URL *string
+	// The template that the content was generated from, empty for the candidate and canned phrases
+	PromptName    string
+	PromptVersion string
+	// Only set on summary transcripts, the ID of the last turn that the summary covers
+	SummarizedUpToID *uint
+	// Candidate transcripts that tried to inject instructions are kept for the review but never sent to the interviewer
+	ExcludedFromContext bool
 }
 
 func (t *Transcript) ToLLMMessage() *model.LLMMessage {
+	if t.Role == SUMMARY {
+		return &model.LLMMessage{
+			Role:    model.SYSTEM,
+			Content: "Summary of the earlier part of the interview:\n" + t.Content,
+		}
+	}
+
+	// Whatever the candidate says is data for the interviewer, never instructions
+	if t.Role == USER {
+		return &model.LLMMessage{
+			Role:    model.USER,
+			Content: model.DelimitUntrustedContent(model.CANDIDATE_SPEECH_TAG, t.Content),
+		}
+	}
+
 	return &model.LLMMessage{
 		Role:    model.LLMRole(t.Role),
 		Content: t.Content,
 	}
 }
 
+// Only the candidate and the interviewer take turns, system prompts and summaries are not part of the conversation
+func (t *Transcript) IsConversationTurn() bool {
+	if t == nil {
+		return false
+	}
+	return t.Role == USER || t.Role == ASSISTANT
+}
+
+func (t *Transcript) SetExcludedFromContext(excludedFromContext bool) *Transcript {
+	if t == nil {
+		return nil
+	}
+	t.ExcludedFromContext = excludedFromContext
+	return t
+}
+
 func NewTranscript() *Transcript {
 	return &Transcript{}
 }
@@ -49,6 +98,13 @@ func NewInterviewerTranscript() *Transcript {
 	return transcript
 }
 
+func NewSummaryTranscript() *Transcript {
+	transcript := NewTranscript()
+	transcript.SetRole(SUMMARY)
+
+	return transcript
+}
+
 func NewCandidateTranscript() *Transcript {
 	transcript := NewTranscript()
 	transcript.SetRole(USER)
@@ -80,10 +136,66 @@ func (t *Transcript) SetInterviewID(interviewID uint) *Transcript {
 	return t
 }
 
-func (t *Transcript) SetURL(url string) *Transcript {
+func (t *Transcript) SetIntent(intent Intent, confidence float64) *Transcript {
+	if t == nil {
+		return nil
+	}
+	t.Intent = intent
+	t.IntentConfidence = confidence
+	return t
+}
+
+func (t *Transcript) SetPrompt(prompt *model.RenderedPrompt) *Transcript {
+	if t == nil || !prompt.Exists() {
+		return t
+	}
+	t.PromptName = string(prompt.Name)
+	t.PromptVersion = prompt.Version
+	return t
+}
+
+func (t *Transcript) SetSummarizedUpToID(transcriptID uint) *Transcript {
 	if t == nil {
 		return nil
 	}
-	t.URL = util.ToPtr(url)
+	t.SummarizedUpToID = util.ToPtr(transcriptID)
 	return t
 }
+
+func (t *Transcript) SetAudioLocation(location *model.FileLocation) *Transcript {
+	if t == nil || !location.Exists() {
+		return t
+	}
+	t.ObjectBucket = util.ToPtr(location.Bucket)
+	t.ObjectKey = util.ToPtr(location.Key)
+	return t
+}
+
+// Returns nil when there is no audio or the transcript has not been migrated yet
+func (t *Transcript) GetAudioLocation() *model.FileLocation {
+	if t == nil || t.ObjectKey == nil {
+		return nil
+	}
+	return model.NewFileLocation().
+		SetBucket(util.FromPtr(t.ObjectBucket)).
+		SetKey(util.FromPtr(t.ObjectKey))
+}
+
+// The payload is prefixed so that a signature of a transcript can never pass as the signature of a file
+func (t *Transcript) GetAudioSigningPayload(expiresAtS int64) string {
+	if t == nil {
+		return ""
+	}
+	return fmt.Sprintf("transcript\n%s\n%d", t.UUID, expiresAtS)
+}
+
+func (t *Transcript) Exists() bool {
+	return t != nil
+}
+
+func (t *Transcript) HasLegacyURL() bool {
+	if t == nil {
+		return false
+	}
+	return t.URL != nil && t.ObjectKey == nil
+}
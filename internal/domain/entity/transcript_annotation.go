@@ -0,0 +1,50 @@
+package entity
+
+// TranscriptAnnotation is an inline comment from a reviewer on a single transcript turn
+type TranscriptAnnotation struct {
+	Base
+	InterviewID    uint `gorm:"index"`
+	TranscriptID   uint `gorm:"index"`
+	ReviewerUserID uint
+	Content        string
+}
+
+func NewTranscriptAnnotation() *TranscriptAnnotation {
+	return &TranscriptAnnotation{}
+}
+
+func (t *TranscriptAnnotation) SetInterviewID(interviewID uint) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.InterviewID = interviewID
+	return t
+}
+
+func (t *TranscriptAnnotation) SetTranscriptID(transcriptID uint) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.TranscriptID = transcriptID
+	return t
+}
+
+func (t *TranscriptAnnotation) SetReviewerUserID(reviewerUserID uint) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.ReviewerUserID = reviewerUserID
+	return t
+}
+
+func (t *TranscriptAnnotation) SetContent(content string) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.Content = content
+	return t
+}
+
+func (t *TranscriptAnnotation) Exists() bool {
+	return t != nil
+}
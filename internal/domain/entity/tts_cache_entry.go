@@ -0,0 +1,82 @@
+package entity
+
+import "github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+
+// TTSCacheEntry points to synthesized audio that is reused for every request with the same hash
+type TTSCacheEntry struct {
+	Base
+	// SHA-256 of the provider, model, voice, language, instruction and text
+	Hash         string `gorm:"uniqueIndex"`
+	Provider     string
+	Model        string
+	Voice        string
+	Text         string
+	ObjectBucket string
+	ObjectKey    string
+}
+
+func NewTTSCacheEntry() *TTSCacheEntry {
+	return &TTSCacheEntry{}
+}
+
+func (t *TTSCacheEntry) SetHash(hash string) *TTSCacheEntry {
+	if t == nil {
+		return nil
+	}
+	t.Hash = hash
+	return t
+}
+
+func (t *TTSCacheEntry) SetProvider(provider string) *TTSCacheEntry {
+	if t == nil {
+		return nil
+	}
+	t.Provider = provider
+	return t
+}
+
+func (t *TTSCacheEntry) SetModel(model string) *TTSCacheEntry {
+	if t == nil {
+		return nil
+	}
+	t.Model = model
+	return t
+}
+
+func (t *TTSCacheEntry) SetVoice(voice string) *TTSCacheEntry {
+	if t == nil {
+		return nil
+	}
+	t.Voice = voice
+	return t
+}
+
+func (t *TTSCacheEntry) SetText(text string) *TTSCacheEntry {
+	if t == nil {
+		return nil
+	}
+	t.Text = text
+	return t
+}
+
+func (t *TTSCacheEntry) SetAudioLocation(location *model.FileLocation) *TTSCacheEntry {
+	if t == nil || !location.Exists() {
+		return t
+	}
+	t.ObjectBucket = location.Bucket
+	t.ObjectKey = location.Key
+	return t
+}
+
+func (t *TTSCacheEntry) GetAudioLocation() *model.FileLocation {
+	if t == nil {
+		return nil
+	}
+	return model.NewFileLocation().
+		SetBucket(t.ObjectBucket).
+		SetKey(t.ObjectKey)
+}
+
+func (t *TTSCacheEntry) Exists() bool {
+	return t != nil
+}
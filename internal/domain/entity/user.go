@@ -7,6 +7,14 @@ import (
 	"gorm.io/plugin/soft_delete"
 )
 
+type UserRole string
+
+const (
+	CANDIDATE_ROLE UserRole = "candidate"
+	REVIEWER_ROLE  UserRole = "reviewer"
+	ADMIN_ROLE     UserRole = "admin"
+)
+
 type User struct {
 	Base
 	Username             string
@@ -16,12 +24,33 @@ type User struct {
 	LastLoginTimeStampMS *int64
 	DeletedTimestampMS   soft_delete.DeletedAt `gorm:"softDelete:milli"`
 	SettingID            uint
+	Role                 UserRole `gorm:"default:candidate"`
 }
 
 func NewUser() *User {
 	return &User{}
 }
 
+func (u *User) HasRole(roles ...UserRole) bool {
+	if u == nil {
+		return false
+	}
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) SetRole(role UserRole) *User {
+	if u == nil {
+		return nil
+	}
+	u.Role = role
+	return u
+}
+
 func (u *User) SetSettingID(settingID uint) *User {
 	if u == nil {
 		return nil
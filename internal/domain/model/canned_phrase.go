@@ -0,0 +1,31 @@
+package model
+
+// CannedPhrase is an interviewer utterance for a fixed moment of the interview, it does not need the LLM
+type CannedPhrase string
+
+const (
+	GREETING_CANNED_PHRASE          CannedPhrase = "greeting"
+	TIME_WARNING_CANNED_PHRASE      CannedPhrase = "time_warning"
+	TIME_UP_CANNED_PHRASE           CannedPhrase = "time_up"
+	RECONNECT_WELCOME_CANNED_PHRASE CannedPhrase = "reconnect_welcome"
+	// Said instead of a reply when the candidate tried to inject instructions, depending on the injection policy
+	INJECTION_REFUSAL_CANNED_PHRASE CannedPhrase = "injection_refusal"
+	INJECTION_WARNING_CANNED_PHRASE CannedPhrase = "injection_warning"
+)
+
+// The variables that the templates of the canned phrases can refer to
+type CannedPhraseVariables struct {
+	MinutesRemaining uint
+}
+
+func NewCannedPhraseVariables() *CannedPhraseVariables {
+	return &CannedPhraseVariables{}
+}
+
+func (c *CannedPhraseVariables) SetMinutesRemaining(minutesRemaining uint) *CannedPhraseVariables {
+	if c == nil {
+		return nil
+	}
+	c.MinutesRemaining = minutesRemaining
+	return c
+}
@@ -0,0 +1,19 @@
+package model
+
+// ExperimentDefinition creates an inactive experiment. The variants cannot be changed afterwards as the interviews refer to them
+type ExperimentDefinition struct {
+	Name        string                         `json:"name"`
+	Description string                         `json:"description"`
+	Variants    []*ExperimentVariantDefinition `json:"variants"`
+}
+
+// The fields that are left empty keep the defaults
+type ExperimentVariantDefinition struct {
+	Name string `json:"name"`
+	// Relative to the other variants of the same experiment
+	Weight        uint   `json:"weight"`
+	PromptVersion string `json:"prompt_version"`
+	Model         string `json:"model"`
+	// Out of 100
+	ClassifierThreshold *float64 `json:"classifier_threshold"`
+}
@@ -0,0 +1,68 @@
+package model
+
+type ExperimentReport struct {
+	ExperimentID string                     `json:"experiment_id"`
+	Name         string                     `json:"name"`
+	Active       bool                       `json:"active"`
+	Variants     []*ExperimentVariantReport `json:"variants"`
+}
+
+func NewExperimentReport() *ExperimentReport {
+	return &ExperimentReport{
+		Variants: make([]*ExperimentVariantReport, 0),
+	}
+}
+
+func (e *ExperimentReport) SetExperimentID(experimentID string) *ExperimentReport {
+	if e == nil {
+		return nil
+	}
+	e.ExperimentID = experimentID
+	return e
+}
+
+func (e *ExperimentReport) SetName(name string) *ExperimentReport {
+	if e == nil {
+		return nil
+	}
+	e.Name = name
+	return e
+}
+
+func (e *ExperimentReport) SetActive(active bool) *ExperimentReport {
+	if e == nil {
+		return nil
+	}
+	e.Active = active
+	return e
+}
+
+func (e *ExperimentReport) AppendVariant(variant *ExperimentVariantReport) *ExperimentReport {
+	if e == nil || variant == nil {
+		return e
+	}
+	e.Variants = append(e.Variants, variant)
+	return e
+}
+
+// The averages and rates are nil when there is nothing to measure them on yet
+type ExperimentVariantReport struct {
+	Name                string   `json:"name"`
+	PromptVersion       string   `json:"prompt_version"`
+	Model               string   `json:"model"`
+	ClassifierThreshold float64  `json:"classifier_threshold"`
+	InterviewCount      uint     `json:"interview_count"`
+	StartedCount        uint     `json:"started_count"`
+	ReviewedCount       uint     `json:"reviewed_count"`
+	AverageScore        *float64 `json:"average_score"`
+	PassRate            *float64 `json:"pass_rate"`
+	// All the replies to the questions of the candidate, including the ones that were neither hints nor clarifications
+	AverageReplyCount *float64 `json:"average_reply_count"`
+	// The replies to the sentences of the candidate that were classified as a request for a hint
+	AverageHintCount *float64 `json:"average_hint_count"`
+	// The replies to the sentences of the candidate that were classified as a request for a clarification
+	AverageClarificationCount *float64 `json:"average_clarification_count"`
+	AbandonmentRate           *float64 `json:"abandonment_rate"`
+	// From the candidate finishing a sentence to the reply being ready, including the TTS
+	AverageReplyLatencyMS *float64 `json:"average_reply_latency_ms"`
+}
@@ -0,0 +1,84 @@
+package model
+
+type FeedbackItem struct {
+	Category string              `json:"category"`
+	Summary  string              `json:"summary"`
+	Evidence []*FeedbackEvidence `json:"evidence"`
+}
+
+func NewFeedbackItem() *FeedbackItem {
+	return &FeedbackItem{
+		Evidence: make([]*FeedbackEvidence, 0),
+	}
+}
+
+func (f *FeedbackItem) SetCategory(category string) *FeedbackItem {
+	if f == nil {
+		return nil
+	}
+	f.Category = category
+	return f
+}
+
+func (f *FeedbackItem) SetSummary(summary string) *FeedbackItem {
+	if f == nil {
+		return nil
+	}
+	f.Summary = summary
+	return f
+}
+
+func (f *FeedbackItem) AddEvidence(evidence *FeedbackEvidence) *FeedbackItem {
+	if f == nil {
+		return nil
+	}
+	f.Evidence = append(f.Evidence, evidence)
+	return f
+}
+
+// FeedbackEvidence lets the frontend deep link into the replay of the interview
+type FeedbackEvidence struct {
+	// Either "transcript" or "code_snapshot"
+	Type string `json:"type"`
+	// This field uses the UUID of the transcript or code snapshot
+	ID         string `json:"id"`
+	TimestampS int64  `json:"timestamp_s"`
+	// Seconds since the start of the interview
+	OffsetS int64 `json:"offset_s"`
+}
+
+func NewFeedbackEvidence() *FeedbackEvidence {
+	return &FeedbackEvidence{}
+}
+
+func (f *FeedbackEvidence) SetType(evidenceType string) *FeedbackEvidence {
+	if f == nil {
+		return nil
+	}
+	f.Type = evidenceType
+	return f
+}
+
+func (f *FeedbackEvidence) SetID(id string) *FeedbackEvidence {
+	if f == nil {
+		return nil
+	}
+	f.ID = id
+	return f
+}
+
+func (f *FeedbackEvidence) SetTimestampS(timestampS int64) *FeedbackEvidence {
+	if f == nil {
+		return nil
+	}
+	f.TimestampS = timestampS
+	return f
+}
+
+func (f *FeedbackEvidence) SetOffsetS(offsetS int64) *FeedbackEvidence {
+	if f == nil {
+		return nil
+	}
+	f.OffsetS = offsetS
+	return f
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"fmt"
+	"io"
+)
+
+// FileLocation is where a file is kept in object storage, URLs to it are signed when they are needed
+type FileLocation struct {
+	Bucket string
+	Key    string
+}
+
+func NewFileLocation() *FileLocation {
+	return &FileLocation{}
+}
+
+func (f *FileLocation) SetBucket(bucket string) *FileLocation {
+	if f == nil {
+		return nil
+	}
+	f.Bucket = bucket
+	return f
+}
+
+func (f *FileLocation) SetKey(key string) *FileLocation {
+	if f == nil {
+		return nil
+	}
+	f.Key = key
+	return f
+}
+
+func (f *FileLocation) Exists() bool {
+	return f != nil && f.Key != ""
+}
+
+// The payload that is signed for the URLs of the local and memory object storage
+func (f *FileLocation) GetSigningPayload(expiresAtS int64) string {
+	if f == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s\n%s\n%d", f.Bucket, f.Key, expiresAtS)
+}
+
+// FileContent has to be closed by the caller after it is read, it is seekable so that byte ranges can be served
+type FileContent struct {
+	Content        io.ReadSeekCloser
+	ContentType    string
+	Size           int64
+	LastModifiedMS int64
+	ETag           string
+}
@@ -10,6 +10,11 @@ const (
 	DEFAULT               Intent = "default"
 )
 
+// Only these intents are used as labels for the intent classification model
+func (i Intent) IsValidLabel() bool {
+	return i == CANDIDATE_EXPLANATION || i == OTHERS
+}
+
 type IntentDetail struct {
 	Mapping map[Intent]float64
 }
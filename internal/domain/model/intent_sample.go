@@ -0,0 +1,84 @@
+package model
+
+import "github.com/ahleongzc/leetcode-live-backend/internal/util"
+
+type IntentSamples struct {
+	Samples []*IntentSample `json:"samples"`
+}
+
+func NewIntentSamples() *IntentSamples {
+	return &IntentSamples{
+		Samples: make([]*IntentSample, 0),
+	}
+}
+
+func (i *IntentSamples) SetSamples(samples []*IntentSample) *IntentSamples {
+	if i == nil {
+		return nil
+	}
+	i.Samples = append([]*IntentSample{}, samples...)
+	return i
+}
+
+type IntentSample struct {
+	// This field uses the UUID of the sample for display purposes
+	ID               string  `json:"id"`
+	Content          string  `json:"content"`
+	PredictedIntent  Intent  `json:"predicted_intent"`
+	Confidence       float64 `json:"confidence"`
+	LabeledIntent    *Intent `json:"labeled_intent"`
+	CreateTimestampS int64   `json:"create_timestamp_s"`
+}
+
+func NewIntentSample() *IntentSample {
+	return &IntentSample{}
+}
+
+// Pass in the UUID here, never use internal id for display
+func (i *IntentSample) SetID(id string) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.ID = id
+	return i
+}
+
+func (i *IntentSample) SetContent(content string) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.Content = content
+	return i
+}
+
+func (i *IntentSample) SetPredictedIntent(intent Intent) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.PredictedIntent = intent
+	return i
+}
+
+func (i *IntentSample) SetConfidence(confidence float64) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.Confidence = confidence
+	return i
+}
+
+func (i *IntentSample) SetLabeledIntent(intent Intent) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.LabeledIntent = util.ToPtr(intent)
+	return i
+}
+
+func (i *IntentSample) SetCreateTimestampS(timestampSeconds int64) *IntentSample {
+	if i == nil {
+		return nil
+	}
+	i.CreateTimestampS = timestampSeconds
+	return i
+}
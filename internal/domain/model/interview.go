@@ -3,8 +3,17 @@ package model
 import "github.com/ahleongzc/leetcode-live-backend/internal/util"
 
 type InterviewerResponse struct {
+	// Empty in text interviews, which have no audio
 	URL string
-	End bool
+	// The reply as it is written in the transcript, for the captions and for when the audio cannot be played
+	Text string
+	// Unique to every message that is sent, a reply that is sent again gets a new one
+	MessageID string
+	// The position of the reply among the replies of the interviewer in the interview, starting from 1
+	Sequence uint
+	// The UUID of the transcript that the reply is written to
+	TranscriptID string
+	End          bool
 }
 
 func NewInterviewerResponse() *InterviewerResponse {
@@ -19,6 +28,38 @@ func (i *InterviewerResponse) SetURL(url string) *InterviewerResponse {
 	return i
 }
 
+func (i *InterviewerResponse) SetText(text string) *InterviewerResponse {
+	if i == nil {
+		return nil
+	}
+	i.Text = text
+	return i
+}
+
+func (i *InterviewerResponse) SetMessageID(messageID string) *InterviewerResponse {
+	if i == nil {
+		return nil
+	}
+	i.MessageID = messageID
+	return i
+}
+
+func (i *InterviewerResponse) SetSequence(sequence uint) *InterviewerResponse {
+	if i == nil {
+		return nil
+	}
+	i.Sequence = sequence
+	return i
+}
+
+func (i *InterviewerResponse) SetTranscriptID(transcriptID string) *InterviewerResponse {
+	if i == nil {
+		return nil
+	}
+	i.TranscriptID = transcriptID
+	return i
+}
+
 func (i *InterviewerResponse) EndInterview() {
 	if i == nil {
 		return
@@ -52,14 +93,23 @@ type Interview struct {
 	// This field uses the UUID of the interview for display purposes
 	ID string `json:"id"`
 	// TODO: This field currently uses the external question ID as the question field, need to see how to change this in the future
-	Question             string  `json:"question"`
-	QuestionAttemptCount uint    `json:"question_attempt_count"`
-	Score                *uint   `json:"score"`
-	Passed               *bool   `json:"passed"`
-	Feedback             *string `json:"feedback"`
-	StartTimestampS      *int64  `json:"start_timestamp_s"`
-	EndTimestampS        *int64  `json:"end_timestamp_s"`
-	TimeRemainingS       *uint   `json:"time_remaining_s"`
+	Question             string `json:"question"`
+	QuestionAttemptCount uint   `json:"question_attempt_count"`
+	// Either "voice" or "text"
+	Mode  string `json:"mode"`
+	Score *uint  `json:"score"`
+	// Only set when the score is the median of several independent reviews
+	ScoreVariance   *float64 `json:"score_variance"`
+	ScoreConfidence *string  `json:"score_confidence"`
+	// Either "pending", "reviewed" or "failed", empty when there is no review yet
+	ReviewStatus    string          `json:"review_status"`
+	Passed          *bool           `json:"passed"`
+	Feedback        *string         `json:"feedback"`
+	Rubric          []*RubricScore  `json:"rubric"`
+	FeedbackItems   []*FeedbackItem `json:"feedback_items"`
+	StartTimestampS *int64          `json:"start_timestamp_s"`
+	EndTimestampS   *int64          `json:"end_timestamp_s"`
+	TimeRemainingS  *uint           `json:"time_remaining_s"`
 }
 
 func NewInterview() *Interview {
@@ -83,6 +133,14 @@ func (i *Interview) SetID(id string) *Interview {
 	return i
 }
 
+func (i *Interview) SetMode(mode string) *Interview {
+	if i == nil {
+		return nil
+	}
+	i.Mode = mode
+	return i
+}
+
 func (i *Interview) SetQuestionAttemptCount(count uint) *Interview {
 	if i == nil {
 		return nil
@@ -131,6 +189,46 @@ func (i *Interview) SetScore(score uint) *Interview {
 	return i
 }
 
+func (i *Interview) SetScoreVariance(scoreVariance float64) *Interview {
+	if i == nil {
+		return nil
+	}
+	i.ScoreVariance = util.ToPtr(scoreVariance)
+	return i
+}
+
+func (i *Interview) SetReviewStatus(reviewStatus string) *Interview {
+	if i == nil {
+		return nil
+	}
+	i.ReviewStatus = reviewStatus
+	return i
+}
+
+func (i *Interview) SetScoreConfidence(scoreConfidence string) *Interview {
+	if i == nil {
+		return nil
+	}
+	i.ScoreConfidence = util.ToPtr(scoreConfidence)
+	return i
+}
+
+func (i *Interview) SetRubric(rubric []*RubricScore) *Interview {
+	if i == nil {
+		return nil
+	}
+	i.Rubric = append([]*RubricScore{}, rubric...)
+	return i
+}
+
+func (i *Interview) SetFeedbackItems(feedbackItems []*FeedbackItem) *Interview {
+	if i == nil {
+		return nil
+	}
+	i.FeedbackItems = append([]*FeedbackItem{}, feedbackItems...)
+	return i
+}
+
 func (i *Interview) SetPassed(passed bool) *Interview {
 	if i == nil {
 		return nil
@@ -0,0 +1,109 @@
+package model
+
+// InterviewReplay is the full timeline of an interview, every offset is relative to the start of the interview
+type InterviewReplay struct {
+	Interview     *Interview        `json:"interview"`
+	Turns         []*TranscriptTurn `json:"turns"`
+	CodeSnapshots []*CodeSnapshot   `json:"code_snapshots"`
+	Events        []*InterviewEvent `json:"events"`
+}
+
+func NewInterviewReplay() *InterviewReplay {
+	return &InterviewReplay{
+		Turns:         make([]*TranscriptTurn, 0),
+		CodeSnapshots: make([]*CodeSnapshot, 0),
+		Events:        make([]*InterviewEvent, 0),
+	}
+}
+
+func (i *InterviewReplay) SetInterview(interview *Interview) *InterviewReplay {
+	if i == nil {
+		return nil
+	}
+	i.Interview = interview
+	return i
+}
+
+func (i *InterviewReplay) AppendTurn(turn *TranscriptTurn) *InterviewReplay {
+	if i == nil || turn == nil {
+		return i
+	}
+	i.Turns = append(i.Turns, turn)
+	return i
+}
+
+func (i *InterviewReplay) AppendCodeSnapshot(codeSnapshot *CodeSnapshot) *InterviewReplay {
+	if i == nil || codeSnapshot == nil {
+		return i
+	}
+	i.CodeSnapshots = append(i.CodeSnapshots, codeSnapshot)
+	return i
+}
+
+func (i *InterviewReplay) AppendEvent(event *InterviewEvent) *InterviewReplay {
+	if i == nil || event == nil {
+		return i
+	}
+	i.Events = append(i.Events, event)
+	return i
+}
+
+type CodeSnapshot struct {
+	// This field uses the UUID of the code snapshot
+	ID       string `json:"id"`
+	Code     string `json:"code"`
+	OffsetMS int64  `json:"offset_ms"`
+}
+
+func NewCodeSnapshot() *CodeSnapshot {
+	return &CodeSnapshot{}
+}
+
+func (c *CodeSnapshot) SetID(id string) *CodeSnapshot {
+	if c == nil {
+		return nil
+	}
+	c.ID = id
+	return c
+}
+
+func (c *CodeSnapshot) SetCode(code string) *CodeSnapshot {
+	if c == nil {
+		return nil
+	}
+	c.Code = code
+	return c
+}
+
+func (c *CodeSnapshot) SetOffsetMS(offsetMS int64) *CodeSnapshot {
+	if c == nil {
+		return nil
+	}
+	c.OffsetMS = offsetMS
+	return c
+}
+
+type InterviewEvent struct {
+	Type     string `json:"type"`
+	OffsetMS int64  `json:"offset_ms"`
+}
+
+func NewInterviewEvent() *InterviewEvent {
+	return &InterviewEvent{}
+}
+
+func (i *InterviewEvent) SetType(eventType string) *InterviewEvent {
+	if i == nil {
+		return nil
+	}
+	i.Type = eventType
+	return i
+}
+
+func (i *InterviewEvent) SetOffsetMS(offsetMS int64) *InterviewEvent {
+	if i == nil {
+		return nil
+	}
+	i.OffsetMS = offsetMS
+	return i
+}
@@ -0,0 +1,203 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+type JSONSchemaType string
+
+const (
+	JSON_SCHEMA_OBJECT  JSONSchemaType = "object"
+	JSON_SCHEMA_ARRAY   JSONSchemaType = "array"
+	JSON_SCHEMA_STRING  JSONSchemaType = "string"
+	JSON_SCHEMA_INTEGER JSONSchemaType = "integer"
+	JSON_SCHEMA_NUMBER  JSONSchemaType = "number"
+	JSON_SCHEMA_BOOLEAN JSONSchemaType = "boolean"
+)
+
+// JSONSchema is the subset of JSON Schema that is needed to validate LLM output,
+// it is also sent as is to providers that support structured output
+type JSONSchema struct {
+	Type                 JSONSchemaType         `json:"type"`
+	Description          string                 `json:"description,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinItems             *uint                  `json:"minItems,omitempty"`
+	MaxItems             *uint                  `json:"maxItems,omitempty"`
+}
+
+func NewJSONSchema(schemaType JSONSchemaType) *JSONSchema {
+	return &JSONSchema{
+		Type: schemaType,
+	}
+}
+
+func (j *JSONSchema) SetDescription(description string) *JSONSchema {
+	if j == nil {
+		return nil
+	}
+	j.Description = description
+	return j
+}
+
+// Properties added here are required by default, because a missing key from the LLM is almost always a mistake
+func (j *JSONSchema) AddProperty(name string, property *JSONSchema) *JSONSchema {
+	if j == nil {
+		return nil
+	}
+	if j.Properties == nil {
+		j.Properties = make(map[string]*JSONSchema)
+	}
+	j.Properties[name] = property
+	j.Required = append(j.Required, name)
+	return j
+}
+
+func (j *JSONSchema) DisallowAdditionalProperties() *JSONSchema {
+	if j == nil {
+		return nil
+	}
+	additionalProperties := false
+	j.AdditionalProperties = &additionalProperties
+	return j
+}
+
+func (j *JSONSchema) SetItems(items *JSONSchema) *JSONSchema {
+	if j == nil {
+		return nil
+	}
+	j.Items = items
+	return j
+}
+
+func (j *JSONSchema) SetEnum(enum []string) *JSONSchema {
+	if j == nil {
+		return nil
+	}
+	j.Enum = enum
+	return j
+}
+
+func (j *JSONSchema) SetRange(minimum, maximum float64) *JSONSchema {
+	if j == nil {
+		return nil
+	}
+	j.Minimum = &minimum
+	j.Maximum = &maximum
+	return j
+}
+
+func (j *JSONSchema) SetItemsRange(minItems, maxItems uint) *JSONSchema {
+	if j == nil {
+		return nil
+	}
+	j.MinItems = &minItems
+	j.MaxItems = &maxItems
+	return j
+}
+
+// Validate checks a value decoded by encoding/json into an any against the schema.
+// Every violation is returned so that all of them can be sent back to the LLM in one repair attempt.
+func (j *JSONSchema) Validate(value any) []string {
+	return j.validate("$", value)
+}
+
+func (j *JSONSchema) validate(path string, value any) []string {
+	if j == nil {
+		return nil
+	}
+
+	violations := make([]string, 0)
+
+	switch j.Type {
+	case JSON_SCHEMA_OBJECT:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return append(violations, fmt.Sprintf("%s must be an object", path))
+		}
+
+		for _, key := range j.Required {
+			if _, ok := object[key]; !ok {
+				violations = append(violations, fmt.Sprintf("%s is missing the required key '%s'", path, key))
+			}
+		}
+
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			property, ok := j.Properties[key]
+			if !ok {
+				if j.AdditionalProperties != nil && !*j.AdditionalProperties {
+					violations = append(violations, fmt.Sprintf("%s has an unexpected key '%s'", path, key))
+				}
+				continue
+			}
+			violations = append(violations, property.validate(path+"."+key, object[key])...)
+		}
+
+	case JSON_SCHEMA_ARRAY:
+		array, ok := value.([]any)
+		if !ok {
+			return append(violations, fmt.Sprintf("%s must be an array", path))
+		}
+
+		if j.MinItems != nil && uint(len(array)) < *j.MinItems {
+			violations = append(violations, fmt.Sprintf("%s must have at least %d items but has %d", path, *j.MinItems, len(array)))
+		}
+		if j.MaxItems != nil && uint(len(array)) > *j.MaxItems {
+			violations = append(violations, fmt.Sprintf("%s must have at most %d items but has %d", path, *j.MaxItems, len(array)))
+		}
+
+		for index, item := range array {
+			violations = append(violations, j.Items.validate(fmt.Sprintf("%s[%d]", path, index), item)...)
+		}
+
+	case JSON_SCHEMA_STRING:
+		str, ok := value.(string)
+		if !ok {
+			return append(violations, fmt.Sprintf("%s must be a string", path))
+		}
+
+		if len(j.Enum) > 0 && !slices.Contains(j.Enum, str) {
+			violations = append(violations, fmt.Sprintf("%s must be one of %v but is '%s'", path, j.Enum, str))
+		}
+
+	case JSON_SCHEMA_INTEGER, JSON_SCHEMA_NUMBER:
+		number, ok := value.(float64)
+		if !ok && j.Type == JSON_SCHEMA_INTEGER {
+			return append(violations, fmt.Sprintf("%s must be an integer", path))
+		}
+		if !ok {
+			return append(violations, fmt.Sprintf("%s must be a number", path))
+		}
+
+		if j.Type == JSON_SCHEMA_INTEGER && number != math.Trunc(number) {
+			violations = append(violations, fmt.Sprintf("%s must be an integer but is %v", path, number))
+		}
+		if j.Minimum != nil && number < *j.Minimum {
+			violations = append(violations, fmt.Sprintf("%s must be at least %v but is %v", path, *j.Minimum, number))
+		}
+		if j.Maximum != nil && number > *j.Maximum {
+			violations = append(violations, fmt.Sprintf("%s must be at most %v but is %v", path, *j.Maximum, number))
+		}
+
+	case JSON_SCHEMA_BOOLEAN:
+		if _, ok := value.(bool); !ok {
+			violations = append(violations, fmt.Sprintf("%s must be a boolean", path))
+		}
+	}
+
+	return violations
+}
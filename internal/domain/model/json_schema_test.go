@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func newTestReviewSchema() *JSONSchema {
+	rubricScore := NewJSONSchema(JSON_SCHEMA_OBJECT).
+		AddProperty("dimension", NewJSONSchema(JSON_SCHEMA_STRING).SetEnum([]string{"coding", "communication"})).
+		AddProperty("score", NewJSONSchema(JSON_SCHEMA_INTEGER).SetRange(1, 5)).
+		DisallowAdditionalProperties()
+
+	return NewJSONSchema(JSON_SCHEMA_OBJECT).
+		AddProperty("score", NewJSONSchema(JSON_SCHEMA_INTEGER).SetRange(0, 100)).
+		AddProperty("variance", NewJSONSchema(JSON_SCHEMA_NUMBER)).
+		AddProperty("passed", NewJSONSchema(JSON_SCHEMA_BOOLEAN)).
+		AddProperty("rubric", NewJSONSchema(JSON_SCHEMA_ARRAY).SetItems(rubricScore).SetItemsRange(1, 2)).
+		DisallowAdditionalProperties()
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema := newTestReviewSchema()
+
+	testCases := []struct {
+		name               string
+		input              string
+		expectedViolations []string
+	}{
+		{
+			name:               "valid",
+			input:              `{"score": 80, "variance": 2.5, "passed": true, "rubric": [{"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{},
+		},
+		{
+			name:               "integer written as a float without a fraction",
+			input:              `{"score": 80.0, "variance": 2, "passed": true, "rubric": [{"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{},
+		},
+		{
+			name:               "missing required key",
+			input:              `{"score": 80, "variance": 2.5, "rubric": [{"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{"$ is missing the required key 'passed'"},
+		},
+		{
+			name:               "unexpected key",
+			input:              `{"score": 80, "variance": 2.5, "passed": true, "rubric": [{"dimension": "coding", "score": 4}], "notes": ""}`,
+			expectedViolations: []string{"$ has an unexpected key 'notes'"},
+		},
+		{
+			name:               "float where an integer is expected",
+			input:              `{"score": 80.5, "variance": 2.5, "passed": true, "rubric": [{"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{"$.score must be an integer but is 80.5"},
+		},
+		{
+			name:               "string where an integer is expected",
+			input:              `{"score": "80", "variance": 2.5, "passed": true, "rubric": [{"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{"$.score must be an integer"},
+		},
+		{
+			name:               "string where a number is expected",
+			input:              `{"score": 80, "variance": "2.5", "passed": true, "rubric": [{"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{"$.variance must be a number"},
+		},
+		{
+			name:               "string where a boolean is expected",
+			input:              `{"score": 80, "variance": 2.5, "passed": "yes", "rubric": [{"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{"$.passed must be a boolean"},
+		},
+		{
+			name:               "out of range",
+			input:              `{"score": 101, "variance": 2.5, "passed": true, "rubric": [{"dimension": "coding", "score": 0}]}`,
+			expectedViolations: []string{"$.rubric[0].score must be at least 1 but is 0", "$.score must be at most 100 but is 101"},
+		},
+		{
+			name:               "enum violation in a nested object",
+			input:              `{"score": 80, "variance": 2.5, "passed": true, "rubric": [{"dimension": "coding", "score": 4}, {"dimension": "speed", "score": 3}]}`,
+			expectedViolations: []string{"$.rubric[1].dimension must be one of [coding communication] but is 'speed'"},
+		},
+		{
+			name:               "too few items",
+			input:              `{"score": 80, "variance": 2.5, "passed": true, "rubric": []}`,
+			expectedViolations: []string{"$.rubric must have at least 1 items but has 0"},
+		},
+		{
+			name:               "too many items",
+			input:              `{"score": 80, "variance": 2.5, "passed": true, "rubric": [{"dimension": "coding", "score": 4}, {"dimension": "coding", "score": 4}, {"dimension": "coding", "score": 4}]}`,
+			expectedViolations: []string{"$.rubric must have at most 2 items but has 3"},
+		},
+		{
+			name:               "object where an array is expected",
+			input:              `{"score": 80, "variance": 2.5, "passed": true, "rubric": {"dimension": "coding", "score": 4}}`,
+			expectedViolations: []string{"$.rubric must be an array"},
+		},
+		{
+			name:               "array where an object is expected",
+			input:              `[{"score": 80}]`,
+			expectedViolations: []string{"$ must be an object"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var value any
+			if err := json.Unmarshal([]byte(testCase.input), &value); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			violations := schema.Validate(value)
+			slices.Sort(violations)
+
+			if !slices.Equal(violations, testCase.expectedViolations) {
+				t.Errorf("violations = %q, want %q", violations, testCase.expectedViolations)
+			}
+		})
+	}
+}
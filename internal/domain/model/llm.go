@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"time"
+	"unicode/utf8"
+)
 
 type LLMRole string
 
@@ -10,8 +13,19 @@ const (
 	ASSISTANT LLMRole = "assistant"
 )
 
+type ResponseFormatType string
+
+const (
+	TEXT_RESPONSE_FORMAT        ResponseFormatType = "text"
+	JSON_OBJECT_RESPONSE_FORMAT ResponseFormatType = "json_object"
+	JSON_SCHEMA_RESPONSE_FORMAT ResponseFormatType = "json_schema"
+)
+
 type ChatCompletionsRequest struct {
-	Messages []*LLMMessage
+	// Overrides the model of the LLM provider when it is not empty
+	Model          string
+	Messages       []*LLMMessage
+	ResponseFormat *ResponseFormat
 }
 
 func NewChatCompletionsRequest() *ChatCompletionsRequest {
@@ -38,11 +52,86 @@ func (c *ChatCompletionsRequest) GetMessages() []*LLMMessage {
 	return c.Messages
 }
 
+func (c *ChatCompletionsRequest) SetModel(model string) *ChatCompletionsRequest {
+	if c == nil {
+		return nil
+	}
+	c.Model = model
+	return c
+}
+
+func (c *ChatCompletionsRequest) GetModel() string {
+	if c == nil {
+		return ""
+	}
+	return c.Model
+}
+
+func (c *ChatCompletionsRequest) SetResponseFormat(responseFormat *ResponseFormat) *ChatCompletionsRequest {
+	if c == nil {
+		return nil
+	}
+	c.ResponseFormat = responseFormat
+	return c
+}
+
+// Defaults to plain text when no response format is set
+func (c *ChatCompletionsRequest) GetResponseFormat() *ResponseFormat {
+	if c == nil || c.ResponseFormat == nil {
+		return NewResponseFormat(TEXT_RESPONSE_FORMAT)
+	}
+	return c.ResponseFormat
+}
+
+type ResponseFormat struct {
+	Type ResponseFormatType
+	// Name and Schema are only used by JSON_SCHEMA_RESPONSE_FORMAT
+	Name   string
+	Schema *JSONSchema
+}
+
+func NewResponseFormat(responseFormatType ResponseFormatType) *ResponseFormat {
+	return &ResponseFormat{
+		Type: responseFormatType,
+	}
+}
+
+func (r *ResponseFormat) SetSchema(name string, schema *JSONSchema) *ResponseFormat {
+	if r == nil {
+		return nil
+	}
+	r.Name = name
+	r.Schema = schema
+	return r
+}
+
+// A rough heuristic that works across tokenizers, so that the context budget can be checked without calling the provider
+const (
+	LLM_CHARACTERS_PER_TOKEN   int = 4
+	LLM_MESSAGE_TOKEN_OVERHEAD int = 4
+)
+
 type LLMMessage struct {
 	Role    LLMRole
 	Content string
 }
 
+func (l *LLMMessage) EstimateTokens() int {
+	if l == nil {
+		return 0
+	}
+	characters := utf8.RuneCountInString(l.Content)
+	return (characters+LLM_CHARACTERS_PER_TOKEN-1)/LLM_CHARACTERS_PER_TOKEN + LLM_MESSAGE_TOKEN_OVERHEAD
+}
+
+func EstimateTokens(messages []*LLMMessage) int {
+	tokens := 0
+	for _, message := range messages {
+		tokens += message.EstimateTokens()
+	}
+	return tokens
+}
+
 func (l *LLMMessage) GetRole() LLMRole {
 	if l == nil {
 		return SYSTEM
@@ -0,0 +1,137 @@
+package model
+
+// PromptName identifies a prompt template, every name can have several versions
+type PromptName string
+
+const (
+	INTERVIEWER_SYSTEM_PROMPT       PromptName = "interviewer_system"
+	INTERVIEWER_REPLY_PROMPT        PromptName = "interviewer_reply"
+	INTERVIEWER_INTRODUCTION_PROMPT PromptName = "interviewer_introduction"
+	INTERVIEWER_RECAP_PROMPT        PromptName = "interviewer_recap"
+	// The instruction for the TTS on how the interviewer should sound
+	INTERVIEWER_SPEECH_PROMPT PromptName = "interviewer_speech"
+	// Appended to the reply prompt when the reply gave away the solution
+	INTERVIEWER_LEAK_CORRECTION_PROMPT PromptName = "interviewer_leak_correction"
+	REVIEW_PROMPT                      PromptName = "review"
+	// Scores a replayed reply of the interviewer against the original one
+	REGRESSION_JUDGE_PROMPT PromptName = "regression_judge"
+	// Decides whether the content from the candidate tries to manipulate the LLM
+	INJECTION_CLASSIFIER_PROMPT PromptName = "injection_classifier"
+	STUDY_PLAN_PROMPT           PromptName = "study_plan"
+	// Folds the older turns of an interview into a summary when the history does not fit in the context budget
+	TRANSCRIPT_SUMMARY_PROMPT PromptName = "transcript_summary"
+)
+
+// PromptScope decides which override of a template is used, the zero value only matches the default templates
+type PromptScope struct {
+	QuestionID    uint
+	InterviewType string
+	// Prefers the templates of this version, the names that do not have it keep their default
+	Version string
+}
+
+func NewPromptScope() *PromptScope {
+	return &PromptScope{}
+}
+
+func (p *PromptScope) SetQuestionID(questionID uint) *PromptScope {
+	if p == nil {
+		return nil
+	}
+	p.QuestionID = questionID
+	return p
+}
+
+func (p *PromptScope) SetInterviewType(interviewType string) *PromptScope {
+	if p == nil {
+		return nil
+	}
+	p.InterviewType = interviewType
+	return p
+}
+
+func (p *PromptScope) SetVersion(version string) *PromptScope {
+	if p == nil {
+		return nil
+	}
+	p.Version = version
+	return p
+}
+
+// The variables that the prompt templates can refer to, each template only uses some of them
+type PromptVariables struct {
+	QuestionDescription string
+	LatestCode          string
+	RubricDimensions    []string
+	RubricMaxScore      uint
+	FeedbackCategories  []string
+	// Why the previous reply was rejected by the solution leak guard
+	LeakReason         string
+	MaxWeakTopics      uint
+	MaxRecommendations uint
+}
+
+func NewPromptVariables() *PromptVariables {
+	return &PromptVariables{}
+}
+
+func (p *PromptVariables) SetQuestionDescription(questionDescription string) *PromptVariables {
+	if p == nil {
+		return nil
+	}
+	p.QuestionDescription = questionDescription
+	return p
+}
+
+func (p *PromptVariables) SetLatestCode(latestCode string) *PromptVariables {
+	if p == nil {
+		return nil
+	}
+	p.LatestCode = latestCode
+	return p
+}
+
+func (p *PromptVariables) SetRubric(dimensions []string, maxScore uint) *PromptVariables {
+	if p == nil {
+		return nil
+	}
+	p.RubricDimensions = dimensions
+	p.RubricMaxScore = maxScore
+	return p
+}
+
+func (p *PromptVariables) SetFeedbackCategories(categories []string) *PromptVariables {
+	if p == nil {
+		return nil
+	}
+	p.FeedbackCategories = categories
+	return p
+}
+
+func (p *PromptVariables) SetLeakReason(leakReason string) *PromptVariables {
+	if p == nil {
+		return nil
+	}
+	p.LeakReason = leakReason
+	return p
+}
+
+func (p *PromptVariables) SetStudyPlanLimits(maxWeakTopics, maxRecommendations uint) *PromptVariables {
+	if p == nil {
+		return nil
+	}
+	p.MaxWeakTopics = maxWeakTopics
+	p.MaxRecommendations = maxRecommendations
+	return p
+}
+
+// RenderedPrompt keeps the version of the template so that it can be recorded with whatever the prompt produced
+type RenderedPrompt struct {
+	Name    PromptName
+	Version string
+	Content string
+}
+
+func (r *RenderedPrompt) Exists() bool {
+	return r != nil
+}
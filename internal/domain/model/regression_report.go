@@ -0,0 +1,128 @@
+package model
+
+// RegressionConfig is the interviewer configuration that the stored interviews are replayed against,
+// the empty fields keep the defaults of each interview
+type RegressionConfig struct {
+	// UUIDs, takes precedence over the question
+	InterviewIDs []string
+	// External ID, the latest ended interviews of the question are replayed
+	QuestionID    string
+	Limit         uint
+	Model         string
+	PromptVersion string
+	JudgeModel    string
+}
+
+func NewRegressionConfig() *RegressionConfig {
+	return &RegressionConfig{
+		InterviewIDs: make([]string, 0),
+	}
+}
+
+func (r *RegressionConfig) SetInterviewIDs(interviewIDs []string) *RegressionConfig {
+	if r == nil {
+		return nil
+	}
+	r.InterviewIDs = append([]string{}, interviewIDs...)
+	return r
+}
+
+func (r *RegressionConfig) SetQuestionID(questionID string) *RegressionConfig {
+	if r == nil {
+		return nil
+	}
+	r.QuestionID = questionID
+	return r
+}
+
+func (r *RegressionConfig) SetLimit(limit uint) *RegressionConfig {
+	if r == nil {
+		return nil
+	}
+	r.Limit = limit
+	return r
+}
+
+func (r *RegressionConfig) SetModel(model string) *RegressionConfig {
+	if r == nil {
+		return nil
+	}
+	r.Model = model
+	return r
+}
+
+func (r *RegressionConfig) SetPromptVersion(promptVersion string) *RegressionConfig {
+	if r == nil {
+		return nil
+	}
+	r.PromptVersion = promptVersion
+	return r
+}
+
+func (r *RegressionConfig) SetJudgeModel(judgeModel string) *RegressionConfig {
+	if r == nil {
+		return nil
+	}
+	r.JudgeModel = judgeModel
+	return r
+}
+
+type RegressionReport struct {
+	Model string `json:"model"`
+	// The versions that the replayed prompts resolved to, comma separated when the interviews resolved to different ones
+	PromptVersion  string `json:"prompt_version"`
+	JudgeModel     string `json:"judge_model"`
+	InterviewCount uint   `json:"interview_count"`
+	ReplyCount     uint   `json:"reply_count"`
+	// The replies that could not be replayed or judged, they are left out of the averages
+	FailedCount uint `json:"failed_count"`
+	// Averages over the judged replies, nil when nothing was judged
+	Original     *RegressionScores  `json:"original"`
+	Replayed     *RegressionScores  `json:"replayed"`
+	ReplayedWins uint               `json:"replayed_wins"`
+	OriginalWins uint               `json:"original_wins"`
+	Ties         uint               `json:"ties"`
+	Replies      []*RegressionReply `json:"replies"`
+}
+
+func NewRegressionReport() *RegressionReport {
+	return &RegressionReport{
+		Replies: make([]*RegressionReply, 0),
+	}
+}
+
+func (r *RegressionReport) AppendReply(reply *RegressionReply) *RegressionReport {
+	if r == nil {
+		return nil
+	}
+	r.Replies = append(r.Replies, reply)
+	return r
+}
+
+// RegressionScores are from 1 to 5, higher is better for every criterion
+type RegressionScores struct {
+	// 5 means that no part of the solution was given away
+	NoSolutionLeakage float64 `json:"no_solution_leakage"`
+	Conciseness       float64 `json:"conciseness"`
+	Correctness       float64 `json:"correctness"`
+}
+
+type RegressionReply struct {
+	InterviewID string `json:"interview_id"`
+	// The UUID of the original reply
+	TranscriptID     string `json:"transcript_id"`
+	CandidateMessage string `json:"candidate_message"`
+	OriginalReply    string `json:"original_reply"`
+	ReplayedReply    string `json:"replayed_reply"`
+	// The version that the reply prompt resolved to, the system prompt is rendered with the same scope
+	PromptVersion string `json:"prompt_version,omitempty"`
+	// Set when the solution leak guard had to rework the replayed reply
+	GuardReason string            `json:"guard_reason,omitempty"`
+	Original    *RegressionScores `json:"original"`
+	Replayed    *RegressionScores `json:"replayed"`
+	// Either "original", "replayed" or "tie"
+	Preferred string `json:"preferred"`
+	Reason    string `json:"reason"`
+	// Only set when the reply could not be replayed or judged
+	Error string `json:"error,omitempty"`
+}
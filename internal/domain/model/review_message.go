@@ -2,4 +2,6 @@ package model
 
 type ReviewMessage struct {
 	InterviewID uint `json:"interview_id"`
+	// A re-review creates a new version of the review instead of filling in the current one
+	ReReview bool `json:"re_review"`
 }
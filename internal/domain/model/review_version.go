@@ -0,0 +1,150 @@
+package model
+
+import "github.com/ahleongzc/leetcode-live-backend/internal/util"
+
+type ReviewVersions struct {
+	Versions []*ReviewVersion `json:"versions"`
+}
+
+func NewReviewVersions() *ReviewVersions {
+	return &ReviewVersions{
+		Versions: make([]*ReviewVersion, 0),
+	}
+}
+
+func (r *ReviewVersions) SetVersions(versions []*ReviewVersion) *ReviewVersions {
+	if r == nil {
+		return nil
+	}
+	r.Versions = append([]*ReviewVersion{}, versions...)
+	return r
+}
+
+type ReviewVersion struct {
+	Version uint `json:"version"`
+	// Either "ai" or "human"
+	Source string `json:"source"`
+	// Only set for human reviews, the version that was overridden and the reason for it
+	BaseVersion   *uint  `json:"base_version"`
+	Justification string `json:"justification"`
+	Model         string `json:"model"`
+	PromptVersion string `json:"prompt_version"`
+	Score         uint   `json:"score"`
+	Passed        bool   `json:"passed"`
+	Feedback      string `json:"feedback"`
+	// Whether this is the version that the candidate sees
+	Displayed bool `json:"displayed"`
+	// Whether the interview had a prompt injection that was let through
+	Flagged bool `json:"flagged"`
+	// Either "pending", "reviewed" or "failed"
+	Status             string `json:"status"`
+	ReviewedTimestampS *int64 `json:"reviewed_timestamp_s"`
+}
+
+func NewReviewVersion() *ReviewVersion {
+	return &ReviewVersion{}
+}
+
+func (r *ReviewVersion) SetVersion(version uint) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Version = version
+	return r
+}
+
+func (r *ReviewVersion) SetSource(source string) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Source = source
+	return r
+}
+
+func (r *ReviewVersion) SetBaseVersion(baseVersion uint) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.BaseVersion = util.ToPtr(baseVersion)
+	return r
+}
+
+func (r *ReviewVersion) SetJustification(justification string) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Justification = justification
+	return r
+}
+
+func (r *ReviewVersion) SetModel(model string) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Model = model
+	return r
+}
+
+func (r *ReviewVersion) SetPromptVersion(promptVersion string) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.PromptVersion = promptVersion
+	return r
+}
+
+func (r *ReviewVersion) SetScore(score uint) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Score = score
+	return r
+}
+
+func (r *ReviewVersion) SetPassed(passed bool) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Passed = passed
+	return r
+}
+
+func (r *ReviewVersion) SetFeedback(feedback string) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Feedback = feedback
+	return r
+}
+
+func (r *ReviewVersion) SetDisplayed(displayed bool) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Displayed = displayed
+	return r
+}
+
+func (r *ReviewVersion) SetStatus(status string) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Status = status
+	return r
+}
+
+func (r *ReviewVersion) SetFlagged(flagged bool) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.Flagged = flagged
+	return r
+}
+
+func (r *ReviewVersion) SetReviewedTimestampS(reviewedTimestampS int64) *ReviewVersion {
+	if r == nil {
+		return nil
+	}
+	r.ReviewedTimestampS = util.ToPtr(reviewedTimestampS)
+	return r
+}
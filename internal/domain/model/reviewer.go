@@ -0,0 +1,144 @@
+package model
+
+// ReviewOverride only changes the fields that are set, the justification is always required
+type ReviewOverride struct {
+	Score         *uint   `json:"score"`
+	Passed        *bool   `json:"passed"`
+	Feedback      *string `json:"feedback"`
+	Justification string  `json:"justification"`
+}
+
+type TranscriptAnnotations struct {
+	Annotations []*TranscriptAnnotation `json:"annotations"`
+}
+
+func NewTranscriptAnnotations() *TranscriptAnnotations {
+	return &TranscriptAnnotations{
+		Annotations: make([]*TranscriptAnnotation, 0),
+	}
+}
+
+func (t *TranscriptAnnotations) SetAnnotations(annotations []*TranscriptAnnotation) *TranscriptAnnotations {
+	if t == nil {
+		return nil
+	}
+	t.Annotations = append([]*TranscriptAnnotation{}, annotations...)
+	return t
+}
+
+type TranscriptAnnotation struct {
+	// This field uses the UUID of the annotation
+	ID string `json:"id"`
+	// This field uses the UUID of the transcript
+	TranscriptID     string `json:"transcript_id"`
+	Content          string `json:"content"`
+	CreateTimestampS int64  `json:"create_timestamp_s"`
+}
+
+func NewTranscriptAnnotation() *TranscriptAnnotation {
+	return &TranscriptAnnotation{}
+}
+
+func (t *TranscriptAnnotation) SetID(id string) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.ID = id
+	return t
+}
+
+func (t *TranscriptAnnotation) SetTranscriptID(transcriptID string) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.TranscriptID = transcriptID
+	return t
+}
+
+func (t *TranscriptAnnotation) SetContent(content string) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.Content = content
+	return t
+}
+
+func (t *TranscriptAnnotation) SetCreateTimestampS(createTimestampS int64) *TranscriptAnnotation {
+	if t == nil {
+		return nil
+	}
+	t.CreateTimestampS = createTimestampS
+	return t
+}
+
+type AuditLogs struct {
+	Logs []*AuditLog `json:"logs"`
+}
+
+func NewAuditLogs() *AuditLogs {
+	return &AuditLogs{
+		Logs: make([]*AuditLog, 0),
+	}
+}
+
+func (a *AuditLogs) SetLogs(logs []*AuditLog) *AuditLogs {
+	if a == nil {
+		return nil
+	}
+	a.Logs = append([]*AuditLog{}, logs...)
+	return a
+}
+
+type AuditLog struct {
+	Action           string         `json:"action"`
+	ActorUserID      uint           `json:"actor_user_id"`
+	Before           map[string]any `json:"before"`
+	After            map[string]any `json:"after"`
+	Reason           string         `json:"reason"`
+	CreateTimestampS int64          `json:"create_timestamp_s"`
+}
+
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+func (a *AuditLog) SetAction(action string) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.Action = action
+	return a
+}
+
+func (a *AuditLog) SetActorUserID(actorUserID uint) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.ActorUserID = actorUserID
+	return a
+}
+
+func (a *AuditLog) SetChange(before, after map[string]any) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.Before = before
+	a.After = after
+	return a
+}
+
+func (a *AuditLog) SetReason(reason string) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.Reason = reason
+	return a
+}
+
+func (a *AuditLog) SetCreateTimestampS(createTimestampS int64) *AuditLog {
+	if a == nil {
+		return nil
+	}
+	a.CreateTimestampS = createTimestampS
+	return a
+}
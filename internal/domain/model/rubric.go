@@ -0,0 +1,44 @@
+package model
+
+type RubricScore struct {
+	Dimension     string `json:"dimension"`
+	Score         uint   `json:"score"`
+	MaxScore      uint   `json:"max_score"`
+	Justification string `json:"justification"`
+}
+
+func NewRubricScore() *RubricScore {
+	return &RubricScore{}
+}
+
+func (r *RubricScore) SetDimension(dimension string) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.Dimension = dimension
+	return r
+}
+
+func (r *RubricScore) SetScore(score uint) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.Score = score
+	return r
+}
+
+func (r *RubricScore) SetMaxScore(maxScore uint) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.MaxScore = maxScore
+	return r
+}
+
+func (r *RubricScore) SetJustification(justification string) *RubricScore {
+	if r == nil {
+		return nil
+	}
+	r.Justification = justification
+	return r
+}
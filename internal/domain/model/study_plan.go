@@ -0,0 +1,139 @@
+package model
+
+type StudyPlanMessage struct {
+	UserID uint `json:"user_id"`
+}
+
+type StudyPlan struct {
+	Summary              string               `json:"summary"`
+	WeakTopics           []*StudyPlanTopic    `json:"weak_topics"`
+	RecommendedQuestions []*StudyPlanQuestion `json:"recommended_questions"`
+	Exercises            []string             `json:"exercises"`
+	ReviewCount          uint                 `json:"review_count"`
+	UpdateTimestampS     int64                `json:"update_timestamp_s"`
+}
+
+func NewStudyPlan() *StudyPlan {
+	return &StudyPlan{
+		WeakTopics:           make([]*StudyPlanTopic, 0),
+		RecommendedQuestions: make([]*StudyPlanQuestion, 0),
+		Exercises:            make([]string, 0),
+	}
+}
+
+func (s *StudyPlan) SetSummary(summary string) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.Summary = summary
+	return s
+}
+
+func (s *StudyPlan) AppendWeakTopic(weakTopic *StudyPlanTopic) *StudyPlan {
+	if s == nil || weakTopic == nil {
+		return s
+	}
+	s.WeakTopics = append(s.WeakTopics, weakTopic)
+	return s
+}
+
+func (s *StudyPlan) AppendRecommendedQuestion(recommendedQuestion *StudyPlanQuestion) *StudyPlan {
+	if s == nil || recommendedQuestion == nil {
+		return s
+	}
+	s.RecommendedQuestions = append(s.RecommendedQuestions, recommendedQuestion)
+	return s
+}
+
+func (s *StudyPlan) SetExercises(exercises []string) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.Exercises = append([]string{}, exercises...)
+	return s
+}
+
+func (s *StudyPlan) SetReviewCount(reviewCount uint) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.ReviewCount = reviewCount
+	return s
+}
+
+func (s *StudyPlan) SetUpdateTimestampS(updateTimestampS int64) *StudyPlan {
+	if s == nil {
+		return nil
+	}
+	s.UpdateTimestampS = updateTimestampS
+	return s
+}
+
+type StudyPlanTopic struct {
+	Dimension    string  `json:"dimension"`
+	AverageScore float64 `json:"average_score"`
+	MaxScore     uint    `json:"max_score"`
+	Reason       string  `json:"reason"`
+}
+
+func NewStudyPlanTopic() *StudyPlanTopic {
+	return &StudyPlanTopic{}
+}
+
+func (s *StudyPlanTopic) SetDimension(dimension string) *StudyPlanTopic {
+	if s == nil {
+		return nil
+	}
+	s.Dimension = dimension
+	return s
+}
+
+func (s *StudyPlanTopic) SetAverageScore(averageScore float64) *StudyPlanTopic {
+	if s == nil {
+		return nil
+	}
+	s.AverageScore = averageScore
+	return s
+}
+
+func (s *StudyPlanTopic) SetMaxScore(maxScore uint) *StudyPlanTopic {
+	if s == nil {
+		return nil
+	}
+	s.MaxScore = maxScore
+	return s
+}
+
+func (s *StudyPlanTopic) SetReason(reason string) *StudyPlanTopic {
+	if s == nil {
+		return nil
+	}
+	s.Reason = reason
+	return s
+}
+
+type StudyPlanQuestion struct {
+	// This field uses the external ID of the question
+	QuestionID string `json:"question_id"`
+	Reason     string `json:"reason"`
+}
+
+func NewStudyPlanQuestion() *StudyPlanQuestion {
+	return &StudyPlanQuestion{}
+}
+
+func (s *StudyPlanQuestion) SetQuestionID(questionID string) *StudyPlanQuestion {
+	if s == nil {
+		return nil
+	}
+	s.QuestionID = questionID
+	return s
+}
+
+func (s *StudyPlanQuestion) SetReason(reason string) *StudyPlanQuestion {
+	if s == nil {
+		return nil
+	}
+	s.Reason = reason
+	return s
+}
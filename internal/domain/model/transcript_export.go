@@ -0,0 +1,163 @@
+package model
+
+type TranscriptExportFormat string
+
+const (
+	MARKDOWN_TRANSCRIPT_EXPORT TranscriptExportFormat = "markdown"
+	JSON_TRANSCRIPT_EXPORT     TranscriptExportFormat = "json"
+	VTT_TRANSCRIPT_EXPORT      TranscriptExportFormat = "vtt"
+)
+
+func (t TranscriptExportFormat) IsValid() bool {
+	switch t {
+	case MARKDOWN_TRANSCRIPT_EXPORT, JSON_TRANSCRIPT_EXPORT, VTT_TRANSCRIPT_EXPORT:
+		return true
+	}
+	return false
+}
+
+type TranscriptSpeaker string
+
+const (
+	INTERVIEWER_SPEAKER TranscriptSpeaker = "interviewer"
+	CANDIDATE_SPEAKER   TranscriptSpeaker = "candidate"
+)
+
+// TranscriptExport is also the body of the JSON export
+type TranscriptExport struct {
+	// This field uses the UUID of the interview
+	InterviewID string `json:"interview_id"`
+	// This field uses the external ID of the question
+	QuestionID      string            `json:"question_id"`
+	StartTimestampS int64             `json:"start_timestamp_s"`
+	EndTimestampS   int64             `json:"end_timestamp_s"`
+	Turns           []*TranscriptTurn `json:"turns"`
+	FinalCode       string            `json:"final_code"`
+}
+
+func NewTranscriptExport() *TranscriptExport {
+	return &TranscriptExport{
+		Turns: make([]*TranscriptTurn, 0),
+	}
+}
+
+func (t *TranscriptExport) SetInterviewID(interviewID string) *TranscriptExport {
+	if t == nil {
+		return nil
+	}
+	t.InterviewID = interviewID
+	return t
+}
+
+func (t *TranscriptExport) SetQuestionID(questionID string) *TranscriptExport {
+	if t == nil {
+		return nil
+	}
+	t.QuestionID = questionID
+	return t
+}
+
+func (t *TranscriptExport) SetStartTimestampS(startTimestampS int64) *TranscriptExport {
+	if t == nil {
+		return nil
+	}
+	t.StartTimestampS = startTimestampS
+	return t
+}
+
+func (t *TranscriptExport) SetEndTimestampS(endTimestampS int64) *TranscriptExport {
+	if t == nil {
+		return nil
+	}
+	t.EndTimestampS = endTimestampS
+	return t
+}
+
+func (t *TranscriptExport) AppendTurn(turn *TranscriptTurn) *TranscriptExport {
+	if t == nil || turn == nil {
+		return t
+	}
+	t.Turns = append(t.Turns, turn)
+	return t
+}
+
+func (t *TranscriptExport) SetFinalCode(finalCode string) *TranscriptExport {
+	if t == nil {
+		return nil
+	}
+	t.FinalCode = finalCode
+	return t
+}
+
+type TranscriptTurn struct {
+	// This field uses the UUID of the transcript
+	ID      string            `json:"id"`
+	Speaker TranscriptSpeaker `json:"speaker"`
+	Content string            `json:"content"`
+	// Relative to the start of the interview
+	OffsetMS int64  `json:"offset_ms"`
+	AudioURL string `json:"audio_url,omitempty"`
+	// Only set on candidate turns in the replay
+	Intent           string  `json:"intent,omitempty"`
+	IntentConfidence float64 `json:"intent_confidence,omitempty"`
+}
+
+func NewTranscriptTurn() *TranscriptTurn {
+	return &TranscriptTurn{}
+}
+
+func (t *TranscriptTurn) SetID(id string) *TranscriptTurn {
+	if t == nil {
+		return nil
+	}
+	t.ID = id
+	return t
+}
+
+func (t *TranscriptTurn) SetSpeaker(speaker TranscriptSpeaker) *TranscriptTurn {
+	if t == nil {
+		return nil
+	}
+	t.Speaker = speaker
+	return t
+}
+
+func (t *TranscriptTurn) SetContent(content string) *TranscriptTurn {
+	if t == nil {
+		return nil
+	}
+	t.Content = content
+	return t
+}
+
+func (t *TranscriptTurn) SetOffsetMS(offsetMS int64) *TranscriptTurn {
+	if t == nil {
+		return nil
+	}
+	t.OffsetMS = offsetMS
+	return t
+}
+
+func (t *TranscriptTurn) SetAudioURL(audioURL string) *TranscriptTurn {
+	if t == nil {
+		return nil
+	}
+	t.AudioURL = audioURL
+	return t
+}
+
+func (t *TranscriptTurn) SetIntent(intent string, confidence float64) *TranscriptTurn {
+	if t == nil {
+		return nil
+	}
+	t.Intent = intent
+	t.IntentConfidence = confidence
+	return t
+}
+
+// TranscriptFile is the rendered export that is sent back as a download
+type TranscriptFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
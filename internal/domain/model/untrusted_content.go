@@ -0,0 +1,21 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// The tags that the content from the candidate or the client is wrapped in before it reaches the LLM
+const (
+	CANDIDATE_SPEECH_TAG     string = "candidate_speech"
+	QUESTION_DESCRIPTION_TAG string = "question_description"
+	CANDIDATE_CODE_TAG       string = "candidate_code"
+)
+
+var untrustedContentTagPattern = regexp.MustCompile(fmt.Sprintf(`(?i)<\s*/?\s*(%s|%s|%s)\s*>`, CANDIDATE_SPEECH_TAG, QUESTION_DESCRIPTION_TAG, CANDIDATE_CODE_TAG))
+
+// Wraps the content so that the LLM can tell it apart from the instructions, the tags inside the content
+// are removed so that it cannot close the delimiter early
+func DelimitUntrustedContent(tag, content string) string {
+	return fmt.Sprintf("<%s>\n%s\n</%s>", tag, untrustedContentTagPattern.ReplaceAllString(content, ""), tag)
+}
@@ -10,12 +10,17 @@ const (
 )
 
 type WebSocketMessage struct {
-	From      Sender  `json:"from"`
-	Chunk     *string `json:"chunk"`
-	Code      *string `json:"code"`
-	URL       *string `json:"url"`
-	End       *bool   `json:"end"`
-	CloseConn bool
+	From  Sender  `json:"from"`
+	Chunk *string `json:"chunk"`
+	Code  *string `json:"code"`
+	URL   *string `json:"url"`
+	// Only set on server messages, see InterviewerResponse
+	Text         *string `json:"text"`
+	MessageID    *string `json:"message_id"`
+	Sequence     *uint   `json:"sequence"`
+	TranscriptID *string `json:"transcript_id"`
+	End          *bool   `json:"end"`
+	CloseConn    bool
 }
 
 func NewWebsocketMessage() *WebSocketMessage {
@@ -60,6 +65,54 @@ func (w *WebSocketMessage) SetURL(url string) *WebSocketMessage {
 	return w
 }
 
+func (w *WebSocketMessage) SetText(text string) *WebSocketMessage {
+	if w == nil {
+		return nil
+	}
+	w.Text = util.ToPtr(text)
+	return w
+}
+
+func (w *WebSocketMessage) SetMessageID(messageID string) *WebSocketMessage {
+	if w == nil {
+		return nil
+	}
+	w.MessageID = util.ToPtr(messageID)
+	return w
+}
+
+func (w *WebSocketMessage) SetSequence(sequence uint) *WebSocketMessage {
+	if w == nil {
+		return nil
+	}
+	w.Sequence = util.ToPtr(sequence)
+	return w
+}
+
+func (w *WebSocketMessage) SetTranscriptID(transcriptID string) *WebSocketMessage {
+	if w == nil {
+		return nil
+	}
+	w.TranscriptID = util.ToPtr(transcriptID)
+	return w
+}
+
+// Carries over everything of the reply that the client needs
+func (w *WebSocketMessage) SetInterviewerResponse(response *InterviewerResponse) *WebSocketMessage {
+	if w == nil || !response.Exists() {
+		return w
+	}
+	// There is no audio in text interviews
+	if response.URL != "" {
+		w.SetURL(response.URL)
+	}
+	return w.
+		SetText(response.Text).
+		SetMessageID(response.MessageID).
+		SetSequence(response.Sequence).
+		SetTranscriptID(response.TranscriptID)
+}
+
 func (w *WebSocketMessage) CloseConnection() *WebSocketMessage {
 	if w == nil {
 		return nil
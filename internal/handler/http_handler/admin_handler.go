@@ -0,0 +1,235 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/service"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type AdminHandler struct {
+	intentSampleService service.IntentSampleService
+	reviewService       service.ReviewService
+	experimentService   service.ExperimentService
+	questionService     service.QuestionService
+}
+
+func NewAdminHandler(
+	intentSampleService service.IntentSampleService,
+	reviewService service.ReviewService,
+	experimentService service.ExperimentService,
+	questionService service.QuestionService,
+) *AdminHandler {
+	return &AdminHandler{
+		intentSampleService: intentSampleService,
+		reviewService:       reviewService,
+		experimentService:   experimentService,
+		questionService:     questionService,
+	}
+}
+
+func (a *AdminHandler) ListLowConfidenceIntentSamples(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	maxConfidence := config.INTENT_SAMPLE_DEFAULT_MAX_CONFIDENCE
+	if maxConfidenceString := r.URL.Query().Get("max_confidence"); maxConfidenceString != "" {
+		if maxConfidenceValue, err := strconv.ParseFloat(maxConfidenceString, 64); err == nil && maxConfidenceValue >= 0 && maxConfidenceValue <= 100 {
+			maxConfidence = maxConfidenceValue
+		}
+	}
+
+	limit, offset := ParsePaginationParams(r)
+	samples, pagination, err := a.intentSampleService.ListLowConfidenceSamples(ctx, maxConfidence, limit, offset)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", samples)
+	payload.Add("pagination", pagination)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
+
+// An empty intent confirms the predicted label, otherwise the sample is relabeled
+func (a *AdminHandler) LabelIntentSample(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	request := &struct {
+		Intent string `json:"intent"`
+	}{}
+
+	err := ReadJSONHTTPReq(w, r, request)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	if err := a.intentSampleService.LabelSample(ctx, userID, r.PathValue("id"), model.Intent(request.Intent)); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusOK, nil)
+}
+
+func (a *AdminHandler) ReReviewInterview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := a.reviewService.EnqueueReReview(ctx, r.PathValue("id")); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusAccepted, nil)
+}
+
+// Re-reviews every attempt of the question, the id in the path is the external question ID
+func (a *AdminHandler) ReReviewQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	count, err := a.reviewService.EnqueueReReviewsForQuestion(ctx, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", map[string]uint{
+		"enqueued_count": count,
+	})
+
+	WriteJSONHTTP(w, payload, http.StatusAccepted, nil)
+}
+
+func (a *AdminHandler) ListReviewVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviewVersions, err := a.reviewService.ListReviewVersions(ctx, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", reviewVersions)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
+
+func (a *AdminHandler) SelectDisplayedReviewVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	request := &struct {
+		Version uint `json:"version"`
+	}{}
+
+	err := ReadJSONHTTPReq(w, r, request)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	if err := a.reviewService.SelectDisplayedReviewVersion(ctx, userID, r.PathValue("id"), request.Version); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusOK, nil)
+}
+
+func (a *AdminHandler) GetExperimentReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	report, err := a.experimentService.GetReport(ctx, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", report)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
+
+func (a *AdminHandler) CreateExperiment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	request := &model.ExperimentDefinition{}
+
+	err := ReadJSONHTTPReq(w, r, request)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	experimentID, err := a.experimentService.CreateExperiment(ctx, request)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", map[string]string{
+		"experiment_id": experimentID,
+	})
+
+	WriteJSONHTTP(w, payload, http.StatusCreated, nil)
+}
+
+func (a *AdminHandler) ActivateExperiment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := a.experimentService.ActivateExperiment(ctx, r.PathValue("id")); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusOK, nil)
+}
+
+func (a *AdminHandler) DeactivateExperiment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := a.experimentService.DeactivateExperiment(ctx, r.PathValue("id")); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusOK, nil)
+}
+
+// The id in the path is the external question ID
+func (a *AdminHandler) SetQuestionReferenceSolution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	request := &struct {
+		ReferenceSolution string `json:"reference_solution"`
+	}{}
+
+	err := ReadJSONHTTPReq(w, r, request)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	if err := a.questionService.SetReferenceSolution(ctx, r.PathValue("id"), request.ReferenceSolution); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusOK, nil)
+}
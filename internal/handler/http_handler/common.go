@@ -15,6 +15,7 @@ import (
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
 	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
 
 	"github.com/coder/websocket"
@@ -78,6 +79,22 @@ func WriteJSONHTTP(w http.ResponseWriter, payload util.JSONPayload, statusCode i
 	return nil
 }
 
+// Handles Range, If-Range, If-None-Match and If-Modified-Since, the content is only read for the ranges that are requested
+func ServeFileContentHTTP(w http.ResponseWriter, r *http.Request, fileContent *model.FileContent, maxAge time.Duration) {
+	if fileContent.ETag != "" {
+		w.Header().Set("ETag", fileContent.ETag)
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int64(max(maxAge, 0).Seconds())))
+	w.Header().Set(common.CONTENT_TYPE, fileContent.ContentType)
+
+	var lastModified time.Time
+	if fileContent.LastModifiedMS > 0 {
+		lastModified = time.UnixMilli(fileContent.LastModifiedMS)
+	}
+
+	http.ServeContent(w, r, "", lastModified, fileContent.Content)
+}
+
 func ReadJSONBytes(data []byte, dst any) error {
 	if dst == nil {
 		return fmt.Errorf("dst cannot be nil when calling readJSON for websockets: %w", common.ErrInternalServerError)
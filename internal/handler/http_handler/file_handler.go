@@ -0,0 +1,51 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/service"
+)
+
+type FileHandler struct {
+	fileService service.FileService
+}
+
+func NewFileHandler(
+	fileService service.FileService,
+) *FileHandler {
+	return &FileHandler{
+		fileService: fileService,
+	}
+}
+
+// The signature in the query authenticates the request, so that audio elements can load the file without a session header
+func (f *FileHandler) ServeSignedFile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	expiresAtS, err := strconv.ParseInt(query.Get(config.SIGNED_FILE_URL_EXPIRES_QUERY_KEY), 10, 64)
+	if err != nil {
+		HandleErrorResponseHTTP(w, fmt.Errorf("invalid expiry in signed url, %s: %w", err, common.ErrBadRequest))
+		return
+	}
+
+	location := model.NewFileLocation().
+		SetBucket(r.PathValue("bucket")).
+		SetKey(r.PathValue("key"))
+
+	fileContent, err := f.fileService.OpenSignedFile(ctx, location, expiresAtS, query.Get(config.SIGNED_FILE_URL_SIGNATURE_QUERY_KEY))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+	defer fileContent.Content.Close()
+
+	// The URL stops working when it expires, so it should not be cached for longer than that
+	ServeFileContentHTTP(w, r, fileContent, time.Until(time.Unix(expiresAtS, 0)))
+}
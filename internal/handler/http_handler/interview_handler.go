@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
@@ -18,23 +19,26 @@ import (
 )
 
 type InterviewHandler struct {
-	websocketConfig  *config.WebsocketConfig
-	authService      service.AuthService
-	interviewService service.InterviewService
-	logger           *zerolog.Logger
+	websocketConfig         *config.WebsocketConfig
+	authService             service.AuthService
+	interviewService        service.InterviewService
+	transcriptExportService service.TranscriptExportService
+	logger                  *zerolog.Logger
 }
 
 func NewInterviewHandler(
 	websocketConfig *config.WebsocketConfig,
 	authService service.AuthService,
 	interviewService service.InterviewService,
+	transcriptExportService service.TranscriptExportService,
 	logger *zerolog.Logger,
 ) *InterviewHandler {
 	return &InterviewHandler{
-		websocketConfig:  websocketConfig,
-		authService:      authService,
-		interviewService: interviewService,
-		logger:           logger,
+		websocketConfig:         websocketConfig,
+		authService:             authService,
+		interviewService:        interviewService,
+		transcriptExportService: transcriptExportService,
+		logger:                  logger,
 	}
 }
 
@@ -136,12 +140,102 @@ func (i *InterviewHandler) GetInterviewHistory(w http.ResponseWriter, r *http.Re
 	WriteJSONHTTP(w, payload, http.StatusOK, nil)
 }
 
+func (i *InterviewHandler) GetInterviewReplay(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	replay, err := i.interviewService.GetReplay(ctx, userID, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", replay)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
+
+// The format defaults to markdown, the transcript is sent back as a file download
+func (i *InterviewHandler) ExportTranscript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	format := model.MARKDOWN_TRANSCRIPT_EXPORT
+	if formatString := r.URL.Query().Get("format"); formatString != "" {
+		format = model.TranscriptExportFormat(formatString)
+	}
+
+	transcriptFile, err := i.transcriptExportService.ExportTranscript(ctx, userID, r.PathValue("id"), format)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	w.Header().Set(common.CONTENT_TYPE, transcriptFile.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", transcriptFile.FileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(transcriptFile.Content)
+}
+
+// Streams the audio through the backend so that the bucket is never exposed, range requests are
+// handled by http.ServeContent so that the audio can be seeked
+func (i *InterviewHandler) StreamTranscriptAudio(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	audio, err := i.interviewService.GetTranscriptAudio(ctx, userID, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+	defer audio.Content.Close()
+
+	ServeFileContentHTTP(w, r, audio, config.TRANSCRIPT_AUDIO_CACHE_MAX_AGE)
+}
+
+// The signature in the query authenticates the request, so that audio elements and exported files can load the audio without a session header
+func (i *InterviewHandler) StreamSignedTranscriptAudio(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	expiresAtS, err := strconv.ParseInt(query.Get(config.SIGNED_FILE_URL_EXPIRES_QUERY_KEY), 10, 64)
+	if err != nil {
+		HandleErrorResponseHTTP(w, fmt.Errorf("invalid expiry in signed url, %s: %w", err, common.ErrBadRequest))
+		return
+	}
+
+	audio, err := i.interviewService.GetSignedTranscriptAudio(ctx, r.PathValue("id"), expiresAtS, query.Get(config.SIGNED_FILE_URL_SIGNATURE_QUERY_KEY))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+	defer audio.Content.Close()
+
+	// The URL stops working when it expires, so it should not be cached for longer than that
+	ServeFileContentHTTP(w, r, audio, time.Until(time.Unix(expiresAtS, 0)))
+}
+
 // TODO: Don't allow user to set up new interview if there is too many abandoned interview since the last one
 func (i *InterviewHandler) SetUpNewInterview(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	request := &struct {
 		QuestionID  string `json:"question_id"`
 		Description string `json:"description"`
+		// Either "voice" or "text", a voice interview is set up when it is empty
+		Mode string `json:"mode"`
 	}{}
 
 	err := ReadJSONHTTPReq(w, r, request)
@@ -156,7 +250,7 @@ func (i *InterviewHandler) SetUpNewInterview(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	token, err := i.interviewService.SetUpNewInterviewForCandidate(ctx, userID, request.QuestionID, request.Description)
+	token, err := i.interviewService.SetUpNewInterviewForCandidate(ctx, userID, request.QuestionID, request.Description, entity.InterviewMode(request.Mode))
 	if err != nil {
 		HandleErrorResponseHTTP(w, err)
 		return
@@ -312,6 +406,10 @@ func (i *InterviewHandler) writePump(
 			payload := util.NewJSONPayload()
 			payload.Add("from", message.From)
 			payload.Add("url", message.URL)
+			payload.Add("text", message.Text)
+			payload.Add("message_id", message.MessageID)
+			payload.Add("sequence", message.Sequence)
+			payload.Add("transcript_id", message.TranscriptID)
 
 			if err := WriteJSONWebsocket(ctx, conn, payload); err != nil {
 				errChan <- err
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
 	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
 	httphandler "github.com/ahleongzc/leetcode-live-backend/internal/handler/http_handler"
 	"github.com/ahleongzc/leetcode-live-backend/internal/service"
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
@@ -135,6 +136,34 @@ func (m *Middleware) SetUserID(next http.Handler) http.Handler {
 	})
 }
 
+// Must be chained after SetUserID
+func (m *Middleware) RequireAdmin(next http.Handler) http.Handler {
+	return m.requireRole(next, entity.ADMIN_ROLE)
+}
+
+// Admins can do everything that reviewers can. Must be chained after SetUserID
+func (m *Middleware) RequireReviewer(next http.Handler) http.Handler {
+	return m.requireRole(next, entity.REVIEWER_ROLE, entity.ADMIN_ROLE)
+}
+
+func (m *Middleware) requireRole(next http.Handler, roles ...entity.UserRole) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userID, err := util.GetUserID(ctx)
+		if err != nil {
+			httphandler.HandleErrorResponseHTTP(w, err)
+			return
+		}
+
+		if err := m.authService.AuthorizeUserRole(ctx, userID, roles...); err != nil {
+			httphandler.HandleErrorResponseHTTP(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (m *Middleware) SetSessionTokenInResponseHeader(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -0,0 +1,143 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/service"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type ReviewerHandler struct {
+	reviewerService service.ReviewerService
+	reviewService   service.ReviewService
+}
+
+func NewReviewerHandler(
+	reviewerService service.ReviewerService,
+	reviewService service.ReviewService,
+) *ReviewerHandler {
+	return &ReviewerHandler{
+		reviewerService: reviewerService,
+		reviewService:   reviewService,
+	}
+}
+
+// The AI reviews are listed together with the overrides so that they can be compared
+func (re *ReviewerHandler) ListReviewVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviewVersions, err := re.reviewService.ListReviewVersions(ctx, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", reviewVersions)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
+
+func (re *ReviewerHandler) OverrideReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	request := &model.ReviewOverride{}
+
+	err := ReadJSONHTTPReq(w, r, request)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	if err := re.reviewerService.OverrideReview(ctx, userID, r.PathValue("id"), request); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusCreated, nil)
+}
+
+func (re *ReviewerHandler) AnnotateTranscript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	request := &struct {
+		Content string `json:"content"`
+	}{}
+
+	err := ReadJSONHTTPReq(w, r, request)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	annotationID, err := re.reviewerService.AnnotateTranscript(ctx, userID, r.PathValue("id"), r.PathValue("transcript_id"), request.Content)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", map[string]string{
+		"id": annotationID,
+	})
+
+	WriteJSONHTTP(w, payload, http.StatusCreated, nil)
+}
+
+func (re *ReviewerHandler) ListAnnotations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	annotations, err := re.reviewerService.ListAnnotations(ctx, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", annotations)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
+
+func (re *ReviewerHandler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	if err := re.reviewerService.DeleteAnnotation(ctx, userID, r.PathValue("id")); err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	WriteJSONHTTP(w, nil, http.StatusOK, nil)
+}
+
+func (re *ReviewerHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	auditLogs, err := re.reviewerService.ListAuditLogs(ctx, r.PathValue("id"))
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", auditLogs)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
@@ -8,14 +8,17 @@ import (
 )
 
 type UserHandler struct {
-	userService service.UserService
+	userService      service.UserService
+	studyPlanService service.StudyPlanService
 }
 
 func NewUserHandler(
 	userService service.UserService,
+	studyPlanService service.StudyPlanService,
 ) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:      userService,
+		studyPlanService: studyPlanService,
 	}
 }
 
@@ -60,3 +63,24 @@ func (u *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	WriteJSONHTTP(w, nil, http.StatusCreated, nil)
 }
+
+// The study plan is generated after the first interview of the user is reviewed
+func (u *UserHandler) GetStudyPlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := util.GetUserID(ctx)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	studyPlan, err := u.studyPlanService.GetStudyPlan(ctx, userID)
+	if err != nil {
+		HandleErrorResponseHTTP(w, err)
+		return
+	}
+
+	payload := util.NewJSONPayload()
+	payload.Add("data", studyPlan)
+
+	WriteJSONHTTP(w, payload, http.StatusOK, nil)
+}
@@ -3,10 +3,16 @@ package rpchandler
 import (
 	"context"
 	"io"
+	"sync"
+	"time"
 
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
 	"github.com/ahleongzc/leetcode-live-backend/internal/service"
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
 	"github.com/ahleongzc/leetcode-live-backend/pb"
+
+	"github.com/rs/zerolog/log"
 )
 
 func NewProxyHandler(
@@ -28,6 +34,10 @@ type ProxyHandler struct {
 // TODO: See how to terminate this stream when the server is terminated as stream.Recv is a blocking operation
 func (p *ProxyHandler) ProcessIncomingMessage(stream pb.InterviewProxy_ProcessIncomingMessageServer) error {
 	ctx := stream.Context()
+	opened := false
+	// The time warnings are sent from their own goroutines, so the buffer of the candidate and the stream
+	// are only written to while holding the lock
+	mu := &sync.Mutex{}
 
 	for {
 		in, err := stream.Recv()
@@ -38,32 +48,133 @@ func (p *ProxyHandler) ProcessIncomingMessage(stream pb.InterviewProxy_ProcessIn
 			return HandleErroResponseRPC(err)
 		}
 
-		res, err := p.interviewService.ProcessCandidateMessage(
-			ctx,
-			uint(in.GetInterviewId()),
-			in.GetChunk(),
-			in.GetCode(),
-		)
-		if err != nil {
-			return HandleErroResponseRPC(err)
+		interviewID := uint(in.GetInterviewId())
+
+		// The interview ID is only known from the messages, so the proxy sends one with only the interview ID right after joining
+		if !opened {
+			opened = true
+			p.openConversation(ctx, interviewID, stream, mu)
+			p.scheduleTimeWarnings(ctx, interviewID, stream, mu)
+
+			if in.GetChunk() == "" && in.GetCode() == "" {
+				continue
+			}
 		}
 
-		if !res.Exists() {
-			continue
+		if err := p.processCandidateMessage(ctx, interviewID, in, stream, mu); err != nil {
+			return HandleErroResponseRPC(err)
 		}
+	}
+}
+
+func (p *ProxyHandler) processCandidateMessage(ctx context.Context, interviewID uint, in *pb.InterviewMessage, stream pb.InterviewProxy_ProcessIncomingMessageServer, mu *sync.Mutex) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	res, err := p.interviewService.ProcessCandidateMessage(
+		ctx,
+		interviewID,
+		in.GetChunk(),
+		in.GetCode(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if !res.Exists() {
+		return nil
+	}
+
+	return stream.Send(p.toInterviewMessage(res))
+}
+
+// The interview goes on without the opening, the candidate can still start talking
+func (p *ProxyHandler) openConversation(ctx context.Context, interviewID uint, stream pb.InterviewProxy_ProcessIncomingMessageServer, mu *sync.Mutex) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	responses, err := p.interviewService.OpenConversation(ctx, interviewID)
+	if err != nil {
+		log.Error().Err(err).Uint("interview_id", interviewID).Msg("unable to open the conversation")
+		return
+	}
 
-		out := &pb.InterviewMessage{
-			Source: pb.Source_SERVER,
-			Url:    util.ToPtr(res.URL),
-			End:    res.End,
+	for _, res := range responses {
+		if err := stream.Send(p.toInterviewMessage(res)); err != nil {
+			log.Error().Err(err).Uint("interview_id", interviewID).Msg("unable to send the opening")
+			return
 		}
+	}
+}
 
-		if err := stream.Send(out); err != nil {
-			return HandleErroResponseRPC(err)
+// The timers stop with the stream, a resumed interview schedules the warnings that are still ahead of it
+func (p *ProxyHandler) scheduleTimeWarnings(ctx context.Context, interviewID uint, stream pb.InterviewProxy_ProcessIncomingMessageServer, mu *sync.Mutex) {
+	timeRemainingS, err := p.interviewService.GetTimeRemainingS(ctx, interviewID)
+	if err != nil {
+		log.Error().Err(err).Uint("interview_id", interviewID).Msg("unable to schedule time warnings")
+		return
+	}
+
+	for _, minutesRemaining := range config.INTERVIEW_TIME_WARNING_MINUTES {
+		warningS := minutesRemaining * 60
+		if timeRemainingS <= warningS {
+			continue
 		}
+		go p.sendTimeWarning(ctx, interviewID, timeRemainingS-warningS, minutesRemaining, stream, mu)
 	}
 }
 
+// A warning that cannot be delivered is only logged because the interview can carry on without it
+func (p *ProxyHandler) sendTimeWarning(
+	ctx context.Context,
+	interviewID uint,
+	delayS uint,
+	minutesRemaining uint,
+	stream pb.InterviewProxy_ProcessIncomingMessageServer,
+	mu *sync.Mutex,
+) {
+	t := time.NewTimer(time.Duration(delayS) * time.Second)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-t.C:
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	res, err := p.interviewService.HandleInterviewTimeWarning(ctx, interviewID, minutesRemaining)
+	if err == nil {
+		err = stream.Send(p.toInterviewMessage(res))
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Uint("interview_id", interviewID).
+			Uint("minutes_remaining", minutesRemaining).
+			Msg("unable to send time warning")
+	}
+}
+
+func (p *ProxyHandler) toInterviewMessage(res *model.InterviewerResponse) *pb.InterviewMessage {
+	out := &pb.InterviewMessage{
+		Source:       pb.Source_SERVER,
+		Text:         util.ToPtr(res.Text),
+		MessageId:    util.ToPtr(res.MessageID),
+		Sequence:     util.ToPtr(uint64(res.Sequence)),
+		TranscriptId: util.ToPtr(res.TranscriptID),
+		End:          res.End,
+	}
+
+	if res.URL != "" {
+		out.Url = util.ToPtr(res.URL)
+	}
+
+	return out
+}
+
 func (p *ProxyHandler) VerifyCandidate(ctx context.Context, req *pb.VerifyCandidateRequest) (*pb.VerificationResponse, error) {
 	token := req.GetToken()
 	if token == "" {
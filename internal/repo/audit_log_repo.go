@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepo interface {
+	Create(ctx context.Context, auditLog *entity.AuditLog) error
+	// Latest first
+	ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.AuditLog, error)
+}
+
+func NewAuditLogRepo(
+	db *gorm.DB,
+) AuditLogRepo {
+	return &AuditLogRepoImpl{
+		db: db,
+	}
+}
+
+type AuditLogRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements AuditLogRepo.
+func (a *AuditLogRepoImpl) Create(ctx context.Context, auditLog *entity.AuditLog) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, a.db).WithContext(ctx).Create(auditLog).Error; err != nil {
+		return fmt.Errorf("unable to create new audit log, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// ListByInterviewID implements AuditLogRepo.
+func (a *AuditLogRepoImpl) ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.AuditLog, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var auditLogs []*entity.AuditLog
+	if err := getDB(ctx, a.db).WithContext(ctx).
+		Where("interview_id = ?", interviewID).
+		Order("create_timestamp_ms DESC").
+		Find(&auditLogs).Error; err != nil {
+		return nil, fmt.Errorf("unable to list audit logs for interview id %d, %s: %w", interviewID, err, common.ErrInternalServerError)
+	}
+
+	return auditLogs, nil
+}
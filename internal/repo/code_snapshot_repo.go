@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type CodeSnapshotRepo interface {
+	Create(ctx context.Context, codeSnapshot *entity.CodeSnapshot) error
+	GetLatestByInterviewID(ctx context.Context, interviewID uint) (*entity.CodeSnapshot, error)
+	ListByInterviewIDAsc(ctx context.Context, interviewID uint) ([]*entity.CodeSnapshot, error)
+}
+
+func NewCodeSnapshotRepo(
+	db *gorm.DB,
+) CodeSnapshotRepo {
+	return &CodeSnapshotRepoImpl{
+		db: db,
+	}
+}
+
+type CodeSnapshotRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements CodeSnapshotRepo.
+func (c *CodeSnapshotRepoImpl) Create(ctx context.Context, codeSnapshot *entity.CodeSnapshot) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, c.db).WithContext(ctx).Create(codeSnapshot).Error; err != nil {
+		return fmt.Errorf("unable to create new code snapshot, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetLatestByInterviewID implements CodeSnapshotRepo.
+func (c *CodeSnapshotRepoImpl) GetLatestByInterviewID(ctx context.Context, interviewID uint) (*entity.CodeSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	codeSnapshot := &entity.CodeSnapshot{}
+	if err := getDB(ctx, c.db).WithContext(ctx).
+		Where("interview_id = ?", interviewID).
+		Order("create_timestamp_ms DESC").
+		First(codeSnapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("code snapshot not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get latest code snapshot for interview id %d, %s: %w", interviewID, err, common.ErrInternalServerError)
+	}
+
+	return codeSnapshot, nil
+}
+
+// ListByInterviewIDAsc implements CodeSnapshotRepo.
+func (c *CodeSnapshotRepoImpl) ListByInterviewIDAsc(ctx context.Context, interviewID uint) ([]*entity.CodeSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var codeSnapshots []*entity.CodeSnapshot
+	if err := getDB(ctx, c.db).WithContext(ctx).
+		Where("interview_id = ?", interviewID).
+		Order("create_timestamp_ms ASC").
+		Find(&codeSnapshots).Error; err != nil {
+		return nil, fmt.Errorf("unable to list code snapshots for interview id %d, %s: %w", interviewID, err, common.ErrInternalServerError)
+	}
+
+	return codeSnapshots, nil
+}
@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type ExperimentRepo interface {
+	Create(ctx context.Context, experiment *entity.Experiment) error
+	Update(ctx context.Context, experiment *entity.Experiment) error
+	// Deactivates every active experiment other than the given one
+	DeactivateOthers(ctx context.Context, experimentID uint) error
+	GetByUUID(ctx context.Context, uuid string) (*entity.Experiment, error)
+	GetByName(ctx context.Context, name string) (*entity.Experiment, error)
+	// Returns the latest active experiment
+	GetActive(ctx context.Context) (*entity.Experiment, error)
+}
+
+func NewExperimentRepo(
+	db *gorm.DB,
+) ExperimentRepo {
+	return &ExperimentRepoImpl{
+		db: db,
+	}
+}
+
+type ExperimentRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements ExperimentRepo.
+func (e *ExperimentRepoImpl) Create(ctx context.Context, experiment *entity.Experiment) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, e.db).WithContext(ctx).Create(experiment).Error; err != nil {
+		return fmt.Errorf("unable to create new experiment, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// Update implements ExperimentRepo.
+func (e *ExperimentRepoImpl) Update(ctx context.Context, experiment *entity.Experiment) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, e.db).WithContext(ctx).Save(experiment).Error; err != nil {
+		return fmt.Errorf("unable to update experiment with id %d, %s: %w", experiment.ID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// DeactivateOthers implements ExperimentRepo.
+func (e *ExperimentRepoImpl) DeactivateOthers(ctx context.Context, experimentID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, e.db).WithContext(ctx).
+		Model(&entity.Experiment{}).
+		Where("active = ? AND id <> ?", true, experimentID).
+		Update("active", false).Error; err != nil {
+		return fmt.Errorf("unable to deactivate the experiments other than id %d, %s: %w", experimentID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetByUUID implements ExperimentRepo.
+func (e *ExperimentRepoImpl) GetByUUID(ctx context.Context, uuid string) (*entity.Experiment, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	experiment := &entity.Experiment{}
+	if err := getDB(ctx, e.db).WithContext(ctx).
+		Where("uuid = ?", uuid).
+		First(experiment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("experiment not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get experiment with uuid %s, %s: %w", uuid, err, common.ErrInternalServerError)
+	}
+
+	return experiment, nil
+}
+
+// GetByName implements ExperimentRepo.
+func (e *ExperimentRepoImpl) GetByName(ctx context.Context, name string) (*entity.Experiment, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	experiment := &entity.Experiment{}
+	if err := getDB(ctx, e.db).WithContext(ctx).
+		Where("name = ?", name).
+		First(experiment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("experiment not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get experiment with name %s, %s: %w", name, err, common.ErrInternalServerError)
+	}
+
+	return experiment, nil
+}
+
+// GetActive implements ExperimentRepo.
+func (e *ExperimentRepoImpl) GetActive(ctx context.Context) (*entity.Experiment, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	experiment := &entity.Experiment{}
+	if err := getDB(ctx, e.db).WithContext(ctx).
+		Where("active = ?", true).
+		Order("id DESC").
+		First(experiment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("no active experiment: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get active experiment, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return experiment, nil
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type ExperimentVariantRepo interface {
+	Create(ctx context.Context, experimentVariant *entity.ExperimentVariant) error
+	GetByID(ctx context.Context, id uint) (*entity.ExperimentVariant, error)
+	// Ordered by ID so that the assignment of a user stays the same between calls
+	ListByExperimentID(ctx context.Context, experimentID uint) ([]*entity.ExperimentVariant, error)
+}
+
+func NewExperimentVariantRepo(
+	db *gorm.DB,
+) ExperimentVariantRepo {
+	return &ExperimentVariantRepoImpl{
+		db: db,
+	}
+}
+
+type ExperimentVariantRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements ExperimentVariantRepo.
+func (e *ExperimentVariantRepoImpl) Create(ctx context.Context, experimentVariant *entity.ExperimentVariant) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, e.db).WithContext(ctx).Create(experimentVariant).Error; err != nil {
+		return fmt.Errorf("unable to create new experiment variant, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetByID implements ExperimentVariantRepo.
+func (e *ExperimentVariantRepoImpl) GetByID(ctx context.Context, id uint) (*entity.ExperimentVariant, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	experimentVariant := &entity.ExperimentVariant{}
+	if err := getDB(ctx, e.db).WithContext(ctx).First(experimentVariant, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("experiment variant not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get experiment variant with id %d, %s: %w", id, err, common.ErrInternalServerError)
+	}
+
+	return experimentVariant, nil
+}
+
+// ListByExperimentID implements ExperimentVariantRepo.
+func (e *ExperimentVariantRepoImpl) ListByExperimentID(ctx context.Context, experimentID uint) ([]*entity.ExperimentVariant, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	experimentVariants := make([]*entity.ExperimentVariant, 0)
+	if err := getDB(ctx, e.db).WithContext(ctx).
+		Where("experiment_id = ?", experimentID).
+		Order("id ASC").
+		Find(&experimentVariants).Error; err != nil {
+		return nil, fmt.Errorf("unable to list variants for experiment id %d, %s: %w", experimentID, err, common.ErrInternalServerError)
+	}
+
+	return experimentVariants, nil
+}
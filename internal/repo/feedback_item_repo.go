@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type FeedbackItemRepo interface {
+	CreateBatch(ctx context.Context, feedbackItems []*entity.FeedbackItem) error
+	ListByReviewID(ctx context.Context, reviewID uint) ([]*entity.FeedbackItem, error)
+	ListByReviewIDs(ctx context.Context, reviewIDs []uint) ([]*entity.FeedbackItem, error)
+	DeleteByReviewID(ctx context.Context, reviewID uint) error
+}
+
+func NewFeedbackItemRepo(
+	db *gorm.DB,
+) FeedbackItemRepo {
+	return &FeedbackItemRepoImpl{
+		db: db,
+	}
+}
+
+type FeedbackItemRepoImpl struct {
+	db *gorm.DB
+}
+
+// CreateBatch implements FeedbackItemRepo.
+func (f *FeedbackItemRepoImpl) CreateBatch(ctx context.Context, feedbackItems []*entity.FeedbackItem) error {
+	if len(feedbackItems) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, f.db).WithContext(ctx).Create(feedbackItems).Error; err != nil {
+		return fmt.Errorf("unable to create feedback items, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// ListByReviewID implements FeedbackItemRepo.
+func (f *FeedbackItemRepoImpl) ListByReviewID(ctx context.Context, reviewID uint) ([]*entity.FeedbackItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var feedbackItems []*entity.FeedbackItem
+	if err := getDB(ctx, f.db).WithContext(ctx).
+		Where("review_id = ?", reviewID).
+		Order("id ASC").
+		Find(&feedbackItems).Error; err != nil {
+		return nil, fmt.Errorf("unable to list feedback items for review id %d, %s: %w", reviewID, err, common.ErrInternalServerError)
+	}
+
+	return feedbackItems, nil
+}
+
+// ListByReviewIDs implements FeedbackItemRepo.
+func (f *FeedbackItemRepoImpl) ListByReviewIDs(ctx context.Context, reviewIDs []uint) ([]*entity.FeedbackItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	feedbackItems := make([]*entity.FeedbackItem, 0)
+	if len(reviewIDs) == 0 {
+		return feedbackItems, nil
+	}
+
+	if err := getDB(ctx, f.db).WithContext(ctx).
+		Where("review_id IN ?", reviewIDs).
+		Order("id ASC").
+		Find(&feedbackItems).Error; err != nil {
+		return nil, fmt.Errorf("unable to list feedback items for review ids %v, %s: %w", reviewIDs, err, common.ErrInternalServerError)
+	}
+
+	return feedbackItems, nil
+}
+
+// DeleteByReviewID implements FeedbackItemRepo.
+func (f *FeedbackItemRepoImpl) DeleteByReviewID(ctx context.Context, reviewID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, f.db).WithContext(ctx).
+		Where("review_id = ?", reviewID).
+		Delete(&entity.FeedbackItem{}).Error; err != nil {
+		return fmt.Errorf("unable to delete feedback items for review id %d, %s: %w", reviewID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
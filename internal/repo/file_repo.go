@@ -4,57 +4,87 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
 	"github.com/ahleongzc/leetcode-live-backend/internal/config"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo/cloudflare"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
 )
 
 type FileRepo interface {
-	Upload(ctx context.Context, name string, content io.Reader, metadata map[string]any) (string, error)
+	Upload(ctx context.Context, name string, content io.Reader, metadata map[string]any) (*model.FileLocation, error)
+	// The URL expires after common.PRESIGNED_URL_EXPIRY_DURATION, so it should be generated on every read
+	GetPresignedURL(ctx context.Context, location *model.FileLocation) (string, error)
+	// Works for both virtual hosted and path style URLs that were presigned by this repo
+	GetLocationFromURL(rawURL string) (*model.FileLocation, error)
+	// Nothing is downloaded until the content is read, and only from the offset that was seeked to
+	Open(ctx context.Context, location *model.FileLocation) (*model.FileContent, error)
 }
 
 func NewFileRepo(
-	client *s3.Client,
 	objectStorageConfig *config.ObjectStorageConfig,
-) FileRepo {
-	return &FileRepoImpl{
-		client:        client,
-		presignClient: s3.NewPresignClient(client),
-		bucketName:    objectStorageConfig.BucketName,
+) (FileRepo, error) {
+	switch objectStorageConfig.Provider {
+	case config.R2_OBJECT_STORAGE_PROVIDER:
+		client, err := cloudflare.NewCloudflareR2ObjectStorageClient(objectStorageConfig)
+		if err != nil {
+			return nil, err
+		}
+		return NewR2FileRepo(client, objectStorageConfig), nil
+	case config.LOCAL_OBJECT_STORAGE_PROVIDER:
+		return NewLocalFileRepo(objectStorageConfig)
+	case config.MEMORY_OBJECT_STORAGE_PROVIDER:
+		return NewInMemoryFileRepo(objectStorageConfig), nil
+	default:
+		return nil, fmt.Errorf("unsupported object storage provider %s: %w", objectStorageConfig.Provider, common.ErrInternalServerError)
 	}
 }
 
-type FileRepoImpl struct {
-	client        *s3.Client
-	presignClient *s3.PresignClient
-	bucketName    string
+// Signs the URLs of the backends that serve their files through config.SIGNED_FILE_URL_PATH
+type fileURLSigner struct {
+	signingSecret string
+	publicBaseURL string
 }
 
-func (f *FileRepoImpl) Upload(ctx context.Context, fileName string, content io.Reader, metadata map[string]any) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.FILE_UPLOAD_TIMEOUT)
-	defer cancel()
+func (f *fileURLSigner) sign(location *model.FileLocation) (string, error) {
+	expiresAtS := time.Now().Add(common.PRESIGNED_URL_EXPIRY_DURATION).Unix()
 
-	_, err := f.client.PutObject(
-		ctx, &s3.PutObjectInput{
-			Bucket: aws.String(f.bucketName),
-			Key:    aws.String(fileName),
-			Body:   content,
-		})
+	signedURL, err := url.JoinPath(f.publicBaseURL, config.SIGNED_FILE_URL_PATH, location.Bucket, location.Key)
 	if err != nil {
-		return "", fmt.Errorf("unable to upload file to object storage, %s: %w", err, common.ErrInternalServerError)
+		return "", fmt.Errorf("unable to build signed url, %s: %w", err, common.ErrInternalServerError)
 	}
 
-	res, err := f.presignClient.PresignGetObject(
-		ctx, &s3.GetObjectInput{
-			Bucket: aws.String(f.bucketName),
-			Key:    aws.String(fileName),
-		}, s3.WithPresignExpires(common.PRESIGNED_URL_EXPIRY_DURATION))
+	query := url.Values{}
+	query.Set(config.SIGNED_FILE_URL_EXPIRES_QUERY_KEY, strconv.FormatInt(expiresAtS, 10))
+	query.Set(config.SIGNED_FILE_URL_SIGNATURE_QUERY_KEY, util.SignHMAC(f.signingSecret, location.GetSigningPayload(expiresAtS)))
+
+	return signedURL + "?" + query.Encode(), nil
+}
+
+func (f *fileURLSigner) getLocation(rawURL string) (*model.FileLocation, error) {
+	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
-		return "", fmt.Errorf("unable to generate presigned url, %s: %w", err, common.ErrInternalServerError)
+		return nil, fmt.Errorf("unable to parse file url, %s: %w", err, common.ErrInternalServerError)
 	}
 
-	return res.URL, nil
+	path, found := strings.CutPrefix(parsedURL.Path, config.SIGNED_FILE_URL_PATH+"/")
+	if !found {
+		return nil, fmt.Errorf("file url %s is not a signed file url: %w", parsedURL.Redacted(), common.ErrInternalServerError)
+	}
+
+	bucket, key, found := strings.Cut(path, "/")
+	if !found || key == "" {
+		return nil, fmt.Errorf("file url %s does not contain a file name: %w", parsedURL.Redacted(), common.ErrInternalServerError)
+	}
+
+	location := model.NewFileLocation().
+		SetBucket(bucket).
+		SetKey(key)
+
+	return location, nil
 }
@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type GuardActivationRepo interface {
+	Create(ctx context.Context, guardActivation *entity.GuardActivation) error
+	ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.GuardActivation, error)
+}
+
+func NewGuardActivationRepo(
+	db *gorm.DB,
+) GuardActivationRepo {
+	return &GuardActivationRepoImpl{
+		db: db,
+	}
+}
+
+type GuardActivationRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements GuardActivationRepo.
+func (g *GuardActivationRepoImpl) Create(ctx context.Context, guardActivation *entity.GuardActivation) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, g.db).WithContext(ctx).Create(guardActivation).Error; err != nil {
+		return fmt.Errorf("unable to create guard activation for interview id %d, %s: %w", guardActivation.InterviewID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// ListByInterviewID implements GuardActivationRepo.
+func (g *GuardActivationRepoImpl) ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.GuardActivation, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var guardActivations []*entity.GuardActivation
+	if err := getDB(ctx, g.db).WithContext(ctx).
+		Where("interview_id = ?", interviewID).
+		Order("id ASC").
+		Find(&guardActivations).Error; err != nil {
+		return nil, fmt.Errorf("unable to list guard activations for interview id %d, %s: %w", interviewID, err, common.ErrInternalServerError)
+	}
+
+	return guardActivations, nil
+}
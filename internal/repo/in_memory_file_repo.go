@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+)
+
+// Keeps the files in memory for tests and local development, they are lost when the process exits
+func NewInMemoryFileRepo(
+	objectStorageConfig *config.ObjectStorageConfig,
+) FileRepo {
+	return &InMemoryFileRepoImpl{
+		bucketName: objectStorageConfig.BucketName,
+		signer: &fileURLSigner{
+			signingSecret: objectStorageConfig.SigningSecret,
+			publicBaseURL: objectStorageConfig.PublicBaseURL,
+		},
+		files: make(map[model.FileLocation]*inMemoryFile),
+	}
+}
+
+type InMemoryFileRepoImpl struct {
+	bucketName string
+	signer     *fileURLSigner
+	mu         sync.RWMutex
+	files      map[model.FileLocation]*inMemoryFile
+}
+
+type inMemoryFile struct {
+	content        []byte
+	lastModifiedMS int64
+	etag           string
+}
+
+type inMemoryFileReader struct {
+	*bytes.Reader
+}
+
+func (i *inMemoryFileReader) Close() error {
+	return nil
+}
+
+// Upload implements FileRepo.
+func (i *InMemoryFileRepoImpl) Upload(ctx context.Context, fileName string, content io.Reader, metadata map[string]any) (*model.FileLocation, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %s, %s: %w", fileName, err, common.ErrInternalServerError)
+	}
+
+	location := model.NewFileLocation().
+		SetBucket(i.bucketName).
+		SetKey(fileName)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.files[*location] = &inMemoryFile{
+		content:        data,
+		lastModifiedMS: time.Now().UnixMilli(),
+		etag:           fmt.Sprintf(`"%x"`, sha256.Sum256(data)),
+	}
+
+	return location, nil
+}
+
+// GetPresignedURL implements FileRepo.
+func (i *InMemoryFileRepoImpl) GetPresignedURL(ctx context.Context, location *model.FileLocation) (string, error) {
+	if !location.Exists() {
+		return "", fmt.Errorf("file location cannot be empty when presigning: %w", common.ErrInternalServerError)
+	}
+
+	return i.signer.sign(location)
+}
+
+// GetLocationFromURL implements FileRepo.
+func (i *InMemoryFileRepoImpl) GetLocationFromURL(rawURL string) (*model.FileLocation, error) {
+	return i.signer.getLocation(rawURL)
+}
+
+// Open implements FileRepo.
+func (i *InMemoryFileRepoImpl) Open(ctx context.Context, location *model.FileLocation) (*model.FileContent, error) {
+	if !location.Exists() {
+		return nil, fmt.Errorf("file location cannot be empty when opening: %w", common.ErrInternalServerError)
+	}
+
+	key := *location
+	if key.Bucket == "" {
+		key.Bucket = i.bucketName
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	file, ok := i.files[key]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found: %w", location.Key, common.ErrNotFound)
+	}
+
+	return &model.FileContent{
+		Content:        &inMemoryFileReader{Reader: bytes.NewReader(file.content)},
+		ContentType:    getContentType(location.Key),
+		Size:           int64(len(file.content)),
+		LastModifiedMS: file.lastModifiedMS,
+		ETag:           file.etag,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+)
+
+func newTestInMemoryFileRepo() FileRepo {
+	return NewInMemoryFileRepo(&config.ObjectStorageConfig{
+		Provider:      config.MEMORY_OBJECT_STORAGE_PROVIDER,
+		BucketName:    "test-bucket",
+		SigningSecret: "test-secret",
+		PublicBaseURL: "http://localhost:8080",
+	})
+}
+
+func TestInMemoryFileRepoUploadAndOpen(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newTestInMemoryFileRepo()
+
+	location, err := fileRepo.Upload(ctx, "audio/reply.mp3", strings.NewReader("hello"), nil)
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	if location.Bucket != "test-bucket" || location.Key != "audio/reply.mp3" {
+		t.Fatalf("unexpected location %+v", location)
+	}
+
+	fileContent, err := fileRepo.Open(ctx, location)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer fileContent.Content.Close()
+
+	data, err := io.ReadAll(fileContent.Content)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if string(data) != "hello" {
+		t.Errorf("content = %q, want %q", data, "hello")
+	}
+	if fileContent.Size != 5 {
+		t.Errorf("size = %d, want 5", fileContent.Size)
+	}
+	if fileContent.ETag == "" {
+		t.Error("etag is empty")
+	}
+}
+
+func TestInMemoryFileRepoOpenWithoutBucket(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newTestInMemoryFileRepo()
+
+	if _, err := fileRepo.Upload(ctx, "reply.mp3", strings.NewReader("hello"), nil); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	location := model.NewFileLocation().SetKey("reply.mp3")
+	if _, err := fileRepo.Open(ctx, location); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+}
+
+func TestInMemoryFileRepoOpenMissingFile(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newTestInMemoryFileRepo()
+
+	location := model.NewFileLocation().
+		SetBucket("test-bucket").
+		SetKey("missing.mp3")
+
+	_, err := fileRepo.Open(ctx, location)
+	if !errors.Is(err, common.ErrNotFound) {
+		t.Fatalf("err = %v, want %v", err, common.ErrNotFound)
+	}
+}
+
+func TestInMemoryFileRepoPresignedURLRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := newTestInMemoryFileRepo()
+
+	location, err := fileRepo.Upload(ctx, "audio/reply.mp3", strings.NewReader("hello"), nil)
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	presignedURL, err := fileRepo.GetPresignedURL(ctx, location)
+	if err != nil {
+		t.Fatalf("presign: %v", err)
+	}
+
+	if !strings.HasPrefix(presignedURL, "http://localhost:8080"+config.SIGNED_FILE_URL_PATH+"/test-bucket/audio/reply.mp3?") {
+		t.Fatalf("unexpected presigned url %s", presignedURL)
+	}
+
+	parsedLocation, err := fileRepo.GetLocationFromURL(presignedURL)
+	if err != nil {
+		t.Fatalf("get location: %v", err)
+	}
+
+	if *parsedLocation != *location {
+		t.Errorf("location = %+v, want %+v", parsedLocation, location)
+	}
+}
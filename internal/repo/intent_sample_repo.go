@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type IntentSampleRepo interface {
+	Create(ctx context.Context, sample *entity.IntentSample) (uint, error)
+	Update(ctx context.Context, sample *entity.IntentSample) error
+	GetByUUID(ctx context.Context, uuid string) (*entity.IntentSample, error)
+	// Lowest confidence first, these are the samples that benefit the most from a human label
+	ListUnlabeledBelowConfidence(ctx context.Context, maxConfidence float64, limit, offset uint) ([]*entity.IntentSample, uint, error)
+	ListLabeledUnexported(ctx context.Context) ([]*entity.IntentSample, error)
+}
+
+func NewIntentSampleRepo(
+	db *gorm.DB,
+) IntentSampleRepo {
+	return &IntentSampleRepoImpl{
+		db: db,
+	}
+}
+
+type IntentSampleRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements IntentSampleRepo.
+func (i *IntentSampleRepoImpl) Create(ctx context.Context, sample *entity.IntentSample) (uint, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, i.db).WithContext(ctx).Create(sample).Error; err != nil {
+		return 0, fmt.Errorf("unable to create new intent sample, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return sample.ID, nil
+}
+
+// Update implements IntentSampleRepo.
+func (i *IntentSampleRepoImpl) Update(ctx context.Context, sample *entity.IntentSample) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, i.db).WithContext(ctx).Save(sample).Error; err != nil {
+		return fmt.Errorf("unable to update intent sample with id %d: %w", sample.ID, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetByUUID implements IntentSampleRepo.
+func (i *IntentSampleRepoImpl) GetByUUID(ctx context.Context, uuid string) (*entity.IntentSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	sample := &entity.IntentSample{}
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Where("uuid = ?", uuid).
+		First(sample).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("intent sample not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get intent sample with uuid %s, %s: %w", uuid, err, common.ErrInternalServerError)
+	}
+
+	return sample, nil
+}
+
+// ListUnlabeledBelowConfidence implements IntentSampleRepo.
+func (i *IntentSampleRepoImpl) ListUnlabeledBelowConfidence(ctx context.Context, maxConfidence float64, limit, offset uint) ([]*entity.IntentSample, uint, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var samples []*entity.IntentSample
+	var total int64
+
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Model(&entity.IntentSample{}).
+		Where("labeled_intent IS NULL AND confidence <= ?", maxConfidence).
+		Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("unable to count unlabeled intent samples, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Where("labeled_intent IS NULL AND confidence <= ?", maxConfidence).
+		Order("confidence ASC").
+		Limit(int(limit)).
+		Offset(int(offset)).
+		Find(&samples).Error; err != nil {
+		return nil, 0, fmt.Errorf("unable to list unlabeled intent samples, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return samples, uint(total), nil
+}
+
+// ListLabeledUnexported implements IntentSampleRepo.
+func (i *IntentSampleRepoImpl) ListLabeledUnexported(ctx context.Context) ([]*entity.IntentSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var samples []*entity.IntentSample
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Where("labeled_intent IS NOT NULL AND exported_timestamp_ms IS NULL").
+		Order("labeled_timestamp_ms ASC").
+		Find(&samples).Error; err != nil {
+		return nil, fmt.Errorf("unable to list labeled intent samples, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return samples, nil
+}
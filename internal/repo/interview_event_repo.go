@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type InterviewEventRepo interface {
+	Create(ctx context.Context, interviewEvent *entity.InterviewEvent) error
+	ListByInterviewIDAsc(ctx context.Context, interviewID uint) ([]*entity.InterviewEvent, error)
+}
+
+func NewInterviewEventRepo(
+	db *gorm.DB,
+) InterviewEventRepo {
+	return &InterviewEventRepoImpl{
+		db: db,
+	}
+}
+
+type InterviewEventRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements InterviewEventRepo.
+func (i *InterviewEventRepoImpl) Create(ctx context.Context, interviewEvent *entity.InterviewEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, i.db).WithContext(ctx).Create(interviewEvent).Error; err != nil {
+		return fmt.Errorf("unable to create new interview event, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// ListByInterviewIDAsc implements InterviewEventRepo.
+func (i *InterviewEventRepoImpl) ListByInterviewIDAsc(ctx context.Context, interviewID uint) ([]*entity.InterviewEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var interviewEvents []*entity.InterviewEvent
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Where("interview_id = ?", interviewID).
+		Order("create_timestamp_ms ASC").
+		Find(&interviewEvents).Error; err != nil {
+		return nil, fmt.Errorf("unable to list interview events for interview id %d, %s: %w", interviewID, err, common.ErrInternalServerError)
+	}
+
+	return interviewEvents, nil
+}
@@ -16,11 +16,15 @@ type InterviewRepo interface {
 	Update(ctx context.Context, interview *entity.Interview) error
 	GetByToken(ctx context.Context, token string) (*entity.Interview, error)
 	GetByID(ctx context.Context, id uint) (*entity.Interview, error)
+	GetByUUID(ctx context.Context, uuid string) (*entity.Interview, error)
 	GetUnfinishedInterviewByUserID(ctx context.Context, userID uint) (*entity.Interview, error)
 	GetUnstartedInterviewByUserID(ctx context.Context, userID uint) (*entity.Interview, error)
 	GetOngoingInterviewByUserID(ctx context.Context, userID uint) (*entity.Interview, error)
 	CountByUserIDAndQuestionID(ctx context.Context, userID, questionID uint) (uint, error)
 	ListStartedInterviewsByUserID(ctx context.Context, userID, limit, offset uint) ([]*entity.Interview, uint, error)
+	// Abandoned interviews are excluded because they are not reviewed by the LLM
+	ListEndedInterviewsByQuestionID(ctx context.Context, questionID uint) ([]*entity.Interview, error)
+	ListByExperimentVariantIDs(ctx context.Context, experimentVariantIDs []uint) ([]*entity.Interview, error)
 }
 
 func NewInterviewRepo(
@@ -41,7 +45,7 @@ func (i *InterviewRepoImpl) GetOngoingInterviewByUserID(ctx context.Context, use
 	defer cancel()
 
 	interview := &entity.Interview{}
-	if err := i.db.WithContext(ctx).
+	if err := getDB(ctx, i.db).WithContext(ctx).
 		Where("user_id = ? AND ongoing IS true", userID).
 		First(interview).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -59,7 +63,7 @@ func (i *InterviewRepoImpl) CountByUserIDAndQuestionID(ctx context.Context, user
 
 	var count int64
 
-	if err := i.db.WithContext(ctx).
+	if err := getDB(ctx, i.db).WithContext(ctx).
 		Model(&entity.Interview{}).
 		Where("user_id = ? AND question_id = ?", userID, questionID).
 		Count(&count).Error; err != nil {
@@ -78,7 +82,7 @@ func (i *InterviewRepoImpl) ListStartedInterviewsByUserID(ctx context.Context, u
 	var interviews []*entity.Interview
 	var total int64
 
-	if err := i.db.WithContext(ctx).
+	if err := getDB(ctx, i.db).WithContext(ctx).
 		Model(&entity.Interview{}).
 		Where("user_id = ? AND start_timestamp_ms IS NOT NULL", userID).
 		Count(&total).
@@ -87,7 +91,7 @@ func (i *InterviewRepoImpl) ListStartedInterviewsByUserID(ctx context.Context, u
 			userID, common.ErrInternalServerError)
 	}
 
-	result := i.db.WithContext(ctx).
+	result := getDB(ctx, i.db).WithContext(ctx).
 		Where("user_id = ? AND start_timestamp_ms IS NOT NULL", userID).
 		Order("end_timestamp_ms IS NULL DESC").
 		Order("end_timestamp_ms DESC").
@@ -109,7 +113,7 @@ func (i *InterviewRepoImpl) GetUnstartedInterviewByUserID(ctx context.Context, u
 	defer cancel()
 
 	interview := &entity.Interview{}
-	if err := i.db.WithContext(ctx).
+	if err := getDB(ctx, i.db).WithContext(ctx).
 		Where("user_id = ? AND start_timestamp_ms IS NULL", userID).
 		First(interview).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -127,7 +131,7 @@ func (i *InterviewRepoImpl) GetUnfinishedInterviewByUserID(ctx context.Context,
 	defer cancel()
 
 	interview := &entity.Interview{}
-	if err := i.db.WithContext(ctx).
+	if err := getDB(ctx, i.db).WithContext(ctx).
 		Where("user_id = ? AND start_timestamp_ms IS NOT NULL AND end_timestamp_ms IS NULL", userID).
 		First(interview).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -144,7 +148,7 @@ func (i *InterviewRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Inter
 	defer cancel()
 
 	interview := &entity.Interview{}
-	if err := i.db.WithContext(ctx).
+	if err := getDB(ctx, i.db).WithContext(ctx).
 		First(interview, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -155,12 +159,65 @@ func (i *InterviewRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Inter
 	return interview, nil
 }
 
+// GetByUUID implements InterviewRepo.
+func (i *InterviewRepoImpl) GetByUUID(ctx context.Context, uuid string) (*entity.Interview, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	interview := &entity.Interview{}
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Where("uuid = ?", uuid).
+		First(interview).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("interview not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get interview with uuid %s, %s: %w", uuid, err, common.ErrInternalServerError)
+	}
+
+	return interview, nil
+}
+
+// ListEndedInterviewsByQuestionID implements InterviewRepo.
+func (i *InterviewRepoImpl) ListEndedInterviewsByQuestionID(ctx context.Context, questionID uint) ([]*entity.Interview, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var interviews []*entity.Interview
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Where("question_id = ? AND end_timestamp_ms IS NOT NULL AND abandoned IS false", questionID).
+		Order("end_timestamp_ms ASC").
+		Find(&interviews).Error; err != nil {
+		return nil, fmt.Errorf("unable to list ended interviews for question id %d, %s: %w", questionID, err, common.ErrInternalServerError)
+	}
+
+	return interviews, nil
+}
+
+// ListByExperimentVariantIDs implements InterviewRepo.
+func (i *InterviewRepoImpl) ListByExperimentVariantIDs(ctx context.Context, experimentVariantIDs []uint) ([]*entity.Interview, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	interviews := make([]*entity.Interview, 0)
+	if len(experimentVariantIDs) == 0 {
+		return interviews, nil
+	}
+
+	if err := getDB(ctx, i.db).WithContext(ctx).
+		Where("experiment_variant_id IN ?", experimentVariantIDs).
+		Find(&interviews).Error; err != nil {
+		return nil, fmt.Errorf("unable to list interviews for experiment variant ids %v, %s: %w", experimentVariantIDs, err, common.ErrInternalServerError)
+	}
+
+	return interviews, nil
+}
+
 func (i *InterviewRepoImpl) GetByToken(ctx context.Context, token string) (*entity.Interview, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
 	interview := &entity.Interview{}
-	if err := i.db.WithContext(ctx).
+	if err := getDB(ctx, i.db).WithContext(ctx).
 		Where("token = ?", token).
 		First(interview).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -177,7 +234,7 @@ func (i *InterviewRepoImpl) Create(ctx context.Context, interview *entity.Interv
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := i.db.WithContext(ctx).Create(interview).Error; err != nil {
+	if err := getDB(ctx, i.db).WithContext(ctx).Create(interview).Error; err != nil {
 		return 0, fmt.Errorf("unable to create new interview: %w", common.ErrInternalServerError)
 	}
 
@@ -189,7 +246,7 @@ func (i *InterviewRepoImpl) Update(ctx context.Context, interview *entity.Interv
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := i.db.WithContext(ctx).Save(interview).Error; err != nil {
+	if err := getDB(ctx, i.db).WithContext(ctx).Save(interview).Error; err != nil {
 		return fmt.Errorf("unable to update interview with id %d: %w", interview.ID, common.ErrInternalServerError)
 	}
 
@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+)
+
+// Keeps the files on the local filesystem so that development does not need cloud credentials
+func NewLocalFileRepo(
+	objectStorageConfig *config.ObjectStorageConfig,
+) (FileRepo, error) {
+	if err := os.MkdirAll(objectStorageConfig.LocalDirectory, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create local object storage directory %s, %s: %w", objectStorageConfig.LocalDirectory, err, common.ErrInternalServerError)
+	}
+
+	return &LocalFileRepoImpl{
+		directory:  objectStorageConfig.LocalDirectory,
+		bucketName: objectStorageConfig.BucketName,
+		signer: &fileURLSigner{
+			signingSecret: objectStorageConfig.SigningSecret,
+			publicBaseURL: objectStorageConfig.PublicBaseURL,
+		},
+	}, nil
+}
+
+type LocalFileRepoImpl struct {
+	directory  string
+	bucketName string
+	signer     *fileURLSigner
+}
+
+// Upload implements FileRepo.
+func (l *LocalFileRepoImpl) Upload(ctx context.Context, fileName string, content io.Reader, metadata map[string]any) (*model.FileLocation, error) {
+	location := model.NewFileLocation().
+		SetBucket(l.bucketName).
+		SetKey(fileName)
+
+	path, err := l.getPath(location)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("unable to create directory for file %s, %s: %w", fileName, err, common.ErrInternalServerError)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create file %s, %s: %w", fileName, err, common.ErrInternalServerError)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, content); err != nil {
+		return nil, fmt.Errorf("unable to write file %s, %s: %w", fileName, err, common.ErrInternalServerError)
+	}
+
+	return location, nil
+}
+
+// GetPresignedURL implements FileRepo.
+func (l *LocalFileRepoImpl) GetPresignedURL(ctx context.Context, location *model.FileLocation) (string, error) {
+	if !location.Exists() {
+		return "", fmt.Errorf("file location cannot be empty when presigning: %w", common.ErrInternalServerError)
+	}
+
+	return l.signer.sign(location)
+}
+
+// GetLocationFromURL implements FileRepo.
+func (l *LocalFileRepoImpl) GetLocationFromURL(rawURL string) (*model.FileLocation, error) {
+	return l.signer.getLocation(rawURL)
+}
+
+// Open implements FileRepo.
+func (l *LocalFileRepoImpl) Open(ctx context.Context, location *model.FileLocation) (*model.FileContent, error) {
+	path, err := l.getPath(location)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("file %s not found: %w", location.Key, common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to open file %s, %s: %w", location.Key, err, common.ErrInternalServerError)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to stat file %s, %s: %w", location.Key, err, common.ErrInternalServerError)
+	}
+
+	return &model.FileContent{
+		Content:        file,
+		ContentType:    getContentType(location.Key),
+		Size:           info.Size(),
+		LastModifiedMS: info.ModTime().UnixMilli(),
+		ETag:           fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+	}, nil
+}
+
+// The bucket is a directory under the local directory, keys that escape it are rejected
+func (l *LocalFileRepoImpl) getPath(location *model.FileLocation) (string, error) {
+	if !location.Exists() {
+		return "", fmt.Errorf("file location cannot be empty: %w", common.ErrInternalServerError)
+	}
+
+	bucketName := location.Bucket
+	if bucketName == "" {
+		bucketName = l.bucketName
+	}
+
+	relativePath := filepath.Join(bucketName, filepath.FromSlash(location.Key))
+	if !filepath.IsLocal(relativePath) {
+		return "", fmt.Errorf("file %s is outside of the local object storage: %w", location.Key, common.ErrBadRequest)
+	}
+
+	return filepath.Join(l.directory, relativePath), nil
+}
+
+// The local and memory backends do not store the content type, so it is derived from the extension
+func getContentType(key string) string {
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
@@ -78,8 +78,19 @@ func (o *Ollama) ChatCompletions(ctx context.Context, chatCompletionsRequest *mo
 }
 
 type OllamaChatCompletionsRequest struct {
-	Model    string
-	Messages []*OllamaMessage
+	Model          string
+	Messages       []*OllamaMessage
+	ResponseFormat *OllamaResponseFormat `json:"response_format,omitempty"`
+}
+
+type OllamaResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *OllamaJSONSchema `json:"json_schema,omitempty"`
+}
+
+type OllamaJSONSchema struct {
+	Name   string            `json:"name"`
+	Schema *model.JSONSchema `json:"schema"`
 }
 
 func NewOllamaChatCompletionsRequest() *OllamaChatCompletionsRequest {
@@ -136,6 +147,9 @@ func (o *Ollama) convertToOllamaChatCompletionsRequest(req *model.ChatCompletion
 
 	ollamaChatCompletionsRequest := NewOllamaChatCompletionsRequest()
 	ollamaChatCompletionsRequest.Model = o.model
+	if req.GetModel() != "" {
+		ollamaChatCompletionsRequest.Model = req.GetModel()
+	}
 
 	for _, message := range req.GetMessages() {
 		ollamaMessage := &OllamaMessage{
@@ -145,6 +159,22 @@ func (o *Ollama) convertToOllamaChatCompletionsRequest(req *model.ChatCompletion
 		ollamaChatCompletionsRequest.addMessage(ollamaMessage)
 	}
 
+	responseFormat := req.GetResponseFormat()
+	switch responseFormat.Type {
+	case model.JSON_OBJECT_RESPONSE_FORMAT:
+		ollamaChatCompletionsRequest.ResponseFormat = &OllamaResponseFormat{
+			Type: string(model.JSON_OBJECT_RESPONSE_FORMAT),
+		}
+	case model.JSON_SCHEMA_RESPONSE_FORMAT:
+		ollamaChatCompletionsRequest.ResponseFormat = &OllamaResponseFormat{
+			Type: string(model.JSON_SCHEMA_RESPONSE_FORMAT),
+			JSONSchema: &OllamaJSONSchema{
+				Name:   responseFormat.Name,
+				Schema: responseFormat.Schema,
+			},
+		}
+	}
+
 	return ollamaChatCompletionsRequest, nil
 }
 
@@ -1,9 +1,15 @@
 package openai
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
 )
 
@@ -24,5 +30,133 @@ func NewOpenAILLM(model, baseURL, apiKey string, httpClient *http.Client) *OpenA
 }
 
 func (o *OpenAILLM) ChatCompletions(ctx context.Context, chatCompletionsRequest *model.ChatCompletionsRequest) (*model.ChatCompletionsResponse, error) {
-	panic("implement me")
+	if chatCompletionsRequest == nil {
+		return nil, fmt.Errorf("chatCompletionRequest cannot be nil when calling OpenAI: %w", common.ErrInternalServerError)
+	}
+
+	url := strings.TrimSuffix(o.baseURL, "/") + "/v1/chat/completions"
+	openAIReq := o.convertToOpenAIChatCompletionsRequest(chatCompletionsRequest)
+
+	jsonPayload, err := json.Marshal(openAIReq)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal payload before calling openai, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate new request for openai, %s: %w", err, common.ErrInternalServerError)
+	}
+	req.Header.Set(common.AUTHORIZATION, fmt.Sprintf("Bearer %s", o.apiKey))
+	req.Header.Set(common.CONTENT_TYPE, "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to perform HTTP call to openai, %s: %w", err, common.ErrInternalServerError)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response from openai is not ok, the status code is %d: %w", resp.StatusCode, common.ErrInternalServerError)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response from openai, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	var openAIResp OpenAIChatCompletionsResponse
+	if err := json.Unmarshal(body, &openAIResp); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal response from openai, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	chatCompletionsResponseModel := model.NewChatCompletionsResponse()
+	for _, openAIChoice := range openAIResp.Choices {
+		if openAIChoice == nil || openAIChoice.Message == nil {
+			continue
+		}
+
+		message := model.NewLLMMessage().
+			SetRole(model.LLMRole(openAIChoice.Message.Role)).
+			SetContent(openAIChoice.Message.Content)
+
+		choice := model.NewChoice().
+			SetIndex(openAIChoice.Index).
+			SetMessage(message)
+
+		chatCompletionsResponseModel.AppendChoice(choice)
+	}
+
+	return chatCompletionsResponseModel, nil
+}
+
+type OpenAIChatCompletionsRequest struct {
+	Model          string                `json:"model"`
+	Messages       []*OpenAIMessage      `json:"messages"`
+	ResponseFormat *OpenAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type OpenAIResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *OpenAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type OpenAIJSONSchema struct {
+	Name   string            `json:"name"`
+	Schema *model.JSONSchema `json:"schema"`
+	Strict bool              `json:"strict"`
+}
+
+type OpenAIChatCompletionsResponse struct {
+	ID                string          `json:"id"`
+	Model             string          `json:"model"`
+	CreatedTimestampS int64           `json:"created"`
+	Choices           []*OpenAIChoice `json:"choices"`
+}
+
+type OpenAIChoice struct {
+	Index   int            `json:"index"`
+	Message *OpenAIMessage `json:"message"`
+}
+
+type OpenAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (o *OpenAILLM) convertToOpenAIChatCompletionsRequest(req *model.ChatCompletionsRequest) *OpenAIChatCompletionsRequest {
+	openAIReq := &OpenAIChatCompletionsRequest{
+		Model:    o.model,
+		Messages: make([]*OpenAIMessage, 0),
+	}
+	if req.GetModel() != "" {
+		openAIReq.Model = req.GetModel()
+	}
+
+	for _, message := range req.GetMessages() {
+		openAIReq.Messages = append(openAIReq.Messages, &OpenAIMessage{
+			Role:    string(message.GetRole()),
+			Content: message.GetContent(),
+		})
+	}
+
+	responseFormat := req.GetResponseFormat()
+	switch responseFormat.Type {
+	case model.JSON_OBJECT_RESPONSE_FORMAT:
+		openAIReq.ResponseFormat = &OpenAIResponseFormat{
+			Type: string(model.JSON_OBJECT_RESPONSE_FORMAT),
+		}
+	case model.JSON_SCHEMA_RESPONSE_FORMAT:
+		openAIReq.ResponseFormat = &OpenAIResponseFormat{
+			Type: string(model.JSON_SCHEMA_RESPONSE_FORMAT),
+			JSONSchema: &OpenAIJSONSchema{
+				Name:   responseFormat.Name,
+				Schema: responseFormat.Schema,
+				// The output is validated against the schema again after the call, so it is fine
+				// to fall back to best effort for schemas that strict mode does not support
+				Strict: false,
+			},
+		}
+	}
+
+	return openAIReq
 }
@@ -64,6 +64,19 @@ func migrateEntities(db *gorm.DB) error {
 		&entity.User{},
 		&entity.Review{},
 		&entity.Setting{},
+		&entity.IntentSample{},
+		&entity.RubricScore{},
+		&entity.CodeSnapshot{},
+		&entity.FeedbackItem{},
+		&entity.TranscriptAnnotation{},
+		&entity.AuditLog{},
+		&entity.StudyPlan{},
+		&entity.InterviewEvent{},
+		&entity.TTSCacheEntry{},
+		&entity.PromptTemplate{},
+		&entity.Experiment{},
+		&entity.ExperimentVariant{},
+		&entity.GuardActivation{},
 	)
 	return err
 }
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type PromptTemplateRepo interface {
+	// Ordered by ID in descending order, so that the latest override comes first
+	ListActiveByName(ctx context.Context, name string) ([]*entity.PromptTemplate, error)
+}
+
+func NewPromptTemplateRepo(
+	db *gorm.DB,
+) PromptTemplateRepo {
+	return &PromptTemplateRepoImpl{
+		db: db,
+	}
+}
+
+type PromptTemplateRepoImpl struct {
+	db *gorm.DB
+}
+
+// ListActiveByName implements PromptTemplateRepo.
+func (p *PromptTemplateRepoImpl) ListActiveByName(ctx context.Context, name string) ([]*entity.PromptTemplate, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	promptTemplates := make([]*entity.PromptTemplate, 0)
+	if err := getDB(ctx, p.db).WithContext(ctx).
+		Where("name = ? AND active = ?", name, true).
+		Order("id DESC").
+		Find(&promptTemplates).Error; err != nil {
+		return nil, fmt.Errorf("unable to list prompt templates with name %s, %s: %w", name, err, common.ErrInternalServerError)
+	}
+
+	return promptTemplates, nil
+}
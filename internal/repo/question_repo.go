@@ -13,8 +13,12 @@ import (
 
 type QuestionRepo interface {
 	Create(ctx context.Context, question *entity.Question) (uint, error)
+	Update(ctx context.Context, question *entity.Question) error
 	GetByExternalID(ctx context.Context, externalID string) (*entity.Question, error)
 	GetByID(ctx context.Context, id uint) (*entity.Question, error)
+	// The questions with a description that candidates score the lowest on in the dimension, judged by the displayed
+	// review of every interview of the question. The excluded questions are left out
+	ListHardestByRubricDimension(ctx context.Context, dimension entity.RubricDimension, excludedIDs []uint, limit uint) ([]*entity.Question, error)
 }
 
 func NewQuestionRepo(
@@ -34,13 +38,25 @@ func (q *QuestionRepoImpl) Create(ctx context.Context, question *entity.Question
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := q.db.WithContext(ctx).Create(question).Error; err != nil {
+	if err := getDB(ctx, q.db).WithContext(ctx).Create(question).Error; err != nil {
 		return 0, fmt.Errorf("unable to create new question: %w", err)
 	}
 
 	return question.ID, nil
 }
 
+// Update implements QuestionRepo.
+func (q *QuestionRepoImpl) Update(ctx context.Context, question *entity.Question) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, q.db).WithContext(ctx).Save(question).Error; err != nil {
+		return fmt.Errorf("unable to update question with id %d, %s: %w", question.ID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
 // GetByExternalID implements QuestionRepo.
 func (q *QuestionRepoImpl) GetByExternalID(ctx context.Context, externalID string) (*entity.Question, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
@@ -48,7 +64,7 @@ func (q *QuestionRepoImpl) GetByExternalID(ctx context.Context, externalID strin
 
 	question := &entity.Question{}
 
-	if err := q.db.WithContext(ctx).
+	if err := getDB(ctx, q.db).WithContext(ctx).
 		Where("external_id = ?", externalID).
 		First(question).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -67,7 +83,7 @@ func (q *QuestionRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Questi
 
 	question := &entity.Question{}
 
-	if err := q.db.WithContext(ctx).
+	if err := getDB(ctx, q.db).WithContext(ctx).
 		First(question, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("question not found: %w", common.ErrNotFound)
@@ -77,3 +93,31 @@ func (q *QuestionRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Questi
 
 	return question, nil
 }
+
+// ListHardestByRubricDimension implements QuestionRepo.
+func (q *QuestionRepoImpl) ListHardestByRubricDimension(ctx context.Context, dimension entity.RubricDimension, excludedIDs []uint, limit uint) ([]*entity.Question, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	query := getDB(ctx, q.db).WithContext(ctx).
+		Select("questions.*").
+		Joins("JOIN interviews ON interviews.question_id = questions.id").
+		Joins("JOIN rubric_scores ON rubric_scores.review_id = interviews.review_id").
+		Where("rubric_scores.dimension = ?", dimension).
+		Where("questions.description <> ''")
+
+	if len(excludedIDs) > 0 {
+		query = query.Where("questions.id NOT IN ?", excludedIDs)
+	}
+
+	questions := make([]*entity.Question, 0)
+	if err := query.
+		Group("questions.id").
+		Order("AVG(rubric_scores.score) ASC").
+		Limit(int(limit)).
+		Find(&questions).Error; err != nil {
+		return nil, fmt.Errorf("unable to list the hardest questions for dimension %s, %s: %w", dimension, err, common.ErrInternalServerError)
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,220 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func NewR2FileRepo(
+	client *s3.Client,
+	objectStorageConfig *config.ObjectStorageConfig,
+) FileRepo {
+	return &R2FileRepoImpl{
+		client:        client,
+		presignClient: s3.NewPresignClient(client),
+		bucketName:    objectStorageConfig.BucketName,
+	}
+}
+
+type R2FileRepoImpl struct {
+	client        *s3.Client
+	presignClient *s3.PresignClient
+	bucketName    string
+}
+
+// Upload implements FileRepo.
+func (f *R2FileRepoImpl) Upload(ctx context.Context, fileName string, content io.Reader, metadata map[string]any) (*model.FileLocation, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.FILE_UPLOAD_TIMEOUT)
+	defer cancel()
+
+	_, err := f.client.PutObject(
+		ctx, &s3.PutObjectInput{
+			Bucket: aws.String(f.bucketName),
+			Key:    aws.String(fileName),
+			Body:   content,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to upload file to object storage, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	location := model.NewFileLocation().
+		SetBucket(f.bucketName).
+		SetKey(fileName)
+
+	return location, nil
+}
+
+// GetPresignedURL implements FileRepo.
+func (f *R2FileRepoImpl) GetPresignedURL(ctx context.Context, location *model.FileLocation) (string, error) {
+	if !location.Exists() {
+		return "", fmt.Errorf("file location cannot be empty when presigning: %w", common.ErrInternalServerError)
+	}
+
+	bucketName := location.Bucket
+	if bucketName == "" {
+		bucketName = f.bucketName
+	}
+
+	res, err := f.presignClient.PresignGetObject(
+		ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(location.Key),
+		}, s3.WithPresignExpires(common.PRESIGNED_URL_EXPIRY_DURATION))
+	if err != nil {
+		return "", fmt.Errorf("unable to generate presigned url, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return res.URL, nil
+}
+
+// GetLocationFromURL implements FileRepo.
+func (f *R2FileRepoImpl) GetLocationFromURL(rawURL string) (*model.FileLocation, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse file url, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	key := strings.TrimPrefix(parsedURL.Path, "/")
+	// Path style URLs have the bucket as the first segment, virtual hosted ones have it in the host
+	if !strings.HasPrefix(parsedURL.Hostname(), f.bucketName+".") {
+		key = strings.TrimPrefix(key, f.bucketName+"/")
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("file url %s does not contain a file name: %w", parsedURL.Redacted(), common.ErrInternalServerError)
+	}
+
+	location := model.NewFileLocation().
+		SetBucket(f.bucketName).
+		SetKey(key)
+
+	return location, nil
+}
+
+// Open implements FileRepo.
+func (f *R2FileRepoImpl) Open(ctx context.Context, location *model.FileLocation) (*model.FileContent, error) {
+	if !location.Exists() {
+		return nil, fmt.Errorf("file location cannot be empty when opening: %w", common.ErrInternalServerError)
+	}
+
+	bucketName := location.Bucket
+	if bucketName == "" {
+		bucketName = f.bucketName
+	}
+
+	res, err := f.client.HeadObject(
+		ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(location.Key),
+		})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("file %s not found: %w", location.Key, common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get file metadata from object storage, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	fileContent := &model.FileContent{
+		Content: &r2ObjectReader{
+			ctx:        ctx,
+			client:     f.client,
+			bucketName: bucketName,
+			key:        location.Key,
+			size:       aws.ToInt64(res.ContentLength),
+		},
+		ContentType: aws.ToString(res.ContentType),
+		Size:        aws.ToInt64(res.ContentLength),
+		ETag:        aws.ToString(res.ETag),
+	}
+	if res.LastModified != nil {
+		fileContent.LastModifiedMS = res.LastModified.UnixMilli()
+	}
+
+	return fileContent, nil
+}
+
+// Issues a ranged GET from the current offset on the first read after a seek, so that a range request
+// does not download the whole object. The context is kept because io.ReadSeeker does not take one.
+type r2ObjectReader struct {
+	ctx        context.Context
+	client     *s3.Client
+	bucketName string
+	key        string
+	size       int64
+	offset     int64
+	body       io.ReadCloser
+}
+
+func (r *r2ObjectReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.body == nil {
+		res, err := r.client.GetObject(
+			r.ctx, &s3.GetObjectInput{
+				Bucket: aws.String(r.bucketName),
+				Key:    aws.String(r.key),
+				Range:  aws.String(fmt.Sprintf("bytes=%d-", r.offset)),
+			})
+		if err != nil {
+			return 0, fmt.Errorf("unable to get file from object storage, %s: %w", err, common.ErrInternalServerError)
+		}
+		r.body = res.Body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *r2ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = r.offset + offset
+	case io.SeekEnd:
+		newOffset = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %d: %w", whence, common.ErrInternalServerError)
+	}
+
+	if newOffset < 0 {
+		return 0, fmt.Errorf("cannot seek to negative offset %d: %w", newOffset, common.ErrInternalServerError)
+	}
+
+	// The open body is at the old offset, so the next read has to request the new range
+	if newOffset != r.offset {
+		if err := r.Close(); err != nil {
+			return 0, err
+		}
+	}
+
+	r.offset = newOffset
+	return newOffset, nil
+}
+
+func (r *r2ObjectReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
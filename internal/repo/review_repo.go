@@ -16,6 +16,10 @@ type ReviewRepo interface {
 	Create(ctx context.Context, review *entity.Review) (uint, error)
 	Update(ctx context.Context, review *entity.Review) error
 	GetByID(ctx context.Context, id uint) (*entity.Review, error)
+	GetByInterviewIDAndVersion(ctx context.Context, interviewID, version uint) (*entity.Review, error)
+	// Latest version first
+	ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.Review, error)
+	ListByIDs(ctx context.Context, ids []uint) ([]*entity.Review, error)
 }
 
 func NewReviewRepo(
@@ -34,7 +38,7 @@ func (r *ReviewRepoImpl) Update(ctx context.Context, review *entity.Review) erro
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := r.db.WithContext(ctx).Save(review).Error; err != nil {
+	if err := getDB(ctx, r.db).WithContext(ctx).Save(review).Error; err != nil {
 		return fmt.Errorf("unable to update review with id %d: %w", review.ID, common.ErrInternalServerError)
 	}
 
@@ -46,7 +50,7 @@ func (r *ReviewRepoImpl) Create(ctx context.Context, review *entity.Review) (uin
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := r.db.WithContext(ctx).Create(review).Error; err != nil {
+	if err := getDB(ctx, r.db).WithContext(ctx).Create(review).Error; err != nil {
 		return 0, fmt.Errorf("unable to create new review, %s: %w", err, common.ErrInternalServerError)
 	}
 
@@ -59,7 +63,7 @@ func (r *ReviewRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Review,
 	defer cancel()
 
 	review := &entity.Review{}
-	if err := r.db.WithContext(ctx).First(review, id).Error; err != nil {
+	if err := getDB(ctx, r.db).WithContext(ctx).First(review, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user: %w", common.ErrNotFound)
 		}
@@ -68,3 +72,56 @@ func (r *ReviewRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Review,
 
 	return review, nil
 }
+
+// GetByInterviewIDAndVersion implements ReviewRepo.
+func (r *ReviewRepoImpl) GetByInterviewIDAndVersion(ctx context.Context, interviewID, version uint) (*entity.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	review := &entity.Review{}
+	if err := getDB(ctx, r.db).WithContext(ctx).
+		Where("interview_id = ? AND version = ?", interviewID, version).
+		First(review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("review version %d: %w", version, common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get review version %d for interview id %d, %s: %w", version, interviewID, err, common.ErrInternalServerError)
+	}
+
+	return review, nil
+}
+
+// ListByInterviewID implements ReviewRepo.
+func (r *ReviewRepoImpl) ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var reviews []*entity.Review
+	if err := getDB(ctx, r.db).WithContext(ctx).
+		Where("interview_id = ?", interviewID).
+		Order("version DESC").
+		Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("unable to list reviews for interview id %d, %s: %w", interviewID, err, common.ErrInternalServerError)
+	}
+
+	return reviews, nil
+}
+
+// ListByIDs implements ReviewRepo.
+func (r *ReviewRepoImpl) ListByIDs(ctx context.Context, ids []uint) ([]*entity.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	reviews := make([]*entity.Review, 0)
+	if len(ids) == 0 {
+		return reviews, nil
+	}
+
+	if err := getDB(ctx, r.db).WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("unable to list reviews with ids %v, %s: %w", ids, err, common.ErrInternalServerError)
+	}
+
+	return reviews, nil
+}
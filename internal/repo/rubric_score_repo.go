@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type RubricScoreRepo interface {
+	CreateBatch(ctx context.Context, rubricScores []*entity.RubricScore) error
+	ListByReviewID(ctx context.Context, reviewID uint) ([]*entity.RubricScore, error)
+	ListByReviewIDs(ctx context.Context, reviewIDs []uint) ([]*entity.RubricScore, error)
+	DeleteByReviewID(ctx context.Context, reviewID uint) error
+}
+
+func NewRubricScoreRepo(
+	db *gorm.DB,
+) RubricScoreRepo {
+	return &RubricScoreRepoImpl{
+		db: db,
+	}
+}
+
+type RubricScoreRepoImpl struct {
+	db *gorm.DB
+}
+
+// CreateBatch implements RubricScoreRepo.
+func (r *RubricScoreRepoImpl) CreateBatch(ctx context.Context, rubricScores []*entity.RubricScore) error {
+	if len(rubricScores) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, r.db).WithContext(ctx).Create(rubricScores).Error; err != nil {
+		return fmt.Errorf("unable to create rubric scores, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// ListByReviewID implements RubricScoreRepo.
+func (r *RubricScoreRepoImpl) ListByReviewID(ctx context.Context, reviewID uint) ([]*entity.RubricScore, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var rubricScores []*entity.RubricScore
+	if err := getDB(ctx, r.db).WithContext(ctx).
+		Where("review_id = ?", reviewID).
+		Find(&rubricScores).Error; err != nil {
+		return nil, fmt.Errorf("unable to list rubric scores for review id %d, %s: %w", reviewID, err, common.ErrInternalServerError)
+	}
+
+	return rubricScores, nil
+}
+
+// ListByReviewIDs implements RubricScoreRepo.
+func (r *RubricScoreRepoImpl) ListByReviewIDs(ctx context.Context, reviewIDs []uint) ([]*entity.RubricScore, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rubricScores := make([]*entity.RubricScore, 0)
+	if len(reviewIDs) == 0 {
+		return rubricScores, nil
+	}
+
+	if err := getDB(ctx, r.db).WithContext(ctx).
+		Where("review_id IN ?", reviewIDs).
+		Find(&rubricScores).Error; err != nil {
+		return nil, fmt.Errorf("unable to list rubric scores for review ids %v, %s: %w", reviewIDs, err, common.ErrInternalServerError)
+	}
+
+	return rubricScores, nil
+}
+
+// DeleteByReviewID implements RubricScoreRepo.
+func (r *RubricScoreRepoImpl) DeleteByReviewID(ctx context.Context, reviewID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, r.db).WithContext(ctx).
+		Where("review_id = ?", reviewID).
+		Delete(&entity.RubricScore{}).Error; err != nil {
+		return fmt.Errorf("unable to delete rubric scores for review id %d, %s: %w", reviewID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
@@ -40,7 +40,7 @@ func (s *SessionRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Session
 	defer cancel()
 
 	session := &entity.Session{}
-	if err := s.db.WithContext(ctx).First(session, id).Error; err != nil {
+	if err := getDB(ctx, s.db).WithContext(ctx).First(session, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("session: %w", common.ErrNotFound)
 		}
@@ -55,7 +55,7 @@ func (s *SessionRepoImpl) DeleteExpired(ctx context.Context) (uint, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	result := s.db.WithContext(ctx).
+	result := getDB(ctx, s.db).WithContext(ctx).
 		Where("expire_timestamp_ms < ? ", time.Now().UnixMilli()).
 		Delete(&entity.Session{})
 
@@ -71,7 +71,7 @@ func (s *SessionRepoImpl) Update(ctx context.Context, session *entity.Session) e
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := s.db.WithContext(ctx).Save(session).Error; err != nil {
+	if err := getDB(ctx, s.db).WithContext(ctx).Save(session).Error; err != nil {
 		return fmt.Errorf("unable to update session %s: %w", err, common.ErrInternalServerError)
 	}
 
@@ -83,7 +83,7 @@ func (s *SessionRepoImpl) DeleteByToken(ctx context.Context, token string) error
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	result := s.db.WithContext(ctx).
+	result := getDB(ctx, s.db).WithContext(ctx).
 		Where("token = ?", token).
 		Delete(&entity.Session{})
 
@@ -103,7 +103,7 @@ func (s *SessionRepoImpl) Create(ctx context.Context, session *entity.Session) e
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := s.db.WithContext(ctx).Create(session).Error; err != nil {
+	if err := getDB(ctx, s.db).WithContext(ctx).Create(session).Error; err != nil {
 		return fmt.Errorf("unable to create new session, %s: %w", err, common.ErrInternalServerError)
 	}
 
@@ -116,7 +116,7 @@ func (s *SessionRepoImpl) GetByToken(ctx context.Context, token string) (*entity
 	defer cancel()
 
 	var session entity.Session
-	err := s.db.WithContext(ctx).First(&session, "token = ?", token).Error
+	err := getDB(ctx, s.db).WithContext(ctx).First(&session, "token = ?", token).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, fmt.Errorf("session: %w", common.ErrNotFound)
 	} else if err != nil {
@@ -35,7 +35,7 @@ func (s *SettingRepoImpl) Update(ctx context.Context, setting *entity.Setting) e
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := s.db.WithContext(ctx).Save(setting).Error; err != nil {
+	if err := getDB(ctx, s.db).WithContext(ctx).Save(setting).Error; err != nil {
 		return fmt.Errorf("unable to update setting with id %d: %w", setting.ID, common.ErrInternalServerError)
 	}
 
@@ -46,7 +46,7 @@ func (s *SettingRepoImpl) Create(ctx context.Context, setting *entity.Setting) (
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := s.db.WithContext(ctx).Create(setting).Error; err != nil {
+	if err := getDB(ctx, s.db).WithContext(ctx).Create(setting).Error; err != nil {
 		return 0, fmt.Errorf("unable to create new setting: %w", common.ErrInternalServerError)
 	}
 
@@ -58,7 +58,7 @@ func (s *SettingRepoImpl) GetByID(ctx context.Context, id uint) (*entity.Setting
 	defer cancel()
 
 	setting := &entity.Setting{}
-	if err := s.db.WithContext(ctx).First(setting, id).Error; err != nil {
+	if err := getDB(ctx, s.db).WithContext(ctx).First(setting, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("setting: %w", common.ErrNotFound)
 		}
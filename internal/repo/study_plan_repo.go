@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type StudyPlanRepo interface {
+	Create(ctx context.Context, studyPlan *entity.StudyPlan) error
+	Update(ctx context.Context, studyPlan *entity.StudyPlan) error
+	GetByUserID(ctx context.Context, userID uint) (*entity.StudyPlan, error)
+}
+
+func NewStudyPlanRepo(
+	db *gorm.DB,
+) StudyPlanRepo {
+	return &StudyPlanRepoImpl{
+		db: db,
+	}
+}
+
+type StudyPlanRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements StudyPlanRepo.
+func (s *StudyPlanRepoImpl) Create(ctx context.Context, studyPlan *entity.StudyPlan) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, s.db).WithContext(ctx).Create(studyPlan).Error; err != nil {
+		return fmt.Errorf("unable to create new study plan, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// Update implements StudyPlanRepo.
+func (s *StudyPlanRepoImpl) Update(ctx context.Context, studyPlan *entity.StudyPlan) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, s.db).WithContext(ctx).Save(studyPlan).Error; err != nil {
+		return fmt.Errorf("unable to update study plan with id %d, %s: %w", studyPlan.ID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetByUserID implements StudyPlanRepo.
+func (s *StudyPlanRepoImpl) GetByUserID(ctx context.Context, userID uint) (*entity.StudyPlan, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	studyPlan := &entity.StudyPlan{}
+	if err := getDB(ctx, s.db).WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(studyPlan).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("study plan not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get study plan for user id %d, %s: %w", userID, err, common.ErrInternalServerError)
+	}
+
+	return studyPlan, nil
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+
+	"gorm.io/gorm"
+)
+
+type TransactionRepo interface {
+	// Every repo call that is made with the context passed to fn runs inside the transaction, which is rolled back
+	// when fn returns an error. A call made inside another transaction joins the outer one
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+func NewTransactionRepo(
+	db *gorm.DB,
+) TransactionRepo {
+	return &TransactionRepoImpl{
+		db: db,
+	}
+}
+
+type TransactionRepoImpl struct {
+	db *gorm.DB
+}
+
+type transactionContextKey struct{}
+
+// Transaction implements TransactionRepo.
+func (t *TransactionRepoImpl) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionContextKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	var fnErr error
+	err := getDB(ctx, t.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		fnErr = fn(context.WithValue(ctx, transactionContextKey{}, tx))
+		return fnErr
+	})
+
+	// The error of fn is already wrapped by the repo that returned it
+	if fnErr != nil {
+		return fnErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to commit transaction, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// Returns the transaction that the context is in, or db when there is none
+func getDB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionContextKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db
+}
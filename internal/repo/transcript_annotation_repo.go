@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type TranscriptAnnotationRepo interface {
+	Create(ctx context.Context, annotation *entity.TranscriptAnnotation) error
+	Delete(ctx context.Context, annotation *entity.TranscriptAnnotation) error
+	GetByUUID(ctx context.Context, uuid string) (*entity.TranscriptAnnotation, error)
+	ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.TranscriptAnnotation, error)
+}
+
+func NewTranscriptAnnotationRepo(
+	db *gorm.DB,
+) TranscriptAnnotationRepo {
+	return &TranscriptAnnotationRepoImpl{
+		db: db,
+	}
+}
+
+type TranscriptAnnotationRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements TranscriptAnnotationRepo.
+func (t *TranscriptAnnotationRepoImpl) Create(ctx context.Context, annotation *entity.TranscriptAnnotation) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, t.db).WithContext(ctx).Create(annotation).Error; err != nil {
+		return fmt.Errorf("unable to create new transcript annotation, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// Delete implements TranscriptAnnotationRepo.
+func (t *TranscriptAnnotationRepoImpl) Delete(ctx context.Context, annotation *entity.TranscriptAnnotation) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, t.db).WithContext(ctx).Delete(annotation).Error; err != nil {
+		return fmt.Errorf("unable to delete transcript annotation with id %d, %s: %w", annotation.ID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetByUUID implements TranscriptAnnotationRepo.
+func (t *TranscriptAnnotationRepoImpl) GetByUUID(ctx context.Context, uuid string) (*entity.TranscriptAnnotation, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	annotation := &entity.TranscriptAnnotation{}
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Where("uuid = ?", uuid).
+		First(annotation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("transcript annotation not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get transcript annotation with uuid %s, %s: %w", uuid, err, common.ErrInternalServerError)
+	}
+
+	return annotation, nil
+}
+
+// ListByInterviewID implements TranscriptAnnotationRepo.
+func (t *TranscriptAnnotationRepoImpl) ListByInterviewID(ctx context.Context, interviewID uint) ([]*entity.TranscriptAnnotation, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var annotations []*entity.TranscriptAnnotation
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Where("interview_id = ?", interviewID).
+		Order("create_timestamp_ms ASC").
+		Find(&annotations).Error; err != nil {
+		return nil, fmt.Errorf("unable to list transcript annotations for interview id %d, %s: %w", interviewID, err, common.ErrInternalServerError)
+	}
+
+	return annotations, nil
+}
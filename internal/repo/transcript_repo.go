@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
@@ -13,8 +14,16 @@ import (
 
 type TranscriptRepo interface {
 	Create(ctx context.Context, transcript *entity.Transcript) error
+	Update(ctx context.Context, transcript *entity.Transcript) error
+	GetByUUID(ctx context.Context, uuid string) (*entity.Transcript, error)
 	ListByInterviewIDAsc(ctx context.Context, interviewID uint) ([]*entity.Transcript, error)
 	ListByInterviewIDDesc(ctx context.Context, interviewID uint) ([]*entity.Transcript, error)
+	// Counts the transcripts of the role in the interview up to and including the one with the ID
+	CountByInterviewIDAndRoleUpToID(ctx context.Context, interviewID uint, role entity.Role, id uint) (uint, error)
+	// Ordered by interview and then by ID
+	ListByInterviewIDs(ctx context.Context, interviewIDs []uint) ([]*entity.Transcript, error)
+	// Lists the transcripts that only have a legacy URL, ordered by ID so that the caller can page through them
+	ListWithLegacyURL(ctx context.Context, afterID, limit uint) ([]*entity.Transcript, error)
 }
 
 func NewTranscriptRepo(
@@ -33,19 +42,49 @@ func (t *TranscriptRepoImpl) Create(ctx context.Context, transcript *entity.Tran
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := t.db.WithContext(ctx).Create(transcript).Error; err != nil {
+	if err := getDB(ctx, t.db).WithContext(ctx).Create(transcript).Error; err != nil {
 		return fmt.Errorf("unable to create new transcript: %w", err)
 	}
 
 	return nil
 }
 
+// Update implements TranscriptRepo.
+func (t *TranscriptRepoImpl) Update(ctx context.Context, transcript *entity.Transcript) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, t.db).WithContext(ctx).Save(transcript).Error; err != nil {
+		return fmt.Errorf("unable to update transcript with id %d, %s: %w", transcript.ID, err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetByUUID implements TranscriptRepo.
+func (t *TranscriptRepoImpl) GetByUUID(ctx context.Context, uuid string) (*entity.Transcript, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	transcript := &entity.Transcript{}
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Where("uuid = ?", uuid).
+		First(transcript).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("transcript not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get transcript with uuid %s, %s: %w", uuid, err, common.ErrInternalServerError)
+	}
+
+	return transcript, nil
+}
+
 func (t *TranscriptRepoImpl) ListByInterviewIDAsc(ctx context.Context, interviewID uint) ([]*entity.Transcript, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
 	var transcripts []*entity.Transcript
-	if err := t.db.WithContext(ctx).
+	if err := getDB(ctx, t.db).WithContext(ctx).
 		Where("interview_id = ?", interviewID).
 		Order("create_timestamp_ms ASC").
 		Find(&transcripts).Error; err != nil {
@@ -60,7 +99,7 @@ func (t *TranscriptRepoImpl) ListByInterviewIDDesc(ctx context.Context, intervie
 	defer cancel()
 
 	var transcripts []*entity.Transcript
-	if err := t.db.WithContext(ctx).
+	if err := getDB(ctx, t.db).WithContext(ctx).
 		Where("interview_id = ?", interviewID).
 		Order("create_timestamp_ms DESC").
 		Find(&transcripts).Error; err != nil {
@@ -69,3 +108,57 @@ func (t *TranscriptRepoImpl) ListByInterviewIDDesc(ctx context.Context, intervie
 
 	return transcripts, nil
 }
+
+// CountByInterviewIDAndRoleUpToID implements TranscriptRepo.
+func (t *TranscriptRepoImpl) CountByInterviewIDAndRoleUpToID(ctx context.Context, interviewID uint, role entity.Role, id uint) (uint, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var count int64
+
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Model(&entity.Transcript{}).
+		Where("interview_id = ? AND role = ? AND id <= ?", interviewID, role, id).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("unable to count %s transcripts for interview id of %d, %s: %w", role, interviewID, err, common.ErrInternalServerError)
+	}
+
+	return uint(count), nil
+}
+
+// ListWithLegacyURL implements TranscriptRepo.
+func (t *TranscriptRepoImpl) ListWithLegacyURL(ctx context.Context, afterID, limit uint) ([]*entity.Transcript, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var transcripts []*entity.Transcript
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Where("id > ? AND url IS NOT NULL AND object_key IS NULL", afterID).
+		Order("id ASC").
+		Limit(int(limit)).
+		Find(&transcripts).Error; err != nil {
+		return nil, fmt.Errorf("unable to list transcripts with legacy url after id %d, %s: %w", afterID, err, common.ErrInternalServerError)
+	}
+
+	return transcripts, nil
+}
+
+// ListByInterviewIDs implements TranscriptRepo.
+func (t *TranscriptRepoImpl) ListByInterviewIDs(ctx context.Context, interviewIDs []uint) ([]*entity.Transcript, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	transcripts := make([]*entity.Transcript, 0)
+	if len(interviewIDs) == 0 {
+		return transcripts, nil
+	}
+
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Where("interview_id IN ?", interviewIDs).
+		Order("interview_id ASC, id ASC").
+		Find(&transcripts).Error; err != nil {
+		return nil, fmt.Errorf("unable to list transcripts for interview ids %v, %s: %w", interviewIDs, err, common.ErrInternalServerError)
+	}
+
+	return transcripts, nil
+}
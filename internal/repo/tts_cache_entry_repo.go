@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TTSCacheEntryRepo interface {
+	// Does nothing when an entry with the same hash already exists, which happens when the same text is synthesized concurrently
+	Create(ctx context.Context, ttsCacheEntry *entity.TTSCacheEntry) error
+	GetByHash(ctx context.Context, hash string) (*entity.TTSCacheEntry, error)
+}
+
+func NewTTSCacheEntryRepo(
+	db *gorm.DB,
+) TTSCacheEntryRepo {
+	return &TTSCacheEntryRepoImpl{
+		db: db,
+	}
+}
+
+type TTSCacheEntryRepoImpl struct {
+	db *gorm.DB
+}
+
+// Create implements TTSCacheEntryRepo.
+func (t *TTSCacheEntryRepoImpl) Create(ctx context.Context, ttsCacheEntry *entity.TTSCacheEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "hash"}}, DoNothing: true}).
+		Create(ttsCacheEntry).Error; err != nil {
+		return fmt.Errorf("unable to create new tts cache entry, %s: %w", err, common.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// GetByHash implements TTSCacheEntryRepo.
+func (t *TTSCacheEntryRepoImpl) GetByHash(ctx context.Context, hash string) (*entity.TTSCacheEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	ttsCacheEntry := &entity.TTSCacheEntry{}
+	if err := getDB(ctx, t.db).WithContext(ctx).
+		Where("hash = ?", hash).
+		First(ttsCacheEntry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("tts cache entry not found: %w", common.ErrNotFound)
+		}
+		return nil, fmt.Errorf("unable to get tts cache entry with hash %s, %s: %w", hash, err, common.ErrInternalServerError)
+	}
+
+	return ttsCacheEntry, nil
+}
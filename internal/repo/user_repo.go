@@ -38,7 +38,7 @@ func (u *UserRepoImpl) GetByEmail(ctx context.Context, email string) (*entity.Us
 	defer cancel()
 
 	user := &entity.User{}
-	if err := u.db.WithContext(ctx).Where("email = ?", email).First(user).Error; err != nil {
+	if err := getDB(ctx, u.db).WithContext(ctx).Where("email = ?", email).First(user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user: %w", common.ErrNotFound)
 		}
@@ -53,7 +53,7 @@ func (u *UserRepoImpl) Create(ctx context.Context, user *entity.User) error {
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := u.db.WithContext(ctx).Create(user).Error; err != nil {
+	if err := getDB(ctx, u.db).WithContext(ctx).Create(user).Error; err != nil {
 		return fmt.Errorf("unable to create new user: %w", common.ErrInternalServerError)
 	}
 
@@ -65,7 +65,7 @@ func (u *UserRepoImpl) DeleteByID(ctx context.Context, id uint) error {
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	result := u.db.WithContext(ctx).Delete(&entity.User{}, id)
+	result := getDB(ctx, u.db).WithContext(ctx).Delete(&entity.User{}, id)
 	if err := result.Error; err != nil {
 		return fmt.Errorf("unable to delete user with id %d: %w", id, common.ErrInternalServerError)
 	}
@@ -83,7 +83,7 @@ func (u *UserRepoImpl) GetByID(ctx context.Context, id uint) (*entity.User, erro
 	defer cancel()
 
 	user := &entity.User{}
-	if err := u.db.WithContext(ctx).First(user, id).Error; err != nil {
+	if err := getDB(ctx, u.db).WithContext(ctx).First(user, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user: %w", common.ErrNotFound)
 		}
@@ -98,7 +98,7 @@ func (u *UserRepoImpl) Update(ctx context.Context, user *entity.User) error {
 	ctx, cancel := context.WithTimeout(ctx, config.DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	if err := u.db.WithContext(ctx).Save(user).Error; err != nil {
+	if err := getDB(ctx, u.db).WithContext(ctx).Save(user).Error; err != nil {
 		return fmt.Errorf("unable to update user with id %d: %w", user.ID, common.ErrInternalServerError)
 	}
 
@@ -2,15 +2,32 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"strings"
 
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
 )
 
 type AIUseCase interface {
 	GenerateSpeechReply(ctx context.Context, text, instruction string) (io.Reader, error)
-	GenerateTextReply(ctx context.Context, messages []*model.LLMMessage) (string, error)
+	// An empty llmModel uses the default model of the provider
+	GenerateTextReply(ctx context.Context, messages []*model.LLMMessage, llmModel string) (string, error)
+	// Unmarshals the reply into dst once it passes the schema, the validation errors are sent back to the LLM
+	// for a bounded number of repair attempts before common.ErrMalformedLLMOutput is returned
+	// An empty llmModel uses the default model of the provider
+	GenerateStructuredReply(ctx context.Context, messages []*model.LLMMessage, llmModel, schemaName string, schema *model.JSONSchema, dst any) error
+}
+
+// StructuredReply can be implemented by the dst of GenerateStructuredReply for checks that a JSON schema cannot express,
+// the violations are sent back to the LLM in the same way as schema violations
+type StructuredReply interface {
+	Validate() []string
 }
 
 func NewAIUseCase(
@@ -39,8 +56,9 @@ func (a *AIUseCaseImpl) GenerateSpeechReply(ctx context.Context, text, instructi
 }
 
 // GenerateTextReply implements AIService.
-func (a *AIUseCaseImpl) GenerateTextReply(ctx context.Context, messages []*model.LLMMessage) (string, error) {
+func (a *AIUseCaseImpl) GenerateTextReply(ctx context.Context, messages []*model.LLMMessage, llmModel string) (string, error) {
 	req := model.NewChatCompletionsRequest().
+		SetModel(llmModel).
 		SetMessages(messages)
 
 	resp, err := a.llmRepo.ChatCompletions(ctx, req)
@@ -51,3 +69,76 @@ func (a *AIUseCaseImpl) GenerateTextReply(ctx context.Context, messages []*model
 	reply := resp.GetResponse().GetContent()
 	return reply, nil
 }
+
+// GenerateStructuredReply implements AIService.
+func (a *AIUseCaseImpl) GenerateStructuredReply(ctx context.Context, messages []*model.LLMMessage, llmModel, schemaName string, schema *model.JSONSchema, dst any) error {
+	responseFormat := model.NewResponseFormat(model.JSON_SCHEMA_RESPONSE_FORMAT).
+		SetSchema(schemaName, schema)
+
+	// Copy so that the repair messages do not leak into the caller's slice
+	conversation := make([]*model.LLMMessage, 0, len(messages))
+	conversation = append(conversation, messages...)
+
+	var violations []string
+	for range config.LLM_MAX_REPAIR_ATTEMPTS + 1 {
+		req := model.NewChatCompletionsRequest().
+			SetModel(llmModel).
+			SetMessages(conversation).
+			SetResponseFormat(responseFormat)
+
+		resp, err := a.llmRepo.ChatCompletions(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		reply := resp.GetResponse().GetContent()
+		violations = a.validateStructuredReply(reply, schema, dst)
+		if len(violations) == 0 {
+			return nil
+		}
+
+		repairPrompt := fmt.Sprintf(`
+			Your previous reply could not be used because of the following problems:
+			- %s
+
+			Reply again with only the corrected JSON object, without markdown fences or any other text.
+		`, strings.Join(violations, "\n- "))
+
+		conversation = append(conversation,
+			model.NewLLMMessage().
+				SetRole(model.ASSISTANT).
+				SetContent(reply),
+			model.NewLLMMessage().
+				SetRole(model.USER).
+				SetContent(repairPrompt),
+		)
+	}
+
+	return fmt.Errorf("llm reply for %s is still invalid after %d repair attempts, %s: %w", schemaName, config.LLM_MAX_REPAIR_ATTEMPTS, strings.Join(violations, "; "), common.ErrMalformedLLMOutput)
+}
+
+func (a *AIUseCaseImpl) validateStructuredReply(reply string, schema *model.JSONSchema, dst any) []string {
+	jsonString, err := util.ExtractJSON(reply)
+	if err != nil {
+		return []string{"the reply does not contain a JSON object"}
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(jsonString), &value); err != nil {
+		return []string{fmt.Sprintf("the reply is not valid JSON: %s", err)}
+	}
+
+	if violations := schema.Validate(value); len(violations) > 0 {
+		return violations
+	}
+
+	if err := json.Unmarshal([]byte(jsonString), dst); err != nil {
+		return []string{fmt.Sprintf("the reply does not match the expected types: %s", err)}
+	}
+
+	if structuredReply, ok := dst.(StructuredReply); ok {
+		return structuredReply.Validate()
+	}
+
+	return nil
+}
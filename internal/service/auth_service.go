@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
@@ -23,6 +24,8 @@ type AuthService interface {
 	// These two functions are only called by middleware
 	ValidateAndRefreshSessionToken(ctx context.Context, token string) (string, error)
 	GetUserIDFromSessionToken(ctx context.Context, token string) (uint, error)
+	// Returns ErrForbidden if the user does not have any of the roles
+	AuthorizeUserRole(ctx context.Context, userID uint, roles ...entity.UserRole) error
 }
 
 func NewAuthService(
@@ -73,6 +76,23 @@ func (a *AuthServiceImpl) GenerateRandomToken() string {
 	return base64.RawURLEncoding.EncodeToString(b)
 }
 
+// AuthorizeUserRole implements AuthService.
+func (a *AuthServiceImpl) AuthorizeUserRole(ctx context.Context, userID uint, roles ...entity.UserRole) error {
+	user, err := a.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return common.ErrUnauthorized
+		}
+		return err
+	}
+
+	if !user.HasRole(roles...) {
+		return fmt.Errorf("user %d does not have the required role: %w", userID, common.ErrForbidden)
+	}
+
+	return nil
+}
+
 // GetUserIDFromSessionToken implements AuthService.
 func (a *AuthServiceImpl) GetUserIDFromSessionToken(ctx context.Context, token string) (uint, error) {
 	session, err := a.sessionRepo.GetByToken(ctx, token)
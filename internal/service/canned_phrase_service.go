@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+)
+
+// Nothing about the candidate goes into the phrases, so that the audio of every phrase is synthesized once and shared
+var cannedPhraseCatalog = map[model.CannedPhrase]*template.Template{
+	model.GREETING_CANNED_PHRASE: template.Must(template.New(string(model.GREETING_CANNED_PHRASE)).Parse(
+		`Hi, thanks for joining. I'll be your interviewer today.`,
+	)),
+	model.TIME_WARNING_CANNED_PHRASE: template.Must(template.New(string(model.TIME_WARNING_CANNED_PHRASE)).Parse(
+		`Just a heads up, you have {{.MinutesRemaining}} {{if eq .MinutesRemaining 1}}minute{{else}}minutes{{end}} left.`,
+	)),
+	model.TIME_UP_CANNED_PHRASE: template.Must(template.New(string(model.TIME_UP_CANNED_PHRASE)).Parse(
+		`That's time. Thank you for working through this problem with me. I'll review the whole session and get back to you with your score and feedback shortly.`,
+	)),
+	model.RECONNECT_WELCOME_CANNED_PHRASE: template.Must(template.New(string(model.RECONNECT_WELCOME_CANNED_PHRASE)).Parse(
+		`Welcome back. Let's pick up where we left off.`,
+	)),
+	model.INJECTION_REFUSAL_CANNED_PHRASE: template.Must(template.New(string(model.INJECTION_REFUSAL_CANNED_PHRASE)).Parse(
+		`I can't help with that. Let's get back to the problem.`,
+	)),
+	model.INJECTION_WARNING_CANNED_PHRASE: template.Must(template.New(string(model.INJECTION_WARNING_CANNED_PHRASE)).Parse(
+		`I can't help with that. Just so you know, attempts to change how I run this interview are noted for the review. Let's get back to the problem.`,
+	)),
+}
+
+type CannedPhraseService interface {
+	// Returns the rendered text and the location of its audio, the audio is synthesized on first use and reused afterwards
+	Speak(ctx context.Context, phrase model.CannedPhrase, variables *model.CannedPhraseVariables) (string, *model.FileLocation, error)
+	// Only renders the text, for the interviews that are not spoken
+	Render(phrase model.CannedPhrase, variables *model.CannedPhraseVariables) (string, error)
+	// Synthesizes every phrase so that the first interviews do not wait for the TTS
+	Presynthesize(ctx context.Context) error
+}
+
+func NewCannedPhraseService(
+	ttsCacheService TTSCacheService,
+	promptService PromptService,
+) CannedPhraseService {
+	return &CannedPhraseServiceImpl{
+		ttsCacheService: ttsCacheService,
+		promptService:   promptService,
+	}
+}
+
+type CannedPhraseServiceImpl struct {
+	ttsCacheService TTSCacheService
+	promptService   PromptService
+}
+
+// Speak implements CannedPhraseService.
+func (c *CannedPhraseServiceImpl) Speak(ctx context.Context, phrase model.CannedPhrase, variables *model.CannedPhraseVariables) (string, *model.FileLocation, error) {
+	text, err := c.Render(phrase, variables)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The default instruction is always used so that the presynthesized audio is shared by every interview
+	instruction, err := c.promptService.Render(ctx, model.INTERVIEWER_SPEECH_PROMPT, nil, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	audioLocation, err := c.ttsCacheService.Synthesize(ctx, text, instruction.Content)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return text, audioLocation, nil
+}
+
+// Presynthesize implements CannedPhraseService.
+func (c *CannedPhraseServiceImpl) Presynthesize(ctx context.Context) error {
+	variablesByPhrase := map[model.CannedPhrase][]*model.CannedPhraseVariables{
+		model.GREETING_CANNED_PHRASE:          {model.NewCannedPhraseVariables()},
+		model.TIME_UP_CANNED_PHRASE:           {model.NewCannedPhraseVariables()},
+		model.RECONNECT_WELCOME_CANNED_PHRASE: {model.NewCannedPhraseVariables()},
+		model.INJECTION_REFUSAL_CANNED_PHRASE: {model.NewCannedPhraseVariables()},
+		model.INJECTION_WARNING_CANNED_PHRASE: {model.NewCannedPhraseVariables()},
+	}
+	for _, minutesRemaining := range config.INTERVIEW_TIME_WARNING_MINUTES {
+		variablesByPhrase[model.TIME_WARNING_CANNED_PHRASE] = append(variablesByPhrase[model.TIME_WARNING_CANNED_PHRASE],
+			model.NewCannedPhraseVariables().SetMinutesRemaining(minutesRemaining))
+	}
+
+	var errs []error
+	for phrase, variablesList := range variablesByPhrase {
+		for _, variables := range variablesList {
+			if _, _, err := c.Speak(ctx, phrase, variables); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Render implements CannedPhraseService.
+func (c *CannedPhraseServiceImpl) Render(phrase model.CannedPhrase, variables *model.CannedPhraseVariables) (string, error) {
+	tmpl, ok := cannedPhraseCatalog[phrase]
+	if !ok {
+		return "", fmt.Errorf("canned phrase %s is not in the catalog: %w", phrase, common.ErrInternalServerError)
+	}
+
+	if variables == nil {
+		variables = model.NewCannedPhraseVariables()
+	}
+
+	var text strings.Builder
+	if err := tmpl.Execute(&text, variables); err != nil {
+		return "", fmt.Errorf("unable to render canned phrase %s, %s: %w", phrase, err, common.ErrInternalServerError)
+	}
+
+	return text.String(), nil
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+)
+
+type ExperimentService interface {
+	// The same user always gets the same variant of an experiment, nil is returned when there is no active experiment
+	AssignVariant(ctx context.Context, userID uint) (*entity.ExperimentVariant, error)
+	// Nil is returned when the interview is not part of any experiment
+	GetVariant(ctx context.Context, interview *entity.Interview) (*entity.ExperimentVariant, error)
+	// The experiment ID here is the UUID
+	GetReport(ctx context.Context, experimentID string) (*model.ExperimentReport, error)
+	// The experiment is created inactive with its variants, the UUID of the experiment is returned
+	CreateExperiment(ctx context.Context, definition *model.ExperimentDefinition) (string, error)
+	// Only the latest active experiment assigns variants, so every other experiment is deactivated
+	ActivateExperiment(ctx context.Context, experimentID string) error
+	// The interviews that were already assigned a variant keep it
+	DeactivateExperiment(ctx context.Context, experimentID string) error
+}
+
+func NewExperimentService(
+	experimentRepo repo.ExperimentRepo,
+	experimentVariantRepo repo.ExperimentVariantRepo,
+	interviewRepo repo.InterviewRepo,
+	reviewRepo repo.ReviewRepo,
+	transcriptRepo repo.TranscriptRepo,
+	transactionRepo repo.TransactionRepo,
+) ExperimentService {
+	return &ExperimentServiceImpl{
+		experimentRepo:        experimentRepo,
+		experimentVariantRepo: experimentVariantRepo,
+		interviewRepo:         interviewRepo,
+		reviewRepo:            reviewRepo,
+		transcriptRepo:        transcriptRepo,
+		transactionRepo:       transactionRepo,
+	}
+}
+
+type ExperimentServiceImpl struct {
+	experimentRepo        repo.ExperimentRepo
+	experimentVariantRepo repo.ExperimentVariantRepo
+	interviewRepo         repo.InterviewRepo
+	reviewRepo            repo.ReviewRepo
+	transcriptRepo        repo.TranscriptRepo
+	transactionRepo       repo.TransactionRepo
+}
+
+// AssignVariant implements ExperimentService.
+func (e *ExperimentServiceImpl) AssignVariant(ctx context.Context, userID uint) (*entity.ExperimentVariant, error) {
+	experiment, err := e.experimentRepo.GetActive(ctx)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	experimentVariants, err := e.experimentVariantRepo.ListByExperimentID(ctx, experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalWeight uint64
+	for _, experimentVariant := range experimentVariants {
+		totalWeight += uint64(experimentVariant.Weight)
+	}
+
+	if totalWeight == 0 {
+		return nil, nil
+	}
+
+	// The experiment is part of the hash so that a user does not land in the same bucket of every experiment
+	hash := sha256.Sum256(fmt.Appendf(nil, "%s:%d", experiment.UUID, userID))
+	bucket := binary.BigEndian.Uint64(hash[:8]) % totalWeight
+
+	for _, experimentVariant := range experimentVariants {
+		if bucket < uint64(experimentVariant.Weight) {
+			return experimentVariant, nil
+		}
+		bucket -= uint64(experimentVariant.Weight)
+	}
+
+	return nil, fmt.Errorf("unable to assign a variant of experiment %s to user id %d: %w", experiment.UUID, userID, common.ErrInternalServerError)
+}
+
+// GetVariant implements ExperimentService.
+func (e *ExperimentServiceImpl) GetVariant(ctx context.Context, interview *entity.Interview) (*entity.ExperimentVariant, error) {
+	if !interview.Exists() || interview.ExperimentVariantID == nil {
+		return nil, nil
+	}
+
+	return e.experimentVariantRepo.GetByID(ctx, *interview.ExperimentVariantID)
+}
+
+// GetReport implements ExperimentService.
+func (e *ExperimentServiceImpl) GetReport(ctx context.Context, experimentID string) (*model.ExperimentReport, error) {
+	experiment, err := e.experimentRepo.GetByUUID(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+
+	experimentVariants, err := e.experimentVariantRepo.ListByExperimentID(ctx, experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	experimentVariantIDs := make([]uint, 0)
+	for _, experimentVariant := range experimentVariants {
+		experimentVariantIDs = append(experimentVariantIDs, experimentVariant.ID)
+	}
+
+	interviews, err := e.interviewRepo.ListByExperimentVariantIDs(ctx, experimentVariantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	interviewIDs := make([]uint, 0)
+	reviewIDs := make([]uint, 0)
+	for _, interview := range interviews {
+		interviewIDs = append(interviewIDs, interview.ID)
+		if interview.ReviewID != nil {
+			reviewIDs = append(reviewIDs, interview.GetReviewID())
+		}
+	}
+
+	reviews, err := e.reviewRepo.ListByIDs(ctx, reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewMap := make(map[uint]*entity.Review)
+	for _, review := range reviews {
+		reviewMap[review.ID] = review
+	}
+
+	transcripts, err := e.transcriptRepo.ListByInterviewIDs(ctx, interviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriptMap := make(map[uint][]*entity.Transcript)
+	for _, transcript := range transcripts {
+		transcriptMap[transcript.InterviewID] = append(transcriptMap[transcript.InterviewID], transcript)
+	}
+
+	interviewMap := make(map[uint][]*entity.Interview)
+	for _, interview := range interviews {
+		variantID := *interview.ExperimentVariantID
+		interviewMap[variantID] = append(interviewMap[variantID], interview)
+	}
+
+	report := model.NewExperimentReport().
+		SetExperimentID(experiment.UUID).
+		SetName(experiment.Name).
+		SetActive(experiment.Active)
+
+	for _, experimentVariant := range experimentVariants {
+		report.AppendVariant(e.buildVariantReport(experimentVariant, interviewMap[experimentVariant.ID], reviewMap, transcriptMap))
+	}
+
+	return report, nil
+}
+
+// CreateExperiment implements ExperimentService.
+func (e *ExperimentServiceImpl) CreateExperiment(ctx context.Context, definition *model.ExperimentDefinition) (string, error) {
+	if err := e.validateDefinition(definition); err != nil {
+		return "", err
+	}
+
+	_, err := e.experimentRepo.GetByName(ctx, definition.Name)
+	if err == nil {
+		return "", fmt.Errorf("experiment name is already taken: %w", common.ErrBadRequest)
+	}
+	if !errors.Is(err, common.ErrNotFound) {
+		return "", err
+	}
+
+	experiment := entity.NewExperiment().
+		SetName(definition.Name).
+		SetDescription(definition.Description)
+
+	// An experiment without all of its variants would split the interviews differently from what was defined
+	err = e.transactionRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := e.experimentRepo.Create(ctx, experiment); err != nil {
+			return err
+		}
+
+		for _, variantDefinition := range definition.Variants {
+			experimentVariant := entity.NewExperimentVariant().
+				SetExperimentID(experiment.ID).
+				SetName(variantDefinition.Name).
+				SetWeight(variantDefinition.Weight).
+				SetPromptVersion(variantDefinition.PromptVersion).
+				SetModel(variantDefinition.Model).
+				SetClassifierThreshold(variantDefinition.ClassifierThreshold)
+
+			if err := e.experimentVariantRepo.Create(ctx, experimentVariant); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return experiment.UUID, nil
+}
+
+// ActivateExperiment implements ExperimentService.
+func (e *ExperimentServiceImpl) ActivateExperiment(ctx context.Context, experimentID string) error {
+	experiment, err := e.experimentRepo.GetByUUID(ctx, experimentID)
+	if err != nil {
+		return err
+	}
+
+	experiment.Activate()
+
+	return e.transactionRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := e.experimentRepo.DeactivateOthers(ctx, experiment.ID); err != nil {
+			return err
+		}
+
+		return e.experimentRepo.Update(ctx, experiment)
+	})
+}
+
+// DeactivateExperiment implements ExperimentService.
+func (e *ExperimentServiceImpl) DeactivateExperiment(ctx context.Context, experimentID string) error {
+	experiment, err := e.experimentRepo.GetByUUID(ctx, experimentID)
+	if err != nil {
+		return err
+	}
+
+	if !experiment.Active {
+		return nil
+	}
+
+	experiment.Deactivate()
+
+	return e.experimentRepo.Update(ctx, experiment)
+}
+
+func (e *ExperimentServiceImpl) validateDefinition(definition *model.ExperimentDefinition) error {
+	if definition == nil || strings.TrimSpace(definition.Name) == "" {
+		return fmt.Errorf("experiment name is required: %w", common.ErrBadRequest)
+	}
+
+	if len(definition.Variants) == 0 {
+		return fmt.Errorf("experiment must have at least one variant: %w", common.ErrBadRequest)
+	}
+
+	variantNames := make(map[string]bool)
+	var totalWeight uint64
+	for _, variantDefinition := range definition.Variants {
+		if variantDefinition == nil || strings.TrimSpace(variantDefinition.Name) == "" {
+			return fmt.Errorf("variant name is required: %w", common.ErrBadRequest)
+		}
+
+		if variantNames[variantDefinition.Name] {
+			return fmt.Errorf("variant name %s is used more than once: %w", variantDefinition.Name, common.ErrBadRequest)
+		}
+		variantNames[variantDefinition.Name] = true
+
+		if threshold := variantDefinition.ClassifierThreshold; threshold != nil && (*threshold < 0 || *threshold > 100) {
+			return fmt.Errorf("classifier threshold of variant %s must be between 0 and 100, got %v: %w", variantDefinition.Name, *threshold, common.ErrBadRequest)
+		}
+
+		totalWeight += uint64(variantDefinition.Weight)
+	}
+
+	// No user would be assigned a variant otherwise
+	if totalWeight == 0 {
+		return fmt.Errorf("at least one variant must have a weight: %w", common.ErrBadRequest)
+	}
+
+	return nil
+}
+
+func (e *ExperimentServiceImpl) buildVariantReport(
+	experimentVariant *entity.ExperimentVariant,
+	interviews []*entity.Interview,
+	reviewMap map[uint]*entity.Review,
+	transcriptMap map[uint][]*entity.Transcript,
+) *model.ExperimentVariantReport {
+	variantReport := &model.ExperimentVariantReport{
+		Name:                experimentVariant.Name,
+		PromptVersion:       experimentVariant.PromptVersion,
+		Model:               experimentVariant.Model,
+		ClassifierThreshold: experimentVariant.GetClassifierThreshold(config.INTENT_CLASSIFICATION_DEFAULT_THRESHOLD),
+		InterviewCount:      uint(len(interviews)),
+	}
+
+	var abandonedCount, passedCount, replyCount, hintCount, clarificationCount, timedReplyCount uint
+	var totalScore, totalReplyLatencyMS int64
+
+	for _, interview := range interviews {
+		if interview.StartTimestampMS == nil {
+			continue
+		}
+		variantReport.StartedCount++
+
+		if interview.Abandoned {
+			abandonedCount++
+		}
+
+		review, ok := reviewMap[interview.GetReviewID()]
+		if ok && review.ReviewedTimestampMS != nil {
+			variantReport.ReviewedCount++
+			totalScore += int64(review.Score)
+			if review.Passed {
+				passedCount++
+			}
+		}
+
+		// The latency is measured from the sentence of the candidate that the interviewer replied to,
+		// and the reply is a hint or a clarification by what that sentence was classified as
+		var previousCandidateTimestampMS int64
+		var previousCandidateIntent entity.Intent
+		for _, transcript := range transcriptMap[interview.ID] {
+			switch {
+			case transcript.Role == entity.USER:
+				previousCandidateTimestampMS = transcript.CreateTimestampMS
+				previousCandidateIntent = transcript.Intent
+			case transcript.Role == entity.ASSISTANT && transcript.PromptName == string(model.INTERVIEWER_REPLY_PROMPT):
+				replyCount++
+				switch previousCandidateIntent {
+				case entity.HINT_REQUEST:
+					hintCount++
+				case entity.CLARIFICATION_REQUEST:
+					clarificationCount++
+				}
+				if previousCandidateTimestampMS > 0 {
+					totalReplyLatencyMS += transcript.CreateTimestampMS - previousCandidateTimestampMS
+					timedReplyCount++
+				}
+				previousCandidateTimestampMS = 0
+				previousCandidateIntent = entity.NO_INTENT
+			}
+		}
+	}
+
+	variantReport.AverageScore = e.divide(float64(totalScore), variantReport.ReviewedCount)
+	variantReport.PassRate = e.divide(float64(passedCount), variantReport.ReviewedCount)
+	variantReport.AverageReplyCount = e.divide(float64(replyCount), variantReport.StartedCount)
+	variantReport.AverageHintCount = e.divide(float64(hintCount), variantReport.StartedCount)
+	variantReport.AverageClarificationCount = e.divide(float64(clarificationCount), variantReport.StartedCount)
+	variantReport.AbandonmentRate = e.divide(float64(abandonedCount), variantReport.StartedCount)
+	variantReport.AverageReplyLatencyMS = e.divide(float64(totalReplyLatencyMS), timedReplyCount)
+
+	return variantReport
+}
+
+func (e *ExperimentServiceImpl) divide(total float64, count uint) *float64 {
+	if count == 0 {
+		return nil
+	}
+	average := total / float64(count)
+	return &average
+}
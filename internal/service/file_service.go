@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type FileService interface {
+	// Serves the URLs signed by the local and memory object storage, the caller has to close the content
+	OpenSignedFile(ctx context.Context, location *model.FileLocation, expiresAtS int64, signature string) (*model.FileContent, error)
+}
+
+func NewFileService(
+	objectStorageConfig *config.ObjectStorageConfig,
+	fileRepo repo.FileRepo,
+) FileService {
+	return &FileServiceImpl{
+		objectStorageConfig: objectStorageConfig,
+		fileRepo:            fileRepo,
+	}
+}
+
+type FileServiceImpl struct {
+	objectStorageConfig *config.ObjectStorageConfig
+	fileRepo            repo.FileRepo
+}
+
+// OpenSignedFile implements FileService.
+func (f *FileServiceImpl) OpenSignedFile(ctx context.Context, location *model.FileLocation, expiresAtS int64, signature string) (*model.FileContent, error) {
+	// R2 serves its own presigned URLs, so the route does not exist for it
+	if f.objectStorageConfig.Provider == config.R2_OBJECT_STORAGE_PROVIDER {
+		return nil, fmt.Errorf("signed file urls are not served by the %s provider: %w", f.objectStorageConfig.Provider, common.ErrNotFound)
+	}
+
+	if !location.Exists() {
+		return nil, fmt.Errorf("file location cannot be empty: %w", common.ErrBadRequest)
+	}
+
+	if !util.VerifyHMAC(f.objectStorageConfig.SigningSecret, location.GetSigningPayload(expiresAtS), signature) {
+		return nil, fmt.Errorf("invalid signature for file %s: %w", location.Key, common.ErrUnauthorized)
+	}
+
+	if time.Now().Unix() > expiresAtS {
+		return nil, fmt.Errorf("signed url for file %s has expired: %w", location.Key, common.ErrUnauthorized)
+	}
+
+	return f.fileRepo.Open(ctx, location)
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+)
+
+var (
+	codeFencePattern      = regexp.MustCompile("(?s)```.*?(```|$)")
+	codeLinePattern       = regexp.MustCompile(`^\s*(def |class |for\s*\(|for \w+ in |while\s*\(|while .*:$|if\s*\(|if .*:$|else\s*[:{]|elif |return\b|function |func |public |private |int |let |const |var |#include|import )|[;{}]\s*$`)
+	assignmentLinePattern = regexp.MustCompile(`^\s*[\w.\[\]]+\s*(=|\+=|-=|\*=|/=)\s*(\S.*?)\s*$`)
+	// Two words in a row after the assignment mean that the line goes on in prose, such as "x = 5 works here"
+	proseWordsPattern    = regexp.MustCompile(`[A-Za-z]{2,}\s+[A-Za-z]{2,}`)
+	algorithmStepPattern = regexp.MustCompile(`(?im)^\s*(\d+[.)]|step \d+|[-*•] )`)
+	sequenceStepPattern  = regexp.MustCompile(`(?i)^(first|second|third|then|next|after that|afterwards|finally|lastly)\b`)
+	wordPattern          = regexp.MustCompile(`[a-z0-9_]+`)
+	sentencePattern      = regexp.MustCompile(`[^.!?\n]+[.!?]*`)
+)
+
+// Phrases that only make sense as instructions to the LLM, the classifier catches the ones that are worded differently
+var injectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override|skip)\b.{0,30}\b(previous|prior|above|earlier|preceding|all|your|system|original)\b.{0,20}\b(instructions?|prompts?|rules|directions|guidelines|messages)\b`),
+	// The prompt of the question is asked about all the time, so only the prompt of the interviewer counts
+	regexp.MustCompile(`(?i)\b(reveal|show|print|repeat|output|tell me|leak|dump)\b.{0,20}\b(your (system )?|the system )(prompt|instructions)\b`),
+	regexp.MustCompile(`(?i)\byou are (now|no longer)\b|\bfrom now on,? (you|act|respond|answer)\b`),
+	regexp.MustCompile(`(?i)\b(act|pretend|behave|roleplay) (as|like|to be)\b.{0,30}\b(unrestricted|jailbroken|developer|admin|another|different|new) `),
+	regexp.MustCompile(`(?i)\b(new|updated|real) (instructions|system prompt|rules)\s*:`),
+	regexp.MustCompile(`(?i)\b(developer|god|dan|jailbreak) mode\b|\bjailbreak\b`),
+	regexp.MustCompile(`(?im)(<\s*/?\s*(system|assistant|user|instructions?)\s*>|\[/?(system|inst)\]|^\s*(system|assistant)\s*:)`),
+}
+
+type llmInjectionClassification struct {
+	Injection  bool `json:"injection"`
+	Confidence uint `json:"confidence"`
+}
+
+type GuardService interface {
+	// Returns an empty reason when the reply does not give away the solution, the score is only set for the reference overlap.
+	// The reference solution is optional
+	CheckSolutionLeak(reply, referenceSolution string) (entity.GuardReason, float64)
+	// Drops the parts of the reply that give away the solution, a generic nudge is returned when nothing is left
+	SoftenSolutionLeak(reply, referenceSolution string) string
+	// Returns nil when the content does not try to inject instructions, otherwise the activation with the action
+	// of the configured policy, which is left for the caller to record
+	DetectInjection(ctx context.Context, content string, source entity.GuardSource) (*entity.GuardActivation, error)
+	Record(ctx context.Context, guardActivation *entity.GuardActivation) error
+	// Whether the interview had an injection that the policy let through
+	IsFlagged(ctx context.Context, interviewID uint) (bool, error)
+}
+
+func NewGuardService(
+	injectionConfig *config.InjectionConfig,
+	aiUseCase AIUseCase,
+	promptService PromptService,
+	guardActivationRepo repo.GuardActivationRepo,
+) GuardService {
+	return &GuardServiceImpl{
+		injectionConfig:     injectionConfig,
+		aiUseCase:           aiUseCase,
+		promptService:       promptService,
+		guardActivationRepo: guardActivationRepo,
+	}
+}
+
+type GuardServiceImpl struct {
+	injectionConfig     *config.InjectionConfig
+	aiUseCase           AIUseCase
+	promptService       PromptService
+	guardActivationRepo repo.GuardActivationRepo
+}
+
+// CheckSolutionLeak implements GuardService.
+func (g *GuardServiceImpl) CheckSolutionLeak(reply, referenceSolution string) (entity.GuardReason, float64) {
+	if codeFencePattern.MatchString(reply) || g.countCodeLines(reply) > config.GUARD_MAX_CODE_LINES {
+		return entity.CODE_BLOCK_GUARD_REASON, 0
+	}
+
+	if g.isAlgorithmWalkthrough(reply) {
+		return entity.ALGORITHM_GUARD_REASON, 0
+	}
+
+	if overlap := g.getReferenceOverlap(reply, referenceSolution); overlap >= config.GUARD_REFERENCE_OVERLAP_THRESHOLD {
+		return entity.REFERENCE_OVERLAP_GUARD_REASON, overlap
+	}
+
+	return "", 0
+}
+
+// SoftenSolutionLeak implements GuardService.
+func (g *GuardServiceImpl) SoftenSolutionLeak(reply, referenceSolution string) string {
+	reply = codeFencePattern.ReplaceAllString(reply, "\n")
+
+	keptLines := make([]string, 0)
+	for line := range strings.SplitSeq(reply, "\n") {
+		if g.isCodeLine(line) || algorithmStepPattern.MatchString(line) {
+			continue
+		}
+		keptLines = append(keptLines, line)
+	}
+
+	keptSentences := make([]string, 0)
+	for _, sentence := range sentencePattern.FindAllString(strings.Join(keptLines, "\n"), -1) {
+		sentence = strings.TrimSpace(sentence)
+		// A sentence that ends with a colon only introduced what was dropped
+		if sentence == "" || strings.HasSuffix(sentence, ":") || g.getReferenceOverlap(sentence, referenceSolution) >= config.GUARD_REFERENCE_OVERLAP_THRESHOLD {
+			continue
+		}
+		keptSentences = append(keptSentences, sentence)
+	}
+
+	// A walkthrough in prose cannot be cut apart
+	if len(keptSentences) == 0 || g.isAlgorithmWalkthrough(strings.Join(keptSentences, " ")) {
+		return config.GUARD_FALLBACK_REPLY
+	}
+
+	return strings.Join(keptSentences, " ")
+}
+
+// DetectInjection implements GuardService.
+func (g *GuardServiceImpl) DetectInjection(ctx context.Context, content string, source entity.GuardSource) (*entity.GuardActivation, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, nil
+	}
+
+	guardActivation := entity.NewGuardActivation().
+		SetType(entity.PROMPT_INJECTION_GUARD).
+		SetSource(source).
+		SetAction(g.getInjectionAction()).
+		SetAttempts(1).
+		SetOriginalContent(content)
+
+	for _, injectionPattern := range injectionPatterns {
+		if injectionPattern.MatchString(content) {
+			return guardActivation.SetReason(entity.HEURISTIC_GUARD_REASON), nil
+		}
+	}
+
+	if !g.injectionConfig.ClassifierEnabled {
+		return nil, nil
+	}
+
+	prompt, err := g.promptService.Render(ctx, model.INJECTION_CLASSIFIER_PROMPT, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := model.CANDIDATE_SPEECH_TAG
+	if source == entity.QUESTION_DESCRIPTION_GUARD_SOURCE {
+		tag = model.QUESTION_DESCRIPTION_TAG
+	}
+
+	llmMessages := []*model.LLMMessage{
+		model.NewLLMMessage().
+			SetRole(model.SYSTEM).
+			SetContent(prompt.Content),
+		model.NewLLMMessage().
+			SetRole(model.USER).
+			SetContent(model.DelimitUntrustedContent(tag, content)),
+	}
+
+	classification := &llmInjectionClassification{}
+	if err := g.aiUseCase.GenerateStructuredReply(ctx, llmMessages, g.injectionConfig.ClassifierModel, "injection_classification", g.injectionClassificationSchema(), classification); err != nil {
+		return nil, err
+	}
+
+	if !classification.Injection || float64(classification.Confidence) <= config.INJECTION_CLASSIFIER_THRESHOLD {
+		return nil, nil
+	}
+
+	return guardActivation.
+		SetReason(entity.CLASSIFIER_GUARD_REASON).
+		SetScore(float64(classification.Confidence) / 100), nil
+}
+
+// Record implements GuardService.
+func (g *GuardServiceImpl) Record(ctx context.Context, guardActivation *entity.GuardActivation) error {
+	return g.guardActivationRepo.Create(ctx, guardActivation)
+}
+
+// IsFlagged implements GuardService.
+func (g *GuardServiceImpl) IsFlagged(ctx context.Context, interviewID uint) (bool, error) {
+	guardActivations, err := g.guardActivationRepo.ListByInterviewID(ctx, interviewID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, guardActivation := range guardActivations {
+		if guardActivation.Type == entity.PROMPT_INJECTION_GUARD && guardActivation.Action == entity.FLAGGED_GUARD_ACTION {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (g *GuardServiceImpl) getInjectionAction() entity.GuardAction {
+	switch g.injectionConfig.Policy {
+	case config.REFUSE_INJECTION_POLICY:
+		return entity.REFUSED_GUARD_ACTION
+	case config.WARN_INJECTION_POLICY:
+		return entity.WARNED_GUARD_ACTION
+	default:
+		return entity.FLAGGED_GUARD_ACTION
+	}
+}
+
+func (g *GuardServiceImpl) injectionClassificationSchema() *model.JSONSchema {
+	return model.NewJSONSchema(model.JSON_SCHEMA_OBJECT).
+		AddProperty("injection", model.NewJSONSchema(model.JSON_SCHEMA_BOOLEAN)).
+		AddProperty("confidence", model.NewJSONSchema(model.JSON_SCHEMA_INTEGER).SetRange(0, 100)).
+		DisallowAdditionalProperties()
+}
+
+func (g *GuardServiceImpl) countCodeLines(content string) int {
+	count := 0
+	for line := range strings.SplitSeq(content, "\n") {
+		if g.isCodeLine(line) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *GuardServiceImpl) isCodeLine(line string) bool {
+	if codeLinePattern.MatchString(line) {
+		return true
+	}
+
+	match := assignmentLinePattern.FindStringSubmatch(line)
+	return match != nil && !proseWordsPattern.MatchString(match[2])
+}
+
+// Either a list of steps or sentences that open with sequence words, the words in the middle of a sentence
+// are left out because a hint uses them in passing
+func (g *GuardServiceImpl) isAlgorithmWalkthrough(content string) bool {
+	if len(algorithmStepPattern.FindAllString(content, -1)) > config.GUARD_MAX_ALGORITHM_STEPS {
+		return true
+	}
+
+	proseSteps := 0
+	for _, sentence := range sentencePattern.FindAllString(content, -1) {
+		if sequenceStepPattern.MatchString(strings.TrimSpace(sentence)) {
+			proseSteps++
+		}
+	}
+
+	return proseSteps > config.GUARD_MAX_PROSE_ALGORITHM_STEPS
+}
+
+// The share of the word sequences of the content that also appear in the reference solution
+func (g *GuardServiceImpl) getReferenceOverlap(content, referenceSolution string) float64 {
+	if strings.TrimSpace(referenceSolution) == "" {
+		return 0
+	}
+
+	contentShingles := g.getShingles(content)
+	if len(contentShingles) == 0 {
+		return 0
+	}
+
+	referenceShingles := g.getShingles(referenceSolution)
+
+	overlapCount := 0
+	for shingle := range contentShingles {
+		if _, ok := referenceShingles[shingle]; ok {
+			overlapCount++
+		}
+	}
+
+	return float64(overlapCount) / float64(len(contentShingles))
+}
+
+func (g *GuardServiceImpl) getShingles(content string) map[string]struct{} {
+	words := wordPattern.FindAllString(strings.ToLower(content), -1)
+
+	shingles := make(map[string]struct{})
+	for index := 0; index+config.GUARD_REFERENCE_SHINGLE_SIZE <= len(words); index++ {
+		shingles[strings.Join(words[index:index+config.GUARD_REFERENCE_SHINGLE_SIZE], " ")] = struct{}{}
+	}
+	return shingles
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type IntentSampleService interface {
+	CaptureSample(ctx context.Context, interviewID uint, sentence string, intentDetail *model.IntentDetail) error
+	ListLowConfidenceSamples(ctx context.Context, maxConfidence float64, limit, offset uint) (*model.IntentSamples, *model.Pagination, error)
+	// An empty intent confirms the predicted intent
+	LabelSample(ctx context.Context, labelerUserID uint, sampleID string, intent model.Intent) error
+	// Writes the labeled samples in fastText format and returns the number of samples written
+	ExportLabeledSamples(ctx context.Context, w io.Writer) (uint, error)
+}
+
+func NewIntentSampleService(
+	intentSampleRepo repo.IntentSampleRepo,
+) IntentSampleService {
+	return &IntentSampleServiceImpl{
+		intentSampleRepo: intentSampleRepo,
+	}
+}
+
+type IntentSampleServiceImpl struct {
+	intentSampleRepo repo.IntentSampleRepo
+}
+
+// CaptureSample implements IntentSampleService.
+func (i *IntentSampleServiceImpl) CaptureSample(ctx context.Context, interviewID uint, sentence string, intentDetail *model.IntentDetail) error {
+	if !intentDetail.Exists() {
+		return fmt.Errorf("intent cannot be nil when capturing sample: %w", common.ErrInternalServerError)
+	}
+
+	if strings.TrimSpace(sentence) == "" {
+		return nil
+	}
+
+	intent, score := intentDetail.GetIntentWithHighestConfidenceWithScoreOutOf100()
+
+	sample := entity.NewIntentSample().
+		SetInterviewID(interviewID).
+		SetContent(sentence).
+		SetPredictedIntent(intent).
+		SetConfidence(score)
+
+	if _, err := i.intentSampleRepo.Create(ctx, sample); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListLowConfidenceSamples implements IntentSampleService.
+func (i *IntentSampleServiceImpl) ListLowConfidenceSamples(ctx context.Context, maxConfidence float64, limit, offset uint) (*model.IntentSamples, *model.Pagination, error) {
+	samples, total, err := i.intentSampleRepo.ListUnlabeledBelowConfidence(ctx, maxConfidence, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sampleModels := make([]*model.IntentSample, 0)
+	for _, sample := range samples {
+		sampleModel := model.NewIntentSample().
+			SetID(sample.UUID).
+			SetContent(sample.Content).
+			SetPredictedIntent(sample.PredictedIntent).
+			SetConfidence(sample.Confidence).
+			SetCreateTimestampS(util.MillisToSeconds(sample.CreateTimestampMS))
+
+		sampleModels = append(sampleModels, sampleModel)
+	}
+
+	pagination := model.NewPagination().
+		SetTotal(total).
+		SetLimit(limit).
+		SetOffset(offset).
+		SetHasNext(offset+limit < total).
+		SetHasPrev(offset > 0)
+
+	intentSamples := model.NewIntentSamples().
+		SetSamples(sampleModels)
+
+	return intentSamples, pagination, nil
+}
+
+// LabelSample implements IntentSampleService.
+func (i *IntentSampleServiceImpl) LabelSample(ctx context.Context, labelerUserID uint, sampleID string, intent model.Intent) error {
+	sample, err := i.intentSampleRepo.GetByUUID(ctx, sampleID)
+	if err != nil {
+		return err
+	}
+
+	if intent == "" {
+		intent = sample.PredictedIntent
+	}
+
+	if !intent.IsValidLabel() {
+		return fmt.Errorf("invalid intent label %s: %w", intent, common.ErrBadRequest)
+	}
+
+	sample.Label(intent, labelerUserID)
+
+	if err := i.intentSampleRepo.Update(ctx, sample); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ExportLabeledSamples implements IntentSampleService.
+func (i *IntentSampleServiceImpl) ExportLabeledSamples(ctx context.Context, w io.Writer) (uint, error) {
+	samples, err := i.intentSampleRepo.ListLabeledUnexported(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint
+	for _, sample := range samples {
+		// The training script cleans and shuffles the data, so only the newlines need to be removed here
+		content := strings.Join(strings.Fields(sample.Content), " ")
+		line := fmt.Sprintf("__label__%s %s\n", sample.GetLabeledIntent(), content)
+
+		if _, err := io.WriteString(w, line); err != nil {
+			return count, fmt.Errorf("unable to write intent sample %d, %s: %w", sample.ID, err, common.ErrInternalServerError)
+		}
+
+		sample.MarkExported()
+		if err := i.intentSampleRepo.Update(ctx, sample); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
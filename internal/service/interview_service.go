@@ -4,18 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
-	"time"
+	"strings"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
 	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
 type InterviewService interface {
 	HandleInterviewTimesUp(ctx context.Context, interviewID uint) (*model.WebSocketMessage, error)
+	// The buffer of the candidate is flushed, so the caller has to make sure that nothing is written to it at the same time
+	HandleInterviewTimeWarning(ctx context.Context, interviewID uint, minutesRemaining uint) (*model.InterviewerResponse, error)
+	GetTimeRemainingS(ctx context.Context, interviewID uint) (uint, error)
 	PrepareToListen(ctx context.Context, interviewID uint) error
 	PauseOngoingInterview(ctx context.Context, interviewID uint) error
 	AbandonCandidateUnfinishedInterview(ctx context.Context, userID uint) error
@@ -24,54 +29,91 @@ type InterviewService interface {
 	GetCandidateOngoingInterview(ctx context.Context, userID uint) (*model.Interview, error)
 	GetCandidateUnfinishedInterview(ctx context.Context, userID uint) (*model.Interview, error)
 	GetHistory(ctx context.Context, userID, limit, offset uint) (*model.InterviewHistory, *model.Pagination, error)
-	SetUpNewInterviewForCandidate(ctx context.Context, userID uint, externalQuestionID, description string) (string, error)
+	// The interview ID here is the UUID, only the candidate of the interview can replay it
+	GetReplay(ctx context.Context, userID uint, interviewID string) (*model.InterviewReplay, error)
+	// The transcript ID here is the UUID, the caller has to close the content
+	GetTranscriptAudio(ctx context.Context, userID uint, transcriptID string) (*model.FileContent, error)
+	// The signature of the URL from TranscriptManager.GetAudioURL stands in for the session, the transcript ID here is the UUID
+	GetSignedTranscriptAudio(ctx context.Context, transcriptID string, expiresAtS int64, signature string) (*model.FileContent, error)
+	// An empty mode sets up a voice interview
+	SetUpNewInterviewForCandidate(ctx context.Context, userID uint, externalQuestionID, description string, mode entity.InterviewMode) (string, error)
 	JoinInterview(ctx context.Context, interviewID uint) error
+	// The replies are the first server messages after a join, in the order that they should be played.
+	// A new interview is greeted and introduced, a resumed one is welcomed back with a recap
+	OpenConversation(ctx context.Context, interviewID uint) ([]*model.InterviewerResponse, error)
 	// Deprecated
 	ProcessIncomingMessage(ctx context.Context, interviewID uint, message *model.WebSocketMessage) (*model.WebSocketMessage, error)
 	ProcessCandidateMessage(ctx context.Context, interviewID uint, chunk, code string) (*model.InterviewerResponse, error)
 }
 
 func NewInterviewService(
-	aiUseCase AIUseCase,
+	replyService ReplyService,
 	userService UserService,
 	authService AuthService,
 	reviewService ReviewService,
 	questionService QuestionService,
 	transcriptManager TranscriptManager,
-	fileRepo repo.FileRepo,
+	intentSampleService IntentSampleService,
+	ttsCacheService TTSCacheService,
+	speechNormalizer SpeechNormalizer,
+	cannedPhraseService CannedPhraseService,
+	promptService PromptService,
+	experimentService ExperimentService,
+	guardService GuardService,
 	reviewRepo repo.ReviewRepo,
+	rubricScoreRepo repo.RubricScoreRepo,
+	feedbackItemRepo repo.FeedbackItemRepo,
 	questionRepo repo.QuestionRepo,
 	interviewRepo repo.InterviewRepo,
+	interviewEventRepo repo.InterviewEventRepo,
 	messageQueueRepo repo.MessageQueueProducerRepo,
 	intentClassificationRepo repo.IntentClassificationRepo,
 ) InterviewService {
 	return &InterviewServiceImpl{
-		aiUseCase:                aiUseCase,
+		replyService:             replyService,
 		authService:              authService,
 		userService:              userService,
 		reviewService:            reviewService,
 		questionService:          questionService,
 		transcriptManager:        transcriptManager,
-		fileRepo:                 fileRepo,
+		intentSampleService:      intentSampleService,
+		ttsCacheService:          ttsCacheService,
+		speechNormalizer:         speechNormalizer,
+		cannedPhraseService:      cannedPhraseService,
+		promptService:            promptService,
+		experimentService:        experimentService,
+		guardService:             guardService,
 		reviewRepo:               reviewRepo,
+		rubricScoreRepo:          rubricScoreRepo,
+		feedbackItemRepo:         feedbackItemRepo,
 		questionRepo:             questionRepo,
 		interviewRepo:            interviewRepo,
+		interviewEventRepo:       interviewEventRepo,
 		messageQueueRepo:         messageQueueRepo,
 		intentClassificationRepo: intentClassificationRepo,
 	}
 }
 
 type InterviewServiceImpl struct {
-	aiUseCase                AIUseCase
+	replyService             ReplyService
 	userService              UserService
 	authService              AuthService
 	reviewService            ReviewService
 	questionService          QuestionService
 	transcriptManager        TranscriptManager
-	fileRepo                 repo.FileRepo
+	intentSampleService      IntentSampleService
+	ttsCacheService          TTSCacheService
+	speechNormalizer         SpeechNormalizer
+	cannedPhraseService      CannedPhraseService
+	promptService            PromptService
+	experimentService        ExperimentService
+	guardService             GuardService
 	reviewRepo               repo.ReviewRepo
+	rubricScoreRepo          repo.RubricScoreRepo
+	feedbackItemRepo         repo.FeedbackItemRepo
 	questionRepo             repo.QuestionRepo
 	interviewRepo            repo.InterviewRepo
+	interviewEventRepo       repo.InterviewEventRepo
 	intentClassificationRepo repo.IntentClassificationRepo
 	messageQueueRepo         repo.MessageQueueProducerRepo
 }
@@ -88,6 +130,8 @@ func (i *InterviewServiceImpl) JoinInterview(ctx context.Context, interviewID ui
 
 	if !interview.ReviewExists() {
 		review := entity.NewReview().
+			SetInterviewID(interview.ID).
+			SetVersion(1).
 			SetFeedback("The interview is still ongoing")
 
 		reviewID, err := i.reviewRepo.Create(ctx, review)
@@ -98,8 +142,10 @@ func (i *InterviewServiceImpl) JoinInterview(ctx context.Context, interviewID ui
 		interview.SetReviewID(reviewID)
 	}
 
+	eventType := entity.RESUME_INTERVIEW_EVENT
 	if !interview.HasStarted() {
 		interview.Start()
+		eventType = entity.START_INTERVIEW_EVENT
 	}
 
 	interview.
@@ -110,16 +156,136 @@ func (i *InterviewServiceImpl) JoinInterview(ctx context.Context, interviewID ui
 		return err
 	}
 
-	return nil
+	return i.recordInterviewEvent(ctx, interview.ID, eventType)
+}
+
+// OpenConversation implements InterviewService.
+func (i *InterviewServiceImpl) OpenConversation(ctx context.Context, interviewID uint) ([]*model.InterviewerResponse, error) {
+	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	resumed, err := i.hasConversationStarted(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	phrase := model.GREETING_CANNED_PHRASE
+	if resumed {
+		phrase = model.RECONNECT_WELCOME_CANNED_PHRASE
+	}
+
+	greeting, err := i.speakCannedPhrase(ctx, interview, phrase, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	experimentVariant, err := i.experimentService.GetVariant(ctx, interview)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := i.replyService.GetPromptScope(interview, experimentVariant)
+
+	var prompt *model.RenderedPrompt
+	if resumed {
+		prompt, err = i.getRecapPrompt(ctx, interview, scope)
+	} else {
+		prompt, err = i.getIntroductionPrompt(ctx, interview, scope)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	llmMessages, err := i.transcriptManager.GetTranscriptHistoryInLLMMessageFormat(ctx, interviewID, experimentVariant.GetModel())
+	if err != nil {
+		return nil, err
+	}
+
+	// The introduction and the recap can give away the solution like any other reply, so they are guarded the same way
+	replyToCandidate, guardActivation, err := i.replyService.ReplyWithPrompt(ctx, llmMessages, prompt, scope, experimentVariant.GetModel())
+	if err != nil {
+		return nil, err
+	}
+	i.recordGuardActivation(ctx, interviewID, guardActivation)
+
+	audioLocation, err := i.generateSpeechReply(ctx, interview, scope, replyToCandidate)
+	if err != nil {
+		return nil, err
+	}
+
+	opening, err := i.writeInterviewerResponse(ctx, interviewID, replyToCandidate, audioLocation, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*model.InterviewerResponse{greeting, opening}, nil
+}
+
+// The system prompt is always there, so anything else means that the candidate has joined before
+func (i *InterviewServiceImpl) hasConversationStarted(ctx context.Context, interviewID uint) (bool, error) {
+	transcripts, err := i.transcriptManager.GetTranscriptHistory(ctx, interviewID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, transcript := range transcripts {
+		if transcript.IsConversationTurn() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (i *InterviewServiceImpl) getIntroductionPrompt(ctx context.Context, interview *entity.Interview, scope *model.PromptScope) (*model.RenderedPrompt, error) {
+	question, err := i.questionRepo.GetByID(ctx, interview.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := model.NewPromptVariables().
+		SetQuestionDescription(question.Description)
+
+	return i.promptService.Render(ctx, model.INTERVIEWER_INTRODUCTION_PROMPT, scope, variables)
+}
+
+func (i *InterviewServiceImpl) getRecapPrompt(ctx context.Context, interview *entity.Interview, scope *model.PromptScope) (*model.RenderedPrompt, error) {
+	codeSnapshots, err := i.transcriptManager.GetCodeSnapshots(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := model.NewPromptVariables()
+	if len(codeSnapshots) > 0 {
+		variables.SetLatestCode(codeSnapshots[len(codeSnapshots)-1].Code)
+	}
+
+	return i.promptService.Render(ctx, model.INTERVIEWER_RECAP_PROMPT, scope, variables)
+}
+
+// The experiment variant is nil when the interview is not part of an experiment
+func (i *InterviewServiceImpl) getClassifierThreshold(experimentVariant *entity.ExperimentVariant) float64 {
+	return experimentVariant.GetClassifierThreshold(config.INTENT_CLASSIFICATION_DEFAULT_THRESHOLD)
 }
 
 // TODO: Add a new method here to process message that are in the buffer after certain delay for better user experience
 func (i *InterviewServiceImpl) ProcessCandidateMessage(ctx context.Context, interviewID uint, chunk, code string) (*model.InterviewerResponse, error) {
+	if err := i.transcriptManager.WriteCode(ctx, interviewID, code); err != nil {
+		return nil, err
+	}
+
 	if err := i.transcriptManager.WriteCandidate(ctx, interviewID, chunk); err != nil {
 		return nil, err
 	}
 
-	sufficient, err := i.transcriptManager.HasSufficientWordsInBuffer(ctx, interviewID)
+	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	sufficient, err := i.isCandidateMessageComplete(ctx, interview, chunk)
 	if err != nil {
 		return nil, err
 	}
@@ -128,24 +294,46 @@ func (i *InterviewServiceImpl) ProcessCandidateMessage(ctx context.Context, inte
 		return nil, nil
 	}
 
-	intent, err := i.intentClassificationRepo.ClassifyIntent(ctx, i.transcriptManager.GetSentenceInBuffer(ctx, interviewID))
+	sentence := i.transcriptManager.GetSentenceInBuffer(ctx, interviewID)
+
+	intent, err := i.intentClassificationRepo.ClassifyIntent(ctx, sentence)
 	if err != nil {
 		return nil, err
 	}
 
+	// The samples are only for retraining, the turn goes on without one
+	if err := i.intentSampleService.CaptureSample(ctx, interviewID, sentence, intent); err != nil {
+		log.Error().Err(err).Uint("interview_id", interviewID).Msg("unable to capture intent sample")
+	}
+
 	if util.IsDevEnv() {
 		intent, score := intent.GetIntentWithHighestConfidenceWithScoreOutOf100()
 		if intent == model.OTHERS {
 			fmt.Println("!!! Needs to generate reply !!!")
 		}
-		fmt.Printf("The current message chunk is '%s', the score is %f\n", i.transcriptManager.GetSentenceInBuffer(ctx, interviewID), score)
+		fmt.Printf("The current message chunk is '%s', the score is %f\n", sentence, score)
 	}
 
-	if err := i.transcriptManager.FlushCandidate(ctx, interviewID); err != nil {
+	guardResponse, err := i.guardAgainstInjection(ctx, interview, sentence, intent)
+	if err != nil {
 		return nil, err
 	}
 
-	resp, err := i.handleCandidateIntent(ctx, interviewID, intent)
+	if guardResponse.Exists() {
+		return guardResponse, nil
+	}
+
+	if err := i.flushCandidateWithIntent(ctx, interviewID, intent); err != nil {
+		return nil, err
+	}
+
+	// Loaded once for the sentence, the threshold and the reply both depend on it
+	experimentVariant, err := i.experimentService.GetVariant(ctx, interview)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := i.handleCandidateIntent(ctx, interview, experimentVariant, intent)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +358,11 @@ func (i *InterviewServiceImpl) HandleInterviewTimesUp(ctx context.Context, inter
 		return nil, err
 	}
 
-	msg, err := i.timesUp(ctx, interviewID)
+	if err := i.recordInterviewEvent(ctx, interviewID, entity.END_INTERVIEW_EVENT); err != nil {
+		return nil, err
+	}
+
+	msg, err := i.timesUp(ctx, ongoingInterview)
 	if err != nil {
 		return nil, nil
 	}
@@ -178,6 +370,42 @@ func (i *InterviewServiceImpl) HandleInterviewTimesUp(ctx context.Context, inter
 	return msg, nil
 }
 
+// HandleInterviewTimeWarning implements InterviewService.
+func (i *InterviewServiceImpl) HandleInterviewTimeWarning(ctx context.Context, interviewID uint, minutesRemaining uint) (*model.InterviewerResponse, error) {
+	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !interview.Ongoing {
+		return nil, fmt.Errorf("there is no ongoing interview :%w", common.ErrBadRequest)
+	}
+
+	// The words in the buffer were said before the warning, so they have to be written first
+	if err := i.transcriptManager.FlushCandidate(ctx, interviewID); err != nil {
+		return nil, err
+	}
+
+	variables := model.NewCannedPhraseVariables().
+		SetMinutesRemaining(minutesRemaining)
+
+	return i.speakCannedPhrase(ctx, interview, model.TIME_WARNING_CANNED_PHRASE, variables)
+}
+
+// GetTimeRemainingS implements InterviewService.
+func (i *InterviewServiceImpl) GetTimeRemainingS(ctx context.Context, interviewID uint) (uint, error) {
+	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !interview.Exists() {
+		return 0, fmt.Errorf("interview not found: %w", common.ErrNotFound)
+	}
+
+	return interview.GetTimeRemainingS(), nil
+}
+
 func (i *InterviewServiceImpl) PauseOngoingInterview(ctx context.Context, interviewID uint) error {
 	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
 	if err != nil && !errors.Is(err, common.ErrNotFound) {
@@ -188,6 +416,8 @@ func (i *InterviewServiceImpl) PauseOngoingInterview(ctx context.Context, interv
 		return fmt.Errorf("there is no ongoing interview :%w", common.ErrNotFound)
 	}
 
+	// The connection also drops after the interview has ended, which is not a pause
+	wasOngoing := interview.Ongoing
 	interview.Pause()
 
 	if err := i.transcriptManager.FlushAndRemoveInterview(ctx, interview.ID); err != nil {
@@ -198,7 +428,11 @@ func (i *InterviewServiceImpl) PauseOngoingInterview(ctx context.Context, interv
 		return err
 	}
 
-	return nil
+	if !wasOngoing {
+		return nil
+	}
+
+	return i.recordInterviewEvent(ctx, interview.ID, entity.PAUSE_INTERVIEW_EVENT)
 }
 
 func (i *InterviewServiceImpl) GetCandidateOngoingInterview(ctx context.Context, userID uint) (*model.Interview, error) {
@@ -211,7 +445,7 @@ func (i *InterviewServiceImpl) GetCandidateOngoingInterview(ctx context.Context,
 		return nil, nil
 	}
 
-	interviewModel, err := i.convertInterviewEntityToModel(ctx, ongoingInterview, nil)
+	interviewModel, err := i.convertInterviewEntityToModel(ctx, ongoingInterview, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +473,10 @@ func (i *InterviewServiceImpl) AbandonCandidateUnfinishedInterview(ctx context.C
 		return err
 	}
 
+	if err := i.recordInterviewEvent(ctx, interview.ID, entity.ABANDON_INTERVIEW_EVENT); err != nil {
+		return err
+	}
+
 	if err := i.transcriptManager.FlushAndRemoveInterview(ctx, interview.ID); err != nil {
 		return err
 	}
@@ -279,7 +517,7 @@ func (i *InterviewServiceImpl) GetCandidateUnfinishedInterview(ctx context.Conte
 		return nil, nil
 	}
 
-	interviewModel, err := i.convertInterviewEntityToModel(ctx, interview, nil)
+	interviewModel, err := i.convertInterviewEntityToModel(ctx, interview, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -312,22 +550,33 @@ func (i *InterviewServiceImpl) getQuestionFromQuestionCacheOrRepo(ctx context.Co
 	return question, nil
 }
 
-func (i *InterviewServiceImpl) convertInterviewEntityToModel(ctx context.Context, interview *entity.Interview, questionCache map[uint]*entity.Question) (*model.Interview, error) {
+// The caches are optional, the review of an interview that is not in the review cache is loaded on its own
+func (i *InterviewServiceImpl) convertInterviewEntityToModel(ctx context.Context, interview *entity.Interview, questionCache map[uint]*entity.Question, reviewCache *reviewCache) (*model.Interview, error) {
 	interviewModel := model.NewInterview().
 		SetID(interview.UUID).
 		SetQuestionAttemptCount(interview.QuestionAttemptCount).
+		SetMode(string(interview.GetMode())).
 		SetTimeRemainingS(interview.GetTimeRemainingS())
 
-	review, err := i.reviewRepo.GetByID(ctx, interview.GetReviewID())
-	if err != nil && !errors.Is(err, common.ErrNotFound) {
+	review, rubricScores, feedbackItems, err := i.getReviewFromReviewCacheOrRepo(ctx, interview.GetReviewID(), reviewCache)
+	if err != nil {
 		return nil, err
 	}
 
 	if review.Exists() {
 		interviewModel.
+			SetReviewStatus(string(review.GetStatus())).
 			SetFeedback(review.Feedback).
 			SetScore(review.Score).
-			SetPassed(review.Passed)
+			SetPassed(review.Passed).
+			SetRubric(convertRubricScoresToModel(rubricScores)).
+			SetFeedbackItems(convertFeedbackItemsToModel(feedbackItems))
+
+		if review.IsSelfConsistent() {
+			interviewModel.
+				SetScoreVariance(review.ScoreVariance).
+				SetScoreConfidence(string(review.GetConfidence()))
+		}
 	}
 
 	question, err := i.getQuestionFromQuestionCacheOrRepo(ctx, interview.QuestionID, questionCache)
@@ -348,6 +597,134 @@ func (i *InterviewServiceImpl) convertInterviewEntityToModel(ctx context.Context
 	return interviewModel, nil
 }
 
+// Holds the displayed reviews of a page of interviews together with their rubric scores and feedback items, all keyed by review ID
+type reviewCache struct {
+	reviews       map[uint]*entity.Review
+	rubricScores  map[uint][]*entity.RubricScore
+	feedbackItems map[uint][]*entity.FeedbackItem
+}
+
+// The displayed reviews, their rubric scores and their feedback items are loaded in one query each
+func (i *InterviewServiceImpl) getReviewCache(ctx context.Context, interviews []*entity.Interview) (*reviewCache, error) {
+	reviewIDs := make([]uint, 0)
+	for _, interview := range interviews {
+		if reviewID := interview.GetReviewID(); reviewID != 0 {
+			reviewIDs = append(reviewIDs, reviewID)
+		}
+	}
+
+	reviews, err := i.reviewRepo.ListByIDs(ctx, reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rubricScores, err := i.rubricScoreRepo.ListByReviewIDs(ctx, reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbackItems, err := i.feedbackItemRepo.ListByReviewIDs(ctx, reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &reviewCache{
+		reviews:       make(map[uint]*entity.Review),
+		rubricScores:  make(map[uint][]*entity.RubricScore),
+		feedbackItems: make(map[uint][]*entity.FeedbackItem),
+	}
+
+	for _, review := range reviews {
+		cache.reviews[review.ID] = review
+	}
+
+	for _, rubricScore := range rubricScores {
+		cache.rubricScores[rubricScore.ReviewID] = append(cache.rubricScores[rubricScore.ReviewID], rubricScore)
+	}
+
+	for _, feedbackItem := range feedbackItems {
+		cache.feedbackItems[feedbackItem.ReviewID] = append(cache.feedbackItems[feedbackItem.ReviewID], feedbackItem)
+	}
+
+	return cache, nil
+}
+
+// A review that is not in the cache is missing from the database as well, so it is only looked up when there is no cache
+func (i *InterviewServiceImpl) getReviewFromReviewCacheOrRepo(ctx context.Context, reviewID uint, reviewCache *reviewCache) (*entity.Review, []*entity.RubricScore, []*entity.FeedbackItem, error) {
+	if reviewCache != nil {
+		return reviewCache.reviews[reviewID], reviewCache.rubricScores[reviewID], reviewCache.feedbackItems[reviewID], nil
+	}
+
+	review, err := i.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, nil, nil, nil
+		}
+		return nil, nil, nil, err
+	}
+
+	rubricScores, err := i.rubricScoreRepo.ListByReviewID(ctx, review.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	feedbackItems, err := i.feedbackItemRepo.ListByReviewID(ctx, review.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return review, rubricScores, feedbackItems, nil
+}
+
+// The rubric is returned in the same order as entity.RUBRIC_DIMENSIONS
+func convertRubricScoresToModel(rubricScores []*entity.RubricScore) []*model.RubricScore {
+	rubricScoreByDimension := make(map[entity.RubricDimension]*entity.RubricScore)
+	for _, rubricScore := range rubricScores {
+		rubricScoreByDimension[rubricScore.Dimension] = rubricScore
+	}
+
+	rubric := make([]*model.RubricScore, 0)
+	for _, dimension := range entity.RUBRIC_DIMENSIONS {
+		rubricScore, ok := rubricScoreByDimension[dimension]
+		if !ok {
+			continue
+		}
+
+		rubricScoreModel := model.NewRubricScore().
+			SetDimension(string(rubricScore.Dimension)).
+			SetScore(rubricScore.Score).
+			SetMaxScore(entity.RUBRIC_MAX_SCORE).
+			SetJustification(rubricScore.Justification)
+
+		rubric = append(rubric, rubricScoreModel)
+	}
+
+	return rubric
+}
+
+func convertFeedbackItemsToModel(feedbackItems []*entity.FeedbackItem) []*model.FeedbackItem {
+	feedbackItemModels := make([]*model.FeedbackItem, 0)
+	for _, feedbackItem := range feedbackItems {
+		feedbackItemModel := model.NewFeedbackItem().
+			SetCategory(string(feedbackItem.Category)).
+			SetSummary(feedbackItem.Summary)
+
+		for _, evidence := range feedbackItem.Evidence {
+			evidenceModel := model.NewFeedbackEvidence().
+				SetType(string(evidence.Type)).
+				SetID(evidence.ID).
+				SetTimestampS(util.MillisToSeconds(evidence.TimestampMS)).
+				SetOffsetS(util.MillisToSeconds(evidence.OffsetMS))
+
+			feedbackItemModel.AddEvidence(evidenceModel)
+		}
+
+		feedbackItemModels = append(feedbackItemModels, feedbackItemModel)
+	}
+
+	return feedbackItemModels
+}
+
 // GetHistory implements InterviewService.
 func (i *InterviewServiceImpl) GetHistory(ctx context.Context, userID, limit, offset uint) (*model.InterviewHistory, *model.Pagination, error) {
 	interviews, total, err := i.interviewRepo.ListStartedInterviewsByUserID(ctx, userID, limit, offset)
@@ -355,11 +732,16 @@ func (i *InterviewServiceImpl) GetHistory(ctx context.Context, userID, limit, of
 		return nil, nil, err
 	}
 
+	reviewCache, err := i.getReviewCache(ctx, interviews)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	interviewModels := make([]*model.Interview, 0)
 	questionCache := make(map[uint]*entity.Question)
 
 	for _, interview := range interviews {
-		interviewModel, err := i.convertInterviewEntityToModel(ctx, interview, questionCache)
+		interviewModel, err := i.convertInterviewEntityToModel(ctx, interview, questionCache, reviewCache)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -379,7 +761,145 @@ func (i *InterviewServiceImpl) GetHistory(ctx context.Context, userID, limit, of
 	return history, pagination, nil
 }
 
-func (i *InterviewServiceImpl) SetUpNewInterviewForCandidate(ctx context.Context, userID uint, externalQuestionID, description string) (string, error) {
+// GetReplay implements InterviewService.
+func (i *InterviewServiceImpl) GetReplay(ctx context.Context, userID uint, interviewID string) (*model.InterviewReplay, error) {
+	interview, err := i.interviewRepo.GetByUUID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Interviews of other candidates are reported as missing so that their existence is not leaked
+	if interview.UserID != userID {
+		return nil, fmt.Errorf("interview not found: %w", common.ErrNotFound)
+	}
+
+	if !interview.HasStarted() {
+		return nil, fmt.Errorf("the interview has not started yet: %w", common.ErrBadRequest)
+	}
+
+	interviewModel, err := i.convertInterviewEntityToModel(ctx, interview, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	startTimestampMS := util.FromPtr(interview.StartTimestampMS)
+	replay := model.NewInterviewReplay().
+		SetInterview(interviewModel)
+
+	transcripts, err := i.transcriptManager.GetTranscriptHistory(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transcript := range transcripts {
+		turn := model.NewTranscriptTurn().
+			SetID(transcript.UUID).
+			SetContent(transcript.Content).
+			SetOffsetMS(max(transcript.CreateTimestampMS-startTimestampMS, 0))
+
+		switch transcript.Role {
+		case entity.ASSISTANT:
+			audioURL, err := i.transcriptManager.GetAudioURL(ctx, transcript)
+			if err != nil {
+				return nil, err
+			}
+			turn.
+				SetSpeaker(model.INTERVIEWER_SPEAKER).
+				SetAudioURL(audioURL)
+		case entity.USER:
+			turn.
+				SetSpeaker(model.CANDIDATE_SPEAKER).
+				SetIntent(string(transcript.Intent), transcript.IntentConfidence)
+		default:
+			continue
+		}
+
+		replay.AppendTurn(turn)
+	}
+
+	codeSnapshots, err := i.transcriptManager.GetCodeSnapshots(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, codeSnapshot := range codeSnapshots {
+		replay.AppendCodeSnapshot(model.NewCodeSnapshot().
+			SetID(codeSnapshot.UUID).
+			SetCode(codeSnapshot.Code).
+			SetOffsetMS(max(codeSnapshot.CreateTimestampMS-startTimestampMS, 0)))
+	}
+
+	interviewEvents, err := i.interviewEventRepo.ListByInterviewIDAsc(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, interviewEvent := range interviewEvents {
+		replay.AppendEvent(model.NewInterviewEvent().
+			SetType(string(interviewEvent.Type)).
+			SetOffsetMS(max(interviewEvent.CreateTimestampMS-startTimestampMS, 0)))
+	}
+
+	return replay, nil
+}
+
+// GetTranscriptAudio implements InterviewService.
+func (i *InterviewServiceImpl) GetTranscriptAudio(ctx context.Context, userID uint, transcriptID string) (*model.FileContent, error) {
+	transcript, err := i.transcriptManager.GetTranscript(ctx, transcriptID)
+	if err != nil {
+		return nil, err
+	}
+
+	interview, err := i.interviewRepo.GetByID(ctx, transcript.InterviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transcripts of other candidates are reported as missing so that their existence is not leaked
+	if interview.UserID != userID {
+		return nil, fmt.Errorf("transcript not found: %w", common.ErrNotFound)
+	}
+
+	return i.transcriptManager.OpenAudio(ctx, transcript)
+}
+
+// GetSignedTranscriptAudio implements InterviewService.
+func (i *InterviewServiceImpl) GetSignedTranscriptAudio(ctx context.Context, transcriptID string, expiresAtS int64, signature string) (*model.FileContent, error) {
+	transcript, err := i.transcriptManager.GetTranscript(ctx, transcriptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.transcriptManager.VerifyAudioURLSignature(transcript, expiresAtS, signature); err != nil {
+		return nil, err
+	}
+
+	return i.transcriptManager.OpenAudio(ctx, transcript)
+}
+
+func (i *InterviewServiceImpl) SetUpNewInterviewForCandidate(ctx context.Context, userID uint, externalQuestionID, description string, mode entity.InterviewMode) (string, error) {
+	if mode == "" {
+		mode = entity.VOICE_INTERVIEW_MODE
+	}
+
+	if !entity.IsValidInterviewMode(mode) {
+		return "", fmt.Errorf("invalid interview mode %s: %w", mode, common.ErrBadRequest)
+	}
+
+	// The description ends up in the system prompt of every interview on the question.
+	// The guard fails open, an interview is not blocked because the classifier or the DB is down
+	guardActivation, err := i.guardService.DetectInjection(ctx, description, entity.QUESTION_DESCRIPTION_GUARD_SOURCE)
+	if err != nil {
+		log.Error().Err(err).Uint("user_id", userID).Msg("unable to check the question description for injection")
+	}
+
+	if guardActivation.Exists() && guardActivation.Action != entity.FLAGGED_GUARD_ACTION {
+		if err := i.guardService.Record(ctx, guardActivation); err != nil {
+			log.Error().Err(err).Uint("user_id", userID).Msg("unable to record the guard activation")
+		}
+		return "", fmt.Errorf("question description contains instructions for the interviewer: %w", common.ErrBadRequest)
+	}
+
 	questionID, err := i.questionService.GetOrCreateQuestion(ctx, externalQuestionID, description)
 	if err != nil {
 		return "", err
@@ -400,6 +920,9 @@ func (i *InterviewServiceImpl) SetUpNewInterviewForCandidate(ctx context.Context
 	}
 
 	if unstartedInterview.Exists() {
+		// The candidate can change their mind about the mode until the interview starts
+		unstartedInterview.SetMode(mode)
+
 		freshToken, err := i.validateInterviewSetUpCount(ctx, unstartedInterview)
 		if err != nil {
 			return "", err
@@ -418,19 +941,32 @@ func (i *InterviewServiceImpl) SetUpNewInterviewForCandidate(ctx context.Context
 		return "", err
 	}
 
+	experimentVariant, err := i.experimentService.AssignVariant(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
 	interview := entity.NewInterview().
 		SetUserID(userID).
 		SetQuestionID(questionID).
 		SetToken(i.authService.GenerateRandomToken()).
 		SetQuestionAttemptCount(questionCount + 1).
 		SetSetupCount(1).
-		SetAllocatedDurationS(setting.InterviewDurationS)
+		SetAllocatedDurationS(setting.InterviewDurationS).
+		SetMode(mode).
+		SetExperimentVariant(experimentVariant)
 
 	id, err := i.interviewRepo.Create(ctx, interview)
 	if err != nil {
 		return "", err
 	}
 
+	if guardActivation.Exists() {
+		if err := i.guardService.Record(ctx, guardActivation.SetInterviewID(id)); err != nil {
+			log.Error().Err(err).Uint("interview_id", id).Msg("unable to record the guard activation")
+		}
+	}
+
 	if err := i.PrepareToListen(ctx, id); err != nil {
 		return "", nil
 	}
@@ -440,11 +976,20 @@ func (i *InterviewServiceImpl) SetUpNewInterviewForCandidate(ctx context.Context
 
 // TODO: Add a new method here to process message that are in the buffer after certain delay for better user experience
 func (i *InterviewServiceImpl) ProcessIncomingMessage(ctx context.Context, interviewID uint, message *model.WebSocketMessage) (*model.WebSocketMessage, error) {
+	if err := i.transcriptManager.WriteCode(ctx, interviewID, util.FromPtr(message.Code)); err != nil {
+		return nil, err
+	}
+
 	if err := i.transcriptManager.WriteCandidate(ctx, interviewID, util.FromPtr(message.Chunk)); err != nil {
 		return nil, err
 	}
 
-	sufficient, err := i.transcriptManager.HasSufficientWordsInBuffer(ctx, interviewID)
+	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	sufficient, err := i.isCandidateMessageComplete(ctx, interview, util.FromPtr(message.Chunk))
 	if err != nil {
 		return nil, err
 	}
@@ -453,24 +998,45 @@ func (i *InterviewServiceImpl) ProcessIncomingMessage(ctx context.Context, inter
 		return nil, nil
 	}
 
-	intent, err := i.intentClassificationRepo.ClassifyIntent(ctx, i.transcriptManager.GetSentenceInBuffer(ctx, interviewID))
+	sentence := i.transcriptManager.GetSentenceInBuffer(ctx, interviewID)
+
+	intent, err := i.intentClassificationRepo.ClassifyIntent(ctx, sentence)
 	if err != nil {
 		return nil, err
 	}
 
+	// The samples are only for retraining, the turn goes on without one
+	if err := i.intentSampleService.CaptureSample(ctx, interviewID, sentence, intent); err != nil {
+		log.Error().Err(err).Uint("interview_id", interviewID).Msg("unable to capture intent sample")
+	}
+
 	if util.IsDevEnv() {
 		intent, score := intent.GetIntentWithHighestConfidenceWithScoreOutOf100()
 		if intent == model.OTHERS {
 			fmt.Println("!!! Needs to generate reply !!!")
 		}
-		fmt.Printf("The current message chunk is '%s', the score is %f\n", i.transcriptManager.GetSentenceInBuffer(ctx, interviewID), score)
+		fmt.Printf("The current message chunk is '%s', the score is %f\n", sentence, score)
 	}
 
-	if err := i.transcriptManager.FlushCandidate(ctx, interviewID); err != nil {
+	guardResponse, err := i.guardAgainstInjection(ctx, interview, sentence, intent)
+	if err != nil {
 		return nil, err
 	}
 
-	response, err := i.handleIntent(ctx, interviewID, intent)
+	if guardResponse.Exists() {
+		return model.NewServerWebsocketMessage().SetInterviewerResponse(guardResponse), nil
+	}
+
+	if err := i.flushCandidateWithIntent(ctx, interviewID, intent); err != nil {
+		return nil, err
+	}
+
+	experimentVariant, err := i.experimentService.GetVariant(ctx, interview)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := i.handleIntent(ctx, interview, experimentVariant, intent)
 	if err != nil {
 		return nil, err
 	}
@@ -478,45 +1044,45 @@ func (i *InterviewServiceImpl) ProcessIncomingMessage(ctx context.Context, inter
 	return response, nil
 }
 
-func (i *InterviewServiceImpl) handleCandidateIntent(ctx context.Context, interviewID uint, intentDetail *model.IntentDetail) (*model.InterviewerResponse, error) {
+func (i *InterviewServiceImpl) handleCandidateIntent(ctx context.Context, interview *entity.Interview, experimentVariant *entity.ExperimentVariant, intentDetail *model.IntentDetail) (*model.InterviewerResponse, error) {
 	if !intentDetail.Exists() {
 		return nil, fmt.Errorf("intent cannot be nil: %w", common.ErrInternalServerError)
 	}
 
 	intent, score := intentDetail.GetIntentWithHighestConfidenceWithScoreOutOf100()
 	if intent == model.CANDIDATE_EXPLANATION {
-		return i.listenToCandidate(ctx, interviewID)
+		return i.listenToCandidate(ctx, interview.ID)
 	}
 
 	// Others mean you would need to answer back, the candidate might be asking for clarification or hints etc etc
-	// This will only be triggered if the score is more than 70 out of 100
+	// This will only be triggered if the score is more than the threshold, which is 70 out of 100 by default
 	if intent == model.OTHERS {
-		if score > 70 {
-			return i.answerCandidate(ctx, interviewID)
+		if score > i.getClassifierThreshold(experimentVariant) {
+			return i.answerCandidate(ctx, interview, experimentVariant)
 		}
-		return i.listenToCandidate(ctx, interviewID)
+		return i.listenToCandidate(ctx, interview.ID)
 	}
 
 	return nil, fmt.Errorf("invalid intent %v: %w,", util.ToPtr(intent), common.ErrInternalServerError)
 }
 
-func (i *InterviewServiceImpl) handleIntent(ctx context.Context, interviewID uint, intentDetail *model.IntentDetail) (*model.WebSocketMessage, error) {
+func (i *InterviewServiceImpl) handleIntent(ctx context.Context, interview *entity.Interview, experimentVariant *entity.ExperimentVariant, intentDetail *model.IntentDetail) (*model.WebSocketMessage, error) {
 	if !intentDetail.Exists() {
 		return nil, fmt.Errorf("intent cannot be nil: %w", common.ErrInternalServerError)
 	}
 
 	intent, score := intentDetail.GetIntentWithHighestConfidenceWithScoreOutOf100()
 	if intent == model.CANDIDATE_EXPLANATION {
-		return i.listen(ctx, interviewID)
+		return i.listen(ctx, interview.ID)
 	}
 
 	// Others mean you would need to answer back, the candidate might be asking for clarification or hints etc etc
-	// This will only be triggered if the score is more than 70 / 100
+	// This will only be triggered if the score is more than the threshold, which is 70 / 100 by default
 	if intent == model.OTHERS {
-		if score > 70 {
-			return i.answer(ctx, interviewID)
+		if score > i.getClassifierThreshold(experimentVariant) {
+			return i.answer(ctx, interview, experimentVariant)
 		}
-		return i.listen(ctx, interviewID)
+		return i.listen(ctx, interview.ID)
 	}
 
 	return nil, fmt.Errorf("invalid intent %v: %w,", util.ToPtr(intent), common.ErrInternalServerError)
@@ -535,6 +1101,8 @@ func (i *InterviewServiceImpl) ConsumeTokenAndStartInterview(ctx context.Context
 
 	if !interview.ReviewExists() {
 		review := entity.NewReview().
+			SetInterviewID(interview.ID).
+			SetVersion(1).
 			SetFeedback("The interview is still ongoing")
 
 		reviewID, err := i.reviewRepo.Create(ctx, review)
@@ -545,8 +1113,10 @@ func (i *InterviewServiceImpl) ConsumeTokenAndStartInterview(ctx context.Context
 		interview.SetReviewID(reviewID)
 	}
 
+	eventType := entity.RESUME_INTERVIEW_EVENT
 	if !interview.HasStarted() {
 		interview.Start()
+		eventType = entity.START_INTERVIEW_EVENT
 	}
 
 	interview.
@@ -559,6 +1129,10 @@ func (i *InterviewServiceImpl) ConsumeTokenAndStartInterview(ctx context.Context
 		return nil, err
 	}
 
+	if err := i.recordInterviewEvent(ctx, interview.ID, eventType); err != nil {
+		return nil, err
+	}
+
 	return interview, nil
 }
 
@@ -588,37 +1162,102 @@ func (i *InterviewServiceImpl) validateInterviewSetUpCount(ctx context.Context,
 	return token, nil
 }
 
-func (i *InterviewServiceImpl) PrepareToListen(ctx context.Context, interviewID uint) error {
-	description, err := i.getInterviewQuestionDescription(ctx, interviewID)
+func (i *InterviewServiceImpl) recordInterviewEvent(ctx context.Context, interviewID uint, eventType entity.InterviewEventType) error {
+	interviewEvent := entity.NewInterviewEvent().
+		SetInterviewID(interviewID).
+		SetType(eventType)
+
+	return i.interviewEventRepo.Create(ctx, interviewEvent)
+}
+
+// The sentence is stored with the label that was used to decide how to respond to it
+func (i *InterviewServiceImpl) flushCandidateWithIntent(ctx context.Context, interviewID uint, intentDetail *model.IntentDetail) error {
+	if !intentDetail.Exists() {
+		return i.transcriptManager.FlushCandidate(ctx, interviewID)
+	}
+
+	intent, score := intentDetail.GetIntentWithHighestConfidenceWithScoreOutOf100()
+	return i.transcriptManager.FlushCandidateWithIntent(ctx, interviewID, entity.Intent(intent), score)
+}
+
+// Returns the canned phrase that answered the sentence because of the injection policy, the sentence is then kept out of
+// the context of the LLM. Nil is returned for a flagged sentence, which is left for the caller to handle like any other.
+// The guard fails open, the turn goes on when the classifier or the DB is down
+func (i *InterviewServiceImpl) guardAgainstInjection(ctx context.Context, interview *entity.Interview, sentence string, intentDetail *model.IntentDetail) (*model.InterviewerResponse, error) {
+	guardActivation, err := i.guardService.DetectInjection(ctx, sentence, entity.CANDIDATE_SPEECH_GUARD_SOURCE)
 	if err != nil {
-		return err
+		log.Error().Err(err).Uint("interview_id", interview.ID).Msg("unable to check the candidate message for injection")
+		return nil, nil
 	}
 
-	prompt := fmt.Sprintf(`
-		You are a senior software engineer conducting a LeetCode-style technical interview with a candidate.
-		You have already prepared the question for the candidate, and the description of the question is as follow:
-		%s
-	`, description)
+	if !guardActivation.Exists() {
+		return nil, nil
+	}
 
-	if err := i.transcriptManager.PrepareInterviewer(ctx, interviewID, prompt); err != nil {
-		return err
+	if err := i.guardService.Record(ctx, guardActivation.SetInterviewID(interview.ID)); err != nil {
+		log.Error().Err(err).Uint("interview_id", interview.ID).Msg("unable to record the guard activation")
 	}
 
-	return nil
+	if guardActivation.Action == entity.FLAGGED_GUARD_ACTION {
+		return nil, nil
+	}
+
+	intent, score := entity.NO_INTENT, 0.0
+	if intentDetail.Exists() {
+		highestIntent, highestScore := intentDetail.GetIntentWithHighestConfidenceWithScoreOutOf100()
+		intent, score = entity.Intent(highestIntent), highestScore
+	}
+
+	if err := i.transcriptManager.FlushCandidateExcludedFromContext(ctx, interview.ID, intent, score); err != nil {
+		return nil, err
+	}
+
+	phrase := model.INJECTION_REFUSAL_CANNED_PHRASE
+	if guardActivation.Action == entity.WARNED_GUARD_ACTION {
+		phrase = model.INJECTION_WARNING_CANNED_PHRASE
+	}
+
+	return i.speakCannedPhrase(ctx, interview, phrase, nil)
+}
+
+// A typed message is complete as soon as it is sent, speech is only complete once the buffer has enough words
+func (i *InterviewServiceImpl) isCandidateMessageComplete(ctx context.Context, interview *entity.Interview, chunk string) (bool, error) {
+	if interview.IsTextMode() {
+		return strings.TrimSpace(chunk) != "", nil
+	}
+
+	return i.transcriptManager.HasSufficientWordsInBuffer(ctx, interview.ID)
 }
 
-func (i *InterviewServiceImpl) getInterviewQuestionDescription(ctx context.Context, interviewID uint) (string, error) {
+func (i *InterviewServiceImpl) PrepareToListen(ctx context.Context, interviewID uint) error {
 	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	question, err := i.questionRepo.GetByID(ctx, interview.QuestionID)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	experimentVariant, err := i.experimentService.GetVariant(ctx, interview)
+	if err != nil {
+		return err
+	}
+
+	variables := model.NewPromptVariables().
+		SetQuestionDescription(question.Description)
+
+	prompt, err := i.promptService.Render(ctx, model.INTERVIEWER_SYSTEM_PROMPT, i.replyService.GetPromptScope(interview, experimentVariant), variables)
+	if err != nil {
+		return err
 	}
 
-	return question.Description, nil
+	if err := i.transcriptManager.PrepareInterviewer(ctx, interviewID, prompt); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // TODO: Add more functionalities here like logging?
@@ -631,166 +1270,146 @@ func (i *InterviewServiceImpl) listen(ctx context.Context, interviewID uint) (*m
 	return nil, nil
 }
 
-func (i *InterviewServiceImpl) answerCandidate(ctx context.Context, interviewID uint) (*model.InterviewerResponse, error) {
-	prompt := `
-		Based on the transcript history, you are now tasked to reply to the candidate.
-		If the candidate is asking for a hint, you must answer in a way that helps them better understand the problem without giving away the solution.
-		Provide only as much information as needed to address their question directly.
-		Avoid adding extra hints or restating parts of the problem unless it's necessary for clarification.
-		If the candidate asks about constraints, clarification on the problem, edge cases, or assumptions, answer truthfully and succinctly.
-		Be clear, concise, and professional — just like you would be in a real interview.
-	`
-	replyToCandidate, err := i.generateTextReply(ctx, prompt, interviewID)
-	if err != nil {
-		return nil, err
-	}
+func (i *InterviewServiceImpl) answerCandidate(ctx context.Context, interview *entity.Interview, experimentVariant *entity.ExperimentVariant) (*model.InterviewerResponse, error) {
+	return i.replyToCandidate(ctx, interview, experimentVariant)
+}
 
-	reader, err := i.generateSpeechReply(ctx, replyToCandidate)
-	if err != nil {
+func (i *InterviewServiceImpl) timesUp(ctx context.Context, interview *entity.Interview) (*model.WebSocketMessage, error) {
+	if err := i.transcriptManager.FlushAndRemoveInterview(ctx, interview.ID); err != nil {
 		return nil, err
 	}
 
-	url, err := i.uploadVoiceReply(ctx, interviewID, reader)
+	// The connection is closing, so a canned phrase is used instead of waiting for the LLM and TTS
+	timeUp, err := i.speakCannedPhrase(ctx, interview, model.TIME_UP_CANNED_PHRASE, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := i.transcriptManager.WriteInterviewer(ctx, interviewID, replyToCandidate, url); err != nil {
-		return nil, err
-	}
-
-	resp := model.NewInterviewerResponse().
-		SetURL(url)
+	msg := model.NewServerWebsocketMessage().
+		SetInterviewerResponse(timeUp).
+		CloseConnection()
 
-	return resp, nil
+	return msg, nil
 }
 
-func (i *InterviewServiceImpl) timesUp(ctx context.Context, interviewID uint) (*model.WebSocketMessage, error) {
-	if err := i.transcriptManager.FlushAndRemoveInterview(ctx, interviewID); err != nil {
+// CandidateAsksForClarification implements InterviewScenario.
+func (i *InterviewServiceImpl) answer(ctx context.Context, interview *entity.Interview, experimentVariant *entity.ExperimentVariant) (*model.WebSocketMessage, error) {
+	reply, err := i.replyToCandidate(ctx, interview, experimentVariant)
+	if err != nil {
 		return nil, err
 	}
 
-	prompt := `
-		The interview time is up.
-		You need to thank the candidate for their time for attempting the interview and remind them that you will review the entire process and give him an appropriate score later.
-		Be clear, concise, and professional — just like you would be in a real interview.
-	`
+	msg := model.NewServerWebsocketMessage().
+		SetInterviewerResponse(reply)
 
-	replyToCandidate, err := i.generateTextReply(ctx, prompt, interviewID)
+	return msg, nil
+}
+
+// Shared by the websocket and the proxy flows, the reply is written to the transcript before it is returned
+func (i *InterviewServiceImpl) replyToCandidate(ctx context.Context, interview *entity.Interview, experimentVariant *entity.ExperimentVariant) (*model.InterviewerResponse, error) {
+	scope := i.replyService.GetPromptScope(interview, experimentVariant)
+
+	llmMessages, err := i.transcriptManager.GetTranscriptHistoryInLLMMessageFormat(ctx, interview.ID, experimentVariant.GetModel())
 	if err != nil {
 		return nil, err
 	}
 
-	reader, err := i.generateSpeechReply(ctx, replyToCandidate)
+	prompt, replyToCandidate, guardActivation, err := i.replyService.ReplyToCandidate(ctx, llmMessages, scope, experimentVariant.GetModel())
 	if err != nil {
 		return nil, err
 	}
 
-	url, err := i.uploadVoiceReply(ctx, interviewID, reader)
+	i.recordGuardActivation(ctx, interview.ID, guardActivation)
+
+	audioLocation, err := i.generateSpeechReply(ctx, interview, scope, replyToCandidate)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := i.transcriptManager.WriteInterviewer(ctx, interviewID, replyToCandidate, url); err != nil {
-		return nil, err
-	}
+	return i.writeInterviewerResponse(ctx, interview.ID, replyToCandidate, audioLocation, prompt)
+}
 
-	msg := model.NewServerWebsocketMessage().
-		SetURL(url).
-		CloseConnection()
+// The reply has already been made safe, so it is not thrown away when the activation cannot be recorded
+func (i *InterviewServiceImpl) recordGuardActivation(ctx context.Context, interviewID uint, guardActivation *entity.GuardActivation) {
+	if !guardActivation.Exists() {
+		return
+	}
 
-	return msg, nil
+	if err := i.guardService.Record(ctx, guardActivation.SetInterviewID(interviewID)); err != nil {
+		log.Error().Err(err).Uint("interview_id", interviewID).Msg("unable to record the guard activation")
+	}
 }
 
-// CandidateAsksForClarification implements InterviewScenario.
-func (i *InterviewServiceImpl) answer(ctx context.Context, interviewID uint) (*model.WebSocketMessage, error) {
-	prompt := `
-		Based on the transcript history, you are now tasked to reply to the candidate.
-		If the candidate is asking for a hint, you must answer in a way that helps them better understand the problem without giving away the solution.
-		Provide only as much information as needed to address their question directly.
-		Avoid adding extra hints or restating parts of the problem unless it's necessary for clarification.
-		If the candidate asks about constraints, clarification on the problem, edge cases, or assumptions, answer truthfully and succinctly.
-		Be clear, concise, and professional — just like you would be in a real interview.
-	`
-	replyToCandidate, err := i.generateTextReply(ctx, prompt, interviewID)
+// The phrase is written to the transcript like any other interviewer reply, it is only spoken in voice interviews
+func (i *InterviewServiceImpl) speakCannedPhrase(ctx context.Context, interview *entity.Interview, phrase model.CannedPhrase, variables *model.CannedPhraseVariables) (*model.InterviewerResponse, error) {
+	if interview.IsTextMode() {
+		text, err := i.cannedPhraseService.Render(phrase, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		return i.writeInterviewerResponse(ctx, interview.ID, text, nil, nil)
+	}
+
+	text, audioLocation, err := i.cannedPhraseService.Speak(ctx, phrase, variables)
 	if err != nil {
 		return nil, err
 	}
 
-	reader, err := i.generateSpeechReply(ctx, replyToCandidate)
+	return i.writeInterviewerResponse(ctx, interview.ID, text, audioLocation, nil)
+}
+
+// Every reply is sent with its text and transcript so that the client can show captions and match them with the replay.
+// The audio is played through the same authenticated route as the replay, so the storage is never exposed to the client
+func (i *InterviewServiceImpl) writeInterviewerResponse(ctx context.Context, interviewID uint, text string, audioLocation *model.FileLocation, prompt *model.RenderedPrompt) (*model.InterviewerResponse, error) {
+	transcript, err := i.transcriptManager.WriteInterviewer(ctx, interviewID, text, audioLocation, prompt)
 	if err != nil {
 		return nil, err
 	}
 
-	url, err := i.uploadVoiceReply(ctx, interviewID, reader)
+	url, err := i.transcriptManager.GetAudioURL(ctx, transcript)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := i.transcriptManager.WriteInterviewer(ctx, interviewID, replyToCandidate, url); err != nil {
+	sequence, err := i.transcriptManager.GetInterviewerSequence(ctx, transcript)
+	if err != nil {
 		return nil, err
 	}
 
-	msg := model.NewServerWebsocketMessage().
-		SetURL(url)
+	resp := model.NewInterviewerResponse().
+		SetURL(url).
+		SetText(transcript.Content).
+		SetMessageID(uuid.NewString()).
+		SetSequence(sequence).
+		SetTranscriptID(transcript.UUID)
 
-	return msg, nil
+	return resp, nil
 }
 
-func (i *InterviewServiceImpl) generateSpeechReply(ctx context.Context, content string) (io.Reader, error) {
-	instruction := `
-		You are a senior software engineer conducting a LeetCode-style technical interview. 
-		Speak clearly and at a measured pace. Use a calm, thoughtful, and professional tone, as if you're guiding a candidate through the problem. 
-		Pause briefly between key points. 
-		Avoid sounding robotic—speak naturally and deliberately, like in a real conversation.
-	`
-
-	reader, err := i.aiUseCase.GenerateSpeechReply(ctx, content, instruction)
-	if err != nil {
-		return nil, err
+// Returns the location to store with the transcript
+func (i *InterviewServiceImpl) generateSpeechReply(ctx context.Context, interview *entity.Interview, scope *model.PromptScope, content string) (*model.FileLocation, error) {
+	// Text interviews are never synthesized, so there is nothing to store or to play
+	if interview.IsTextMode() {
+		return nil, nil
 	}
 
-	return reader, nil
-}
-
-func (i *InterviewServiceImpl) uploadVoiceReply(ctx context.Context, interviewID uint, reader io.Reader) (string, error) {
 	defer func() {
 		if util.IsDevEnv() {
 			fmt.Println("Finished sending reply to frontend")
 		}
 	}()
 
-	interview, err := i.interviewRepo.GetByID(ctx, interviewID)
-	if err != nil {
-		return "", err
-	}
-
-	path := fmt.Sprintf("user_%d/interview_%d/timestamp_ms_%d.mp3", interview.UserID, interviewID, time.Now().UnixMilli())
-
-	url, err := i.fileRepo.Upload(ctx, path, reader, nil)
+	instruction, err := i.promptService.Render(ctx, model.INTERVIEWER_SPEECH_PROMPT, scope, nil)
 	if err != nil {
-		return "", err
-	}
-
-	return url, nil
-}
-
-func (i *InterviewServiceImpl) generateTextReply(ctx context.Context, prompt string, interviewID uint) (string, error) {
-	llmMessages, err := i.transcriptManager.GetTranscriptHistoryInLLMMessageFormat(ctx, interviewID)
-	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	latestPrompt := model.NewLLMMessage().
-		SetRole(model.ASSISTANT).
-		SetContent(prompt)
-
-	llmMessages = append(llmMessages, latestPrompt)
-
-	replyToCandidate, err := i.aiUseCase.GenerateTextReply(ctx, llmMessages)
+	// The transcript keeps the reply as it was generated, only the TTS gets the speakable version
+	audioLocation, err := i.ttsCacheService.Synthesize(ctx, i.speechNormalizer.Normalize(content), instruction.Content)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return replyToCandidate, nil
+	return audioLocation, nil
 }
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+)
+
+// The files are named <name>.<version>.tmpl, the highest version of every name is the default
+//
+//go:embed prompts/*.tmpl
+var embeddedPromptFiles embed.FS
+
+var promptFuncMap = template.FuncMap{
+	"join":    strings.Join,
+	"delimit": model.DelimitUntrustedContent,
+}
+
+var embeddedPromptTemplateMap = mustLoadEmbeddedPromptTemplates()
+
+type embeddedPromptTemplates struct {
+	latestVersion string
+	templates     map[string]*template.Template
+}
+
+type PromptService interface {
+	// The most specific active override in the database is used, otherwise the embedded template
+	Render(ctx context.Context, name model.PromptName, scope *model.PromptScope, variables *model.PromptVariables) (*model.RenderedPrompt, error)
+}
+
+func NewPromptService(
+	promptTemplateRepo repo.PromptTemplateRepo,
+) PromptService {
+	return &PromptServiceImpl{
+		promptTemplateRepo: promptTemplateRepo,
+	}
+}
+
+type PromptServiceImpl struct {
+	promptTemplateRepo repo.PromptTemplateRepo
+}
+
+// Render implements PromptService.
+func (p *PromptServiceImpl) Render(ctx context.Context, name model.PromptName, scope *model.PromptScope, variables *model.PromptVariables) (*model.RenderedPrompt, error) {
+	if scope == nil {
+		scope = model.NewPromptScope()
+	}
+	if variables == nil {
+		variables = model.NewPromptVariables()
+	}
+
+	override, err := p.getOverride(ctx, name, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	embedded, ok := embeddedPromptTemplateMap[name]
+	if !ok {
+		return nil, fmt.Errorf("prompt template %s does not exist: %w", name, common.ErrInternalServerError)
+	}
+
+	var version string
+	var tmpl *template.Template
+	_, hasEmbeddedVersion := embedded.templates[scope.Version]
+	switch {
+	// An embedded template of the requested version wins over an override of another version
+	case override.Exists() && (override.Version == scope.Version || !hasEmbeddedVersion):
+		tmpl, err = template.New(string(name)).Funcs(promptFuncMap).Parse(override.Content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse prompt template %s version %s, %s: %w", name, override.Version, err, common.ErrInternalServerError)
+		}
+		version = override.Version
+	case hasEmbeddedVersion:
+		version = scope.Version
+		tmpl = embedded.templates[version]
+	default:
+		version = embedded.latestVersion
+		tmpl = embedded.templates[version]
+	}
+
+	var content strings.Builder
+	if err := tmpl.Execute(&content, variables); err != nil {
+		return nil, fmt.Errorf("unable to render prompt template %s version %s, %s: %w", name, version, err, common.ErrInternalServerError)
+	}
+
+	renderedPrompt := &model.RenderedPrompt{
+		Name:    name,
+		Version: version,
+		Content: strings.TrimSpace(content.String()),
+	}
+
+	return renderedPrompt, nil
+}
+
+// The templates are listed latest first, so the first one of the highest specificity wins.
+// The overrides of the requested version are preferred over the rest.
+func (p *PromptServiceImpl) getOverride(ctx context.Context, name model.PromptName, scope *model.PromptScope) (*entity.PromptTemplate, error) {
+	promptTemplates, err := p.promptTemplateRepo.ListActiveByName(ctx, string(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var override, versionOverride *entity.PromptTemplate
+	for _, promptTemplate := range promptTemplates {
+		if !promptTemplate.Matches(scope.QuestionID, entity.InterviewType(scope.InterviewType)) {
+			continue
+		}
+		if promptTemplate.GetSpecificity() > override.GetSpecificity() {
+			override = promptTemplate
+		}
+		if scope.Version != "" && promptTemplate.Version == scope.Version && promptTemplate.GetSpecificity() > versionOverride.GetSpecificity() {
+			versionOverride = promptTemplate
+		}
+	}
+
+	if versionOverride.Exists() {
+		return versionOverride, nil
+	}
+
+	return override, nil
+}
+
+func mustLoadEmbeddedPromptTemplates() map[model.PromptName]*embeddedPromptTemplates {
+	fileNames, err := fs.Glob(embeddedPromptFiles, "prompts/*.tmpl")
+	if err != nil {
+		panic(err)
+	}
+
+	templateMap := make(map[model.PromptName]*embeddedPromptTemplates)
+	for _, fileName := range fileNames {
+		name, version, found := strings.Cut(strings.TrimSuffix(path.Base(fileName), ".tmpl"), ".")
+		if !found {
+			panic(fmt.Sprintf("prompt template %s is not named <name>.<version>.tmpl", fileName))
+		}
+
+		content, err := embeddedPromptFiles.ReadFile(fileName)
+		if err != nil {
+			panic(err)
+		}
+
+		embedded, ok := templateMap[model.PromptName(name)]
+		if !ok {
+			embedded = &embeddedPromptTemplates{
+				templates: make(map[string]*template.Template),
+			}
+			templateMap[model.PromptName(name)] = embedded
+		}
+
+		embedded.templates[version] = template.Must(template.New(name).Funcs(promptFuncMap).Parse(string(content)))
+		if parsePromptVersion(version) > parsePromptVersion(embedded.latestVersion) {
+			embedded.latestVersion = version
+		}
+	}
+
+	return templateMap
+}
+
+// v10 has to come after v9, so the versions are compared by their number
+func parsePromptVersion(version string) int {
+	number, err := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return -1
+	}
+	return number
+}
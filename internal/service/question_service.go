@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
@@ -12,6 +13,8 @@ import (
 type QuestionService interface {
 	// Returns the internal question ID
 	GetOrCreateQuestion(ctx context.Context, externalID, description string) (uint, error)
+	// The reference solution is only used to catch the interviewer giving away the solution, an empty one removes it
+	SetReferenceSolution(ctx context.Context, externalID, referenceSolution string) error
 }
 
 func NewQuestionService(
@@ -47,3 +50,15 @@ func (q *QuestionServiceImpl) GetOrCreateQuestion(ctx context.Context, externalI
 
 	return id, nil
 }
+
+// SetReferenceSolution implements QuestionService.
+func (q *QuestionServiceImpl) SetReferenceSolution(ctx context.Context, externalID, referenceSolution string) error {
+	question, err := q.questionRepo.GetByExternalID(ctx, externalID)
+	if err != nil {
+		return err
+	}
+
+	question.SetReferenceSolution(strings.TrimSpace(referenceSolution))
+
+	return q.questionRepo.Update(ctx, question)
+}
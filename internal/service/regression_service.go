@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+)
+
+const (
+	REGRESSION_PREFERRED_ORIGINAL string = "original"
+	REGRESSION_PREFERRED_REPLAYED string = "replayed"
+	REGRESSION_PREFERRED_TIE      string = "tie"
+)
+
+type llmRegressionScores struct {
+	NoSolutionLeakage uint `json:"no_solution_leakage"`
+	Conciseness       uint `json:"conciseness"`
+	Correctness       uint `json:"correctness"`
+}
+
+func (l *llmRegressionScores) toModel() *model.RegressionScores {
+	return &model.RegressionScores{
+		NoSolutionLeakage: float64(l.NoSolutionLeakage),
+		Conciseness:       float64(l.Conciseness),
+		Correctness:       float64(l.Correctness),
+	}
+}
+
+type llmRegressionJudgement struct {
+	ReplyA    *llmRegressionScores `json:"reply_a"`
+	ReplyB    *llmRegressionScores `json:"reply_b"`
+	Preferred string               `json:"preferred"`
+	Reason    string               `json:"reason"`
+}
+
+type RegressionService interface {
+	// Replays every candidate turn of the stored interviews through the reply path with the given configuration
+	// and has the judge score the replayed replies against the original ones, nothing is saved
+	Run(ctx context.Context, regressionConfig *model.RegressionConfig) (*model.RegressionReport, error)
+}
+
+func NewRegressionService(
+	llmConfig *config.LLMConfig,
+	aiUseCase AIUseCase,
+	replyService ReplyService,
+	promptService PromptService,
+	transcriptManager TranscriptManager,
+	interviewRepo repo.InterviewRepo,
+	questionRepo repo.QuestionRepo,
+) RegressionService {
+	return &RegressionServiceImpl{
+		llmConfig:         llmConfig,
+		aiUseCase:         aiUseCase,
+		replyService:      replyService,
+		promptService:     promptService,
+		transcriptManager: transcriptManager,
+		interviewRepo:     interviewRepo,
+		questionRepo:      questionRepo,
+	}
+}
+
+type RegressionServiceImpl struct {
+	llmConfig         *config.LLMConfig
+	aiUseCase         AIUseCase
+	replyService      ReplyService
+	promptService     PromptService
+	transcriptManager TranscriptManager
+	interviewRepo     repo.InterviewRepo
+	questionRepo      repo.QuestionRepo
+}
+
+// Run implements RegressionService.
+func (r *RegressionServiceImpl) Run(ctx context.Context, regressionConfig *model.RegressionConfig) (*model.RegressionReport, error) {
+	interviews, err := r.getInterviews(ctx, regressionConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	judgePrompt, err := r.promptService.Render(ctx, model.REGRESSION_JUDGE_PROMPT, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := model.NewRegressionReport()
+	report.Model = r.getModel(regressionConfig.Model)
+	report.JudgeModel = r.getModel(regressionConfig.JudgeModel)
+	report.InterviewCount = uint(len(interviews))
+
+	for _, interview := range interviews {
+		replies, err := r.replayInterview(ctx, interview, regressionConfig, judgePrompt.Content)
+		if err != nil {
+			return nil, err
+		}
+		for _, reply := range replies {
+			report.AppendReply(reply)
+		}
+	}
+
+	r.aggregate(report)
+
+	return report, nil
+}
+
+func (r *RegressionServiceImpl) getInterviews(ctx context.Context, regressionConfig *model.RegressionConfig) ([]*entity.Interview, error) {
+	interviews := make([]*entity.Interview, 0)
+
+	if len(regressionConfig.InterviewIDs) > 0 {
+		for _, interviewID := range regressionConfig.InterviewIDs {
+			interview, err := r.interviewRepo.GetByUUID(ctx, interviewID)
+			if err != nil {
+				return nil, err
+			}
+			interviews = append(interviews, interview)
+		}
+		return interviews, nil
+	}
+
+	if regressionConfig.QuestionID == "" {
+		return nil, fmt.Errorf("either the interviews or the question to replay must be set: %w", common.ErrInvalidArgument)
+	}
+
+	question, err := r.questionRepo.GetByExternalID(ctx, regressionConfig.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !question.Exists() {
+		return nil, fmt.Errorf("question %s: %w", regressionConfig.QuestionID, common.ErrNotFound)
+	}
+
+	interviews, err = r.interviewRepo.ListEndedInterviewsByQuestionID(ctx, question.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The interviews are listed oldest first, the latest ones are the most representative of the current interviewer
+	limit := regressionConfig.Limit
+	if limit == 0 {
+		limit = config.REGRESSION_DEFAULT_INTERVIEW_LIMIT
+	}
+	if uint(len(interviews)) > limit {
+		interviews = interviews[uint(len(interviews))-limit:]
+	}
+
+	return interviews, nil
+}
+
+// A reply or judgement that fails is recorded on the reply so that one bad turn does not stop the whole run
+func (r *RegressionServiceImpl) replayInterview(ctx context.Context, interview *entity.Interview, regressionConfig *model.RegressionConfig, judgePrompt string) ([]*model.RegressionReply, error) {
+	transcripts, err := r.transcriptManager.GetTranscriptHistory(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	codeSnapshots, err := r.transcriptManager.GetCodeSnapshots(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := r.questionRepo.GetByID(ctx, interview.QuestionID)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return nil, err
+	}
+
+	// Only the template and the version of the configuration change, the question and type of the interview are kept
+	scope := r.replyService.GetPromptScope(interview, nil).
+		SetVersion(regressionConfig.PromptVersion)
+
+	// The stored system prompt is of the version that the interview ran with, so it is rendered again for the replay
+	variables := model.NewPromptVariables()
+	if question.Exists() {
+		variables.SetQuestionDescription(question.Description)
+	}
+
+	systemPrompt, err := r.promptService.Render(ctx, model.INTERVIEWER_SYSTEM_PROMPT, scope, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	replies := make([]*model.RegressionReply, 0)
+	turns := make([]*entity.Transcript, 0)
+	for _, transcript := range transcripts {
+		if !transcript.IsConversationTurn() {
+			continue
+		}
+
+		if r.isReplyToCandidate(transcript, turns) {
+			reply := &model.RegressionReply{
+				InterviewID:      interview.UUID,
+				TranscriptID:     transcript.UUID,
+				CandidateMessage: turns[len(turns)-1].Content,
+				OriginalReply:    transcript.Content,
+			}
+
+			if err := r.replayReply(ctx, interview, transcript, turns, codeSnapshots, question, scope, systemPrompt, regressionConfig, judgePrompt, reply); err != nil {
+				reply.Error = err.Error()
+			}
+			replies = append(replies, reply)
+		}
+
+		turns = append(turns, transcript)
+	}
+
+	return replies, nil
+}
+
+// The replies of the interview that were written before the prompts were tracked have no prompt name,
+// so every interviewer turn that directly follows the candidate counts for them
+func (r *RegressionServiceImpl) isReplyToCandidate(transcript *entity.Transcript, previousTurns []*entity.Transcript) bool {
+	if transcript.Role != entity.ASSISTANT || len(previousTurns) == 0 || previousTurns[len(previousTurns)-1].Role != entity.USER {
+		return false
+	}
+
+	return transcript.PromptName == "" || transcript.PromptName == string(model.INTERVIEWER_REPLY_PROMPT)
+}
+
+func (r *RegressionServiceImpl) replayReply(
+	ctx context.Context,
+	interview *entity.Interview,
+	transcript *entity.Transcript,
+	previousTurns []*entity.Transcript,
+	codeSnapshots []*entity.CodeSnapshot,
+	question *entity.Question,
+	scope *model.PromptScope,
+	systemPrompt *model.RenderedPrompt,
+	regressionConfig *model.RegressionConfig,
+	judgePrompt string,
+	reply *model.RegressionReply,
+) error {
+	llmMessages, err := r.transcriptManager.GetTranscriptHistoryBefore(ctx, interview.ID, transcript.ID, systemPrompt, regressionConfig.Model)
+	if err != nil {
+		return err
+	}
+
+	prompt, replayedReply, guardActivation, err := r.replyService.ReplyToCandidate(ctx, llmMessages, scope, regressionConfig.Model)
+	if err != nil {
+		return err
+	}
+	reply.ReplayedReply = replayedReply
+	reply.PromptVersion = prompt.Version
+	if guardActivation.Exists() {
+		reply.GuardReason = string(guardActivation.Reason)
+	}
+
+	var code string
+	for _, codeSnapshot := range codeSnapshots {
+		if codeSnapshot.CreateTimestampMS > transcript.CreateTimestampMS {
+			break
+		}
+		code = codeSnapshot.Code
+	}
+
+	var record strings.Builder
+	if question.Exists() {
+		fmt.Fprintf(&record, "Question:\n%s\n\n", model.DelimitUntrustedContent(model.QUESTION_DESCRIPTION_TAG, question.Description))
+	}
+	if strings.TrimSpace(code) != "" {
+		fmt.Fprintf(&record, "Latest code of the candidate:\n%s\n\n", model.DelimitUntrustedContent(model.CANDIDATE_CODE_TAG, code))
+	}
+	record.WriteString("Conversation:\n")
+	for _, turn := range previousTurns[max(0, len(previousTurns)-config.REGRESSION_JUDGE_CONTEXT_TURNS):] {
+		if turn.Role == entity.ASSISTANT {
+			fmt.Fprintf(&record, "Interviewer: %s\n", turn.Content)
+			continue
+		}
+		fmt.Fprintf(&record, "Candidate: %s\n", model.DelimitUntrustedContent(model.CANDIDATE_SPEECH_TAG, turn.Content))
+	}
+
+	// The position of the replies alternates so that a bias of the judge towards either position evens out
+	replayedFirst := len(previousTurns)%2 == 1
+	replyA, replyB := reply.OriginalReply, reply.ReplayedReply
+	if replayedFirst {
+		replyA, replyB = replyB, replyA
+	}
+	fmt.Fprintf(&record, "\nReply A:\n%s\n\nReply B:\n%s\n", replyA, replyB)
+
+	llmMessages = []*model.LLMMessage{
+		model.NewLLMMessage().
+			SetRole(model.SYSTEM).
+			SetContent(judgePrompt),
+		model.NewLLMMessage().
+			SetRole(model.USER).
+			SetContent(record.String()),
+	}
+
+	judgement := &llmRegressionJudgement{}
+	if err := r.aiUseCase.GenerateStructuredReply(ctx, llmMessages, regressionConfig.JudgeModel, "regression_judgement", r.judgementSchema(), judgement); err != nil {
+		return err
+	}
+
+	reply.Original, reply.Replayed = judgement.ReplyA.toModel(), judgement.ReplyB.toModel()
+	if replayedFirst {
+		reply.Original, reply.Replayed = reply.Replayed, reply.Original
+	}
+
+	switch {
+	case judgement.Preferred == "tie":
+		reply.Preferred = REGRESSION_PREFERRED_TIE
+	case (judgement.Preferred == "a") == replayedFirst:
+		reply.Preferred = REGRESSION_PREFERRED_REPLAYED
+	default:
+		reply.Preferred = REGRESSION_PREFERRED_ORIGINAL
+	}
+	reply.Reason = judgement.Reason
+
+	return nil
+}
+
+func (r *RegressionServiceImpl) judgementSchema() *model.JSONSchema {
+	scoresSchema := model.NewJSONSchema(model.JSON_SCHEMA_OBJECT).
+		AddProperty("no_solution_leakage", model.NewJSONSchema(model.JSON_SCHEMA_INTEGER).SetRange(1, 5)).
+		AddProperty("conciseness", model.NewJSONSchema(model.JSON_SCHEMA_INTEGER).SetRange(1, 5)).
+		AddProperty("correctness", model.NewJSONSchema(model.JSON_SCHEMA_INTEGER).SetRange(1, 5)).
+		DisallowAdditionalProperties()
+
+	return model.NewJSONSchema(model.JSON_SCHEMA_OBJECT).
+		AddProperty("reply_a", scoresSchema).
+		AddProperty("reply_b", scoresSchema).
+		AddProperty("preferred", model.NewJSONSchema(model.JSON_SCHEMA_STRING).SetEnum([]string{"a", "b", "tie"})).
+		AddProperty("reason", model.NewJSONSchema(model.JSON_SCHEMA_STRING)).
+		DisallowAdditionalProperties()
+}
+
+func (r *RegressionServiceImpl) aggregate(report *model.RegressionReport) {
+	original := &model.RegressionScores{}
+	replayed := &model.RegressionScores{}
+	var judgedCount float64
+	promptVersions := make([]string, 0)
+
+	for _, reply := range report.Replies {
+		report.ReplyCount++
+		if reply.PromptVersion != "" && !slices.Contains(promptVersions, reply.PromptVersion) {
+			promptVersions = append(promptVersions, reply.PromptVersion)
+		}
+		if reply.Error != "" {
+			report.FailedCount++
+			continue
+		}
+
+		judgedCount++
+		original.NoSolutionLeakage += reply.Original.NoSolutionLeakage
+		original.Conciseness += reply.Original.Conciseness
+		original.Correctness += reply.Original.Correctness
+		replayed.NoSolutionLeakage += reply.Replayed.NoSolutionLeakage
+		replayed.Conciseness += reply.Replayed.Conciseness
+		replayed.Correctness += reply.Replayed.Correctness
+
+		switch reply.Preferred {
+		case REGRESSION_PREFERRED_REPLAYED:
+			report.ReplayedWins++
+		case REGRESSION_PREFERRED_ORIGINAL:
+			report.OriginalWins++
+		default:
+			report.Ties++
+		}
+	}
+
+	report.PromptVersion = strings.Join(promptVersions, ",")
+
+	if judgedCount == 0 {
+		return
+	}
+
+	for _, scores := range []*model.RegressionScores{original, replayed} {
+		scores.NoSolutionLeakage /= judgedCount
+		scores.Conciseness /= judgedCount
+		scores.Correctness /= judgedCount
+	}
+
+	report.Original = original
+	report.Replayed = replayed
+}
+
+func (r *RegressionServiceImpl) getModel(llmModel string) string {
+	if llmModel == "" {
+		return r.llmConfig.Model
+	}
+	return llmModel
+}
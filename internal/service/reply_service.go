@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+)
+
+// The reply path of the interviewer, it is shared by the live interviews and the regression replays so that both
+// go through the same prompt and model selection
+type ReplyService interface {
+	// The variant is nil when the interview is not part of any experiment
+	GetPromptScope(interview *entity.Interview, experimentVariant *entity.ExperimentVariant) *model.PromptScope
+	// Renders the reply prompt of the scope and generates the reply to the candidate from the history, nothing is saved.
+	// A reply that gives away the solution is regenerated or softened, the returned activation is nil when that did not
+	// happen and is left for the caller to record.
+	// An empty llmModel uses the default model
+	ReplyToCandidate(ctx context.Context, llmMessages []*model.LLMMessage, scope *model.PromptScope, llmModel string) (*model.RenderedPrompt, string, *entity.GuardActivation, error)
+	// Same as ReplyToCandidate but with a prompt that the caller rendered, such as the introduction or the recap
+	ReplyWithPrompt(ctx context.Context, llmMessages []*model.LLMMessage, prompt *model.RenderedPrompt, scope *model.PromptScope, llmModel string) (string, *entity.GuardActivation, error)
+	// The prompt goes last as the instruction of the interviewer
+	Generate(ctx context.Context, llmMessages []*model.LLMMessage, prompt, llmModel string) (string, error)
+}
+
+func NewReplyService(
+	aiUseCase AIUseCase,
+	promptService PromptService,
+	guardService GuardService,
+	questionRepo repo.QuestionRepo,
+) ReplyService {
+	return &ReplyServiceImpl{
+		aiUseCase:     aiUseCase,
+		promptService: promptService,
+		guardService:  guardService,
+		questionRepo:  questionRepo,
+	}
+}
+
+type ReplyServiceImpl struct {
+	aiUseCase     AIUseCase
+	promptService PromptService
+	guardService  GuardService
+	questionRepo  repo.QuestionRepo
+}
+
+// GetPromptScope implements ReplyService.
+func (r *ReplyServiceImpl) GetPromptScope(interview *entity.Interview, experimentVariant *entity.ExperimentVariant) *model.PromptScope {
+	return model.NewPromptScope().
+		SetQuestionID(interview.QuestionID).
+		SetInterviewType(string(interview.GetType())).
+		SetVersion(experimentVariant.GetPromptVersion())
+}
+
+// ReplyToCandidate implements ReplyService.
+func (r *ReplyServiceImpl) ReplyToCandidate(ctx context.Context, llmMessages []*model.LLMMessage, scope *model.PromptScope, llmModel string) (*model.RenderedPrompt, string, *entity.GuardActivation, error) {
+	if scope == nil {
+		scope = model.NewPromptScope()
+	}
+
+	prompt, err := r.promptService.Render(ctx, model.INTERVIEWER_REPLY_PROMPT, scope, nil)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	replyToCandidate, guardActivation, err := r.ReplyWithPrompt(ctx, llmMessages, prompt, scope, llmModel)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return prompt, replyToCandidate, guardActivation, nil
+}
+
+// ReplyWithPrompt implements ReplyService.
+func (r *ReplyServiceImpl) ReplyWithPrompt(ctx context.Context, llmMessages []*model.LLMMessage, prompt *model.RenderedPrompt, scope *model.PromptScope, llmModel string) (string, *entity.GuardActivation, error) {
+	if scope == nil {
+		scope = model.NewPromptScope()
+	}
+
+	replyToCandidate, err := r.Generate(ctx, llmMessages, prompt.Content, llmModel)
+	if err != nil {
+		return "", nil, err
+	}
+
+	question, err := r.questionRepo.GetByID(ctx, scope.QuestionID)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return "", nil, err
+	}
+	referenceSolution := question.GetReferenceSolution()
+
+	reason, score := r.guardService.CheckSolutionLeak(replyToCandidate, referenceSolution)
+	if reason == "" {
+		return replyToCandidate, nil, nil
+	}
+
+	guardActivation := entity.NewGuardActivation().
+		SetType(entity.SOLUTION_LEAK_GUARD).
+		SetSource(entity.INTERVIEWER_REPLY_GUARD_SOURCE).
+		SetReason(reason).
+		SetAttempts(1).
+		SetOriginalContent(replyToCandidate)
+
+	if reason == entity.REFERENCE_OVERLAP_GUARD_REASON {
+		guardActivation.SetScore(score)
+	}
+
+	for range config.GUARD_MAX_REGENERATIONS {
+		correction, err := r.promptService.Render(ctx, model.INTERVIEWER_LEAK_CORRECTION_PROMPT, scope, model.NewPromptVariables().SetLeakReason(string(reason)))
+		if err != nil {
+			return "", nil, err
+		}
+
+		// The candidate is still waiting, so a failed regeneration falls back to softening what there is
+		regeneratedReply, err := r.Generate(ctx, llmMessages, prompt.Content+"\n\n"+correction.Content, llmModel)
+		if err != nil {
+			break
+		}
+
+		guardActivation.SetAttempts(guardActivation.Attempts + 1)
+		replyToCandidate = regeneratedReply
+
+		reason, _ = r.guardService.CheckSolutionLeak(replyToCandidate, referenceSolution)
+		if reason == "" {
+			guardActivation.
+				SetAction(entity.REGENERATED_GUARD_ACTION).
+				SetFinalContent(replyToCandidate)
+
+			return replyToCandidate, guardActivation, nil
+		}
+	}
+
+	replyToCandidate = r.guardService.SoftenSolutionLeak(replyToCandidate, referenceSolution)
+	guardActivation.
+		SetAction(entity.SOFTENED_GUARD_ACTION).
+		SetFinalContent(replyToCandidate)
+
+	return replyToCandidate, guardActivation, nil
+}
+
+// Generate implements ReplyService.
+func (r *ReplyServiceImpl) Generate(ctx context.Context, llmMessages []*model.LLMMessage, prompt, llmModel string) (string, error) {
+	latestPrompt := model.NewLLMMessage().
+		SetRole(model.ASSISTANT).
+		SetContent(prompt)
+
+	// The history of the caller is left untouched
+	messages := append(append([]*model.LLMMessage{}, llmMessages...), latestPrompt)
+
+	return r.aiUseCase.GenerateTextReply(ctx, messages, llmModel)
+}
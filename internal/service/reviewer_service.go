@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+// The interview and transcript IDs here are the UUIDs
+type ReviewerService interface {
+	// The override is created as a new review version and displayed to the candidate, the AI review is kept as is
+	OverrideReview(ctx context.Context, reviewerUserID uint, interviewID string, reviewOverride *model.ReviewOverride) error
+	// Returns the UUID of the annotation
+	AnnotateTranscript(ctx context.Context, reviewerUserID uint, interviewID, transcriptID, content string) (string, error)
+	ListAnnotations(ctx context.Context, interviewID string) (*model.TranscriptAnnotations, error)
+	// Only the author of the annotation or an admin can delete it
+	DeleteAnnotation(ctx context.Context, reviewerUserID uint, annotationID string) error
+	ListAuditLogs(ctx context.Context, interviewID string) (*model.AuditLogs, error)
+}
+
+func NewReviewerService(
+	reviewService ReviewService,
+	reviewRepo repo.ReviewRepo,
+	interviewRepo repo.InterviewRepo,
+	transcriptRepo repo.TranscriptRepo,
+	rubricScoreRepo repo.RubricScoreRepo,
+	feedbackItemRepo repo.FeedbackItemRepo,
+	transcriptAnnotationRepo repo.TranscriptAnnotationRepo,
+	auditLogRepo repo.AuditLogRepo,
+	userRepo repo.UserRepo,
+	transactionRepo repo.TransactionRepo,
+) ReviewerService {
+	return &ReviewerServiceImpl{
+		reviewService:            reviewService,
+		reviewRepo:               reviewRepo,
+		interviewRepo:            interviewRepo,
+		transcriptRepo:           transcriptRepo,
+		rubricScoreRepo:          rubricScoreRepo,
+		feedbackItemRepo:         feedbackItemRepo,
+		transcriptAnnotationRepo: transcriptAnnotationRepo,
+		auditLogRepo:             auditLogRepo,
+		userRepo:                 userRepo,
+		transactionRepo:          transactionRepo,
+	}
+}
+
+type ReviewerServiceImpl struct {
+	reviewService            ReviewService
+	reviewRepo               repo.ReviewRepo
+	interviewRepo            repo.InterviewRepo
+	transcriptRepo           repo.TranscriptRepo
+	rubricScoreRepo          repo.RubricScoreRepo
+	feedbackItemRepo         repo.FeedbackItemRepo
+	transcriptAnnotationRepo repo.TranscriptAnnotationRepo
+	auditLogRepo             repo.AuditLogRepo
+	userRepo                 repo.UserRepo
+	transactionRepo          repo.TransactionRepo
+}
+
+// OverrideReview implements ReviewerService.
+func (r *ReviewerServiceImpl) OverrideReview(ctx context.Context, reviewerUserID uint, interviewID string, reviewOverride *model.ReviewOverride) error {
+	if reviewOverride == nil {
+		return fmt.Errorf("review override cannot be nil: %w", common.ErrBadRequest)
+	}
+
+	justification := strings.TrimSpace(reviewOverride.Justification)
+	if justification == "" {
+		return fmt.Errorf("a justification is required to override a review: %w", common.ErrBadRequest)
+	}
+
+	if reviewOverride.Score != nil && *reviewOverride.Score > 100 {
+		return fmt.Errorf("score must be between 0 and 100: %w", common.ErrBadRequest)
+	}
+
+	interview, err := r.interviewRepo.GetByUUID(ctx, interviewID)
+	if err != nil {
+		return err
+	}
+
+	if !interview.HasEnded() {
+		return fmt.Errorf("the review of an unfinished interview cannot be overridden: %w", common.ErrBadRequest)
+	}
+
+	baseReview, err := r.reviewService.GetDisplayedReview(ctx, interview)
+	if err != nil {
+		return err
+	}
+
+	if !baseReview.IsReviewed() {
+		return fmt.Errorf("the review of interview %s has not been completed yet: %w", interviewID, common.ErrBadRequest)
+	}
+
+	reviews, err := r.reviewRepo.ListByInterviewID(ctx, interview.ID)
+	if err != nil {
+		return err
+	}
+
+	latestVersion := baseReview.Version
+	for _, review := range reviews {
+		latestVersion = max(latestVersion, review.Version)
+	}
+
+	review := entity.NewReview().
+		Override(baseReview, reviewerUserID, justification).
+		SetVersion(latestVersion + 1)
+
+	if reviewOverride.Score != nil {
+		review.SetScore(*reviewOverride.Score)
+	}
+	if reviewOverride.Passed != nil {
+		review.SetPassed(*reviewOverride.Passed)
+	}
+	if reviewOverride.Feedback != nil {
+		review.SetFeedback(*reviewOverride.Feedback)
+	}
+	review.MarkReviewed()
+
+	// The override is only displayed together with its rubric, its feedback items and its audit log
+	return r.transactionRepo.Transaction(ctx, func(ctx context.Context) error {
+		reviewID, err := r.reviewRepo.Create(ctx, review)
+		if err != nil {
+			return err
+		}
+
+		if err := r.copyReviewDetails(ctx, baseReview.ID, reviewID); err != nil {
+			return err
+		}
+
+		interview.SetReviewID(reviewID)
+		if err := r.interviewRepo.Update(ctx, interview); err != nil {
+			return err
+		}
+
+		auditLog := entity.NewAuditLog().
+			SetActorUserID(reviewerUserID).
+			SetInterviewID(interview.ID).
+			SetAction(entity.OVERRIDE_REVIEW_ACTION).
+			SetEntity(entity.REVIEW_AUDIT_ENTITY, reviewID).
+			SetBefore(reviewSnapshot(baseReview)).
+			SetAfter(reviewSnapshot(review)).
+			SetReason(justification)
+
+		return r.auditLogRepo.Create(ctx, auditLog)
+	})
+}
+
+// AnnotateTranscript implements ReviewerService.
+func (r *ReviewerServiceImpl) AnnotateTranscript(ctx context.Context, reviewerUserID uint, interviewID, transcriptID, content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", fmt.Errorf("annotation cannot be empty: %w", common.ErrBadRequest)
+	}
+
+	interview, err := r.interviewRepo.GetByUUID(ctx, interviewID)
+	if err != nil {
+		return "", err
+	}
+
+	transcript, err := r.transcriptRepo.GetByUUID(ctx, transcriptID)
+	if err != nil {
+		return "", err
+	}
+
+	if transcript.InterviewID != interview.ID {
+		return "", fmt.Errorf("transcript %s does not belong to interview %s: %w", transcriptID, interviewID, common.ErrBadRequest)
+	}
+
+	annotation := entity.NewTranscriptAnnotation().
+		SetInterviewID(interview.ID).
+		SetTranscriptID(transcript.ID).
+		SetReviewerUserID(reviewerUserID).
+		SetContent(content)
+
+	err = r.transactionRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.transcriptAnnotationRepo.Create(ctx, annotation); err != nil {
+			return err
+		}
+
+		auditLog := entity.NewAuditLog().
+			SetActorUserID(reviewerUserID).
+			SetInterviewID(interview.ID).
+			SetAction(entity.CREATE_ANNOTATION_ACTION).
+			SetEntity(entity.TRANSCRIPT_ANNOTATION_AUDIT_ENTITY, annotation.ID).
+			SetAfter(annotationSnapshot(annotation, transcriptID))
+
+		return r.auditLogRepo.Create(ctx, auditLog)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return annotation.UUID, nil
+}
+
+// ListAnnotations implements ReviewerService.
+func (r *ReviewerServiceImpl) ListAnnotations(ctx context.Context, interviewID string) (*model.TranscriptAnnotations, error) {
+	interview, err := r.interviewRepo.GetByUUID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := r.transcriptAnnotationRepo.ListByInterviewID(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriptUUIDs, err := r.getTranscriptUUIDs(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotationModels := make([]*model.TranscriptAnnotation, 0)
+	for _, annotation := range annotations {
+		annotationModel := model.NewTranscriptAnnotation().
+			SetID(annotation.UUID).
+			SetTranscriptID(transcriptUUIDs[annotation.TranscriptID]).
+			SetContent(annotation.Content).
+			SetCreateTimestampS(util.MillisToSeconds(annotation.CreateTimestampMS))
+
+		annotationModels = append(annotationModels, annotationModel)
+	}
+
+	return model.NewTranscriptAnnotations().SetAnnotations(annotationModels), nil
+}
+
+// DeleteAnnotation implements ReviewerService.
+func (r *ReviewerServiceImpl) DeleteAnnotation(ctx context.Context, reviewerUserID uint, annotationID string) error {
+	annotation, err := r.transcriptAnnotationRepo.GetByUUID(ctx, annotationID)
+	if err != nil {
+		return err
+	}
+
+	if annotation.ReviewerUserID != reviewerUserID {
+		reviewer, err := r.userRepo.GetByID(ctx, reviewerUserID)
+		if err != nil {
+			return err
+		}
+
+		if !reviewer.HasRole(entity.ADMIN_ROLE) {
+			return fmt.Errorf("annotation %s can only be deleted by its author or an admin: %w", annotationID, common.ErrForbidden)
+		}
+	}
+
+	transcriptUUIDs, err := r.getTranscriptUUIDs(ctx, annotation.InterviewID)
+	if err != nil {
+		return err
+	}
+
+	return r.transactionRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.transcriptAnnotationRepo.Delete(ctx, annotation); err != nil {
+			return err
+		}
+
+		auditLog := entity.NewAuditLog().
+			SetActorUserID(reviewerUserID).
+			SetInterviewID(annotation.InterviewID).
+			SetAction(entity.DELETE_ANNOTATION_ACTION).
+			SetEntity(entity.TRANSCRIPT_ANNOTATION_AUDIT_ENTITY, annotation.ID).
+			SetBefore(annotationSnapshot(annotation, transcriptUUIDs[annotation.TranscriptID]))
+
+		return r.auditLogRepo.Create(ctx, auditLog)
+	})
+}
+
+// ListAuditLogs implements ReviewerService.
+func (r *ReviewerServiceImpl) ListAuditLogs(ctx context.Context, interviewID string) (*model.AuditLogs, error) {
+	interview, err := r.interviewRepo.GetByUUID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogs, err := r.auditLogRepo.ListByInterviewID(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogModels := make([]*model.AuditLog, 0)
+	for _, auditLog := range auditLogs {
+		auditLogModel := model.NewAuditLog().
+			SetAction(string(auditLog.Action)).
+			SetActorUserID(auditLog.ActorUserID).
+			SetChange(auditLog.Before, auditLog.After).
+			SetReason(auditLog.Reason).
+			SetCreateTimestampS(util.MillisToSeconds(auditLog.CreateTimestampMS))
+
+		auditLogModels = append(auditLogModels, auditLogModel)
+	}
+
+	return model.NewAuditLogs().SetLogs(auditLogModels), nil
+}
+
+// The rubric and feedback items are carried over so that the override is displayed the same way as an AI review
+func (r *ReviewerServiceImpl) copyReviewDetails(ctx context.Context, fromReviewID, toReviewID uint) error {
+	rubricScores, err := r.rubricScoreRepo.ListByReviewID(ctx, fromReviewID)
+	if err != nil {
+		return err
+	}
+
+	for _, rubricScore := range rubricScores {
+		rubricScore.Base = entity.Base{}
+		rubricScore.SetReviewID(toReviewID)
+	}
+
+	if err := r.rubricScoreRepo.CreateBatch(ctx, rubricScores); err != nil {
+		return err
+	}
+
+	feedbackItems, err := r.feedbackItemRepo.ListByReviewID(ctx, fromReviewID)
+	if err != nil {
+		return err
+	}
+
+	for _, feedbackItem := range feedbackItems {
+		feedbackItem.Base = entity.Base{}
+		feedbackItem.SetReviewID(toReviewID)
+	}
+
+	return r.feedbackItemRepo.CreateBatch(ctx, feedbackItems)
+}
+
+// Maps the internal transcript ID to the UUID that is exposed to reviewers
+func (r *ReviewerServiceImpl) getTranscriptUUIDs(ctx context.Context, interviewID uint) (map[uint]string, error) {
+	transcripts, err := r.transcriptRepo.ListByInterviewIDAsc(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriptUUIDs := make(map[uint]string)
+	for _, transcript := range transcripts {
+		transcriptUUIDs[transcript.ID] = transcript.UUID
+	}
+
+	return transcriptUUIDs, nil
+}
+
+func reviewSnapshot(review *entity.Review) map[string]any {
+	return map[string]any{
+		"version":  review.Version,
+		"source":   review.Source,
+		"score":    review.Score,
+		"passed":   review.Passed,
+		"feedback": review.Feedback,
+	}
+}
+
+func annotationSnapshot(annotation *entity.TranscriptAnnotation, transcriptID string) map[string]any {
+	return map[string]any{
+		"transcript_id": transcriptID,
+		"content":       annotation.Content,
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+type speechRewrite struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+var (
+	superscriptReplacer = strings.NewReplacer("²", "^2", "³", "^3", "ⁿ", "^n", "ᵏ", "^k")
+	symbolReplacer      = strings.NewReplacer(
+		"→", " to ", "⇒", " to ", "≤", " less than or equal to ", "≥", " greater than or equal to ", "≠", " not equal to ",
+		"≈", " about ", "∞", " infinity ", "×", " times ", "·", " times ", "÷", " divided by ", "√", " square root of ",
+		"&", " and ", "@", " at ",
+	)
+	codeSpanPattern      = regexp.MustCompile("`([^`]*)`")
+	bigONotationPattern  = regexp.MustCompile(`(\bO|Θ|Ω|\bTheta|\bOmega)\s*\(((?:[^()]|\([^()]*\))*)\)`)
+	indexPattern         = regexp.MustCompile(`\b(\w+)((?:\[[^\[\]]+\])+)`)
+	indexArgumentPattern = regexp.MustCompile(`\[([^\[\]]+)\]`)
+	callPattern          = regexp.MustCompile(`\b([A-Za-z_]\w*)\(([^()]*)\)`)
+	// Such as "approach(es)", which is a word in prose and not a call
+	wordSuffixPattern  = regexp.MustCompile(`^(s|es|ies|ed|d|ing|er|ers)$`)
+	unspeakablePattern = regexp.MustCompile("[`*_#|\\\\{}\\[\\]<>~^=]")
+	lineBreakPattern   = regexp.MustCompile(`([^.!?,:;\s])[ \t]*\n+`)
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+	punctuationPattern = regexp.MustCompile(`\s+([.,!?;:])`)
+)
+
+var markdownRewrites = []*speechRewrite{
+	{regexp.MustCompile("(?s)```.*?(```|$)"), "\n"},
+	{regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`), "$1"},
+	{regexp.MustCompile(`https?://\S+`), "the link"},
+	{regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+`), ""},
+	{regexp.MustCompile(`(?m)^\s*>\s?`), ""},
+	{regexp.MustCompile(`(?m)^\s*([-*_]\s*){3,}$`), ""},
+	{regexp.MustCompile(`(?m)^\s*[-*+•]\s+`), ""},
+	{regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`), "$1$2"},
+	{regexp.MustCompile(`(^|[\s(])[*_](\S[^*_\n]*?)[*_]([\s).,!?:;]|$)`), "$1$2$3"},
+}
+
+// Only applied within the Big-O notation, where the symbols always mean math
+var complexityRewrites = []*speechRewrite{
+	{regexp.MustCompile(`\bnlogn\b`), "n log n"},
+	{regexp.MustCompile(`\blog\s*\(\s*(\w+)\s*\)`), "log ${1}"},
+	{regexp.MustCompile(`\bsqrt\s*\(\s*(\w+)\s*\)`), "square root of ${1}"},
+	{regexp.MustCompile(`(\w+)!`), "${1} factorial"},
+	{regexp.MustCompile(`\s*[*·×]\s*`), " times "},
+	{regexp.MustCompile(`\s*\+\s*`), " plus "},
+	{regexp.MustCompile(`\s*-\s*`), " minus "},
+	{regexp.MustCompile(`\s*/\s*`), " divided by "},
+}
+
+var exponentRewrites = []*speechRewrite{
+	{regexp.MustCompile(`(\w+)\s*\^\s*2\b`), "${1} squared"},
+	{regexp.MustCompile(`(\w+)\s*\^\s*3\b`), "${1} cubed"},
+	{regexp.MustCompile(`(\w+)\s*\^\s*(\w+)`), "${1} to the power of ${2}"},
+}
+
+// Applied everywhere because these never mean anything else in prose.
+// The order matters, the longer operators have to be verbalized before the ones they contain
+var identifierRewrites = []*speechRewrite{
+	{regexp.MustCompile(`\bC\+\+`), "C plus plus"},
+	{regexp.MustCompile(`\bC#`), "C sharp"},
+	{regexp.MustCompile(`\bsqrt\b`), "square root"},
+	{regexp.MustCompile(`\b([A-Za-z_]\w*)\+\+`), "increment ${1}"},
+	{regexp.MustCompile(`\b([A-Za-z_]\w*)--`), "decrement ${1}"},
+	{regexp.MustCompile(`\s*\+=\s*`), " plus equals "},
+	{regexp.MustCompile(`\s*-=\s*`), " minus equals "},
+	{regexp.MustCompile(`\s*\*=\s*`), " times equals "},
+	{regexp.MustCompile(`\s*/=\s*`), " divided by equals "},
+	{regexp.MustCompile(`\s*===?\s*`), " equals "},
+	{regexp.MustCompile(`\s*!==?\s*`), " not equal to "},
+	{regexp.MustCompile(`\s*<=\s*`), " less than or equal to "},
+	{regexp.MustCompile(`\s*>=\s*`), " greater than or equal to "},
+	{regexp.MustCompile(`\s*(->|=>)\s*`), " to "},
+	{regexp.MustCompile(`\s*&&\s*`), " and "},
+	{regexp.MustCompile(`\s*\|\|\s*`), " or "},
+	{regexp.MustCompile(`\s*<<\s*`), " shifted left by "},
+	{regexp.MustCompile(`\s*>>\s*`), " shifted right by "},
+	{regexp.MustCompile(`(\w)\s*<\s*(\w)`), "${1} less than ${2}"},
+	{regexp.MustCompile(`(\w)\s*>\s*(\w)`), "${1} greater than ${2}"},
+	{regexp.MustCompile(`(\w)\s*=\s*(\S)`), "${1} equals ${2}"},
+	{regexp.MustCompile(`(\d)%`), "${1} percent"},
+	{regexp.MustCompile(`\b([A-Za-z_]\w+)\.([A-Za-z_]\w*)\b`), "${1} dot ${2}"},
+	{regexp.MustCompile(`\b(\w+)\[\]`), "${1} array"},
+	{regexp.MustCompile(`([a-z0-9])([A-Z])`), "${1} ${2}"},
+}
+
+// Only applied within inline code, in prose these are punctuation such as "24/7", "well-known" or "5+ years"
+var codeSpanRewrites = []*speechRewrite{
+	{regexp.MustCompile(`(^|[\s(])!(\w)`), "${1}not ${2}"},
+	{regexp.MustCompile(`(^|[\s(=,])-(\d)`), "${1}negative ${2}"},
+	{regexp.MustCompile(`(\w)\s+-\s+(\w)`), "${1} minus ${2}"},
+	{regexp.MustCompile(`\b([A-Za-z_]\w*)\s*-\s*(\d+)\b`), "${1} minus ${2}"},
+	{regexp.MustCompile(`([\w)])\s*\+\s*([\w(])`), "${1} plus ${2}"},
+	{regexp.MustCompile(`([\w)])\s*\*\s*([\w(])`), "${1} times ${2}"},
+	{regexp.MustCompile(`([\w)])\s*/\s*([\w(])`), "${1} divided by ${2}"},
+	{regexp.MustCompile(`([\w)])\s*%\s*([\w(])`), "${1} mod ${2}"},
+}
+
+type SpeechNormalizer interface {
+	// Rewrites a reply into text that the TTS reads out naturally, the original reply is what goes into the transcript
+	Normalize(text string) string
+}
+
+func NewSpeechNormalizer() SpeechNormalizer {
+	return &SpeechNormalizerImpl{}
+}
+
+type SpeechNormalizerImpl struct{}
+
+// Normalize implements SpeechNormalizer.
+func (s *SpeechNormalizerImpl) Normalize(text string) string {
+	text = s.rewrite(text, markdownRewrites)
+	text = superscriptReplacer.Replace(text)
+
+	text = bigONotationPattern.ReplaceAllStringFunc(text, func(notation string) string {
+		match := bigONotationPattern.FindStringSubmatch(notation)
+		return s.getNotationName(match[1]) + " of " + s.rewrite(s.rewrite(match[2], complexityRewrites), exponentRewrites)
+	})
+
+	// Inline code is verbalized as a whole, the prose around it only gets the rewrites of the identifiers in it
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(codeSpan string) string {
+		return s.verbalizeCode(codeSpanPattern.FindStringSubmatch(codeSpan)[1], true)
+	})
+	text = s.verbalizeCode(text, false)
+
+	text = symbolReplacer.Replace(text)
+	text = unspeakablePattern.ReplaceAllString(text, " ")
+
+	// A line break is a pause, so the lines of a list do not run into each other
+	text = lineBreakPattern.ReplaceAllString(text, "$1. ")
+	text = whitespacePattern.ReplaceAllString(text, " ")
+	text = punctuationPattern.ReplaceAllString(text, "$1")
+
+	return strings.TrimSpace(text)
+}
+
+func (s *SpeechNormalizerImpl) verbalizeCode(code string, isCodeSpan bool) string {
+	// Nested calls and indexes are verbalized from the inside out
+	for range 3 {
+		code = indexPattern.ReplaceAllStringFunc(code, func(index string) string {
+			match := indexPattern.FindStringSubmatch(index)
+			arguments := make([]string, 0)
+			for _, argument := range indexArgumentPattern.FindAllStringSubmatch(match[2], -1) {
+				arguments = append(arguments, strings.TrimSpace(argument[1]))
+			}
+			return match[1] + " at " + strings.Join(arguments, ", ")
+		})
+		code = callPattern.ReplaceAllStringFunc(code, func(call string) string {
+			match := callPattern.FindStringSubmatch(call)
+			if !isCodeSpan && wordSuffixPattern.MatchString(match[2]) {
+				return call
+			}
+			if strings.TrimSpace(match[2]) == "" {
+				return match[1]
+			}
+			return match[1] + " of " + match[2]
+		})
+	}
+
+	code = s.rewrite(code, exponentRewrites)
+	code = s.rewrite(code, identifierRewrites)
+	if isCodeSpan {
+		code = s.rewrite(code, codeSpanRewrites)
+	}
+	return code
+}
+
+func (s *SpeechNormalizerImpl) rewrite(text string, rewrites []*speechRewrite) string {
+	for _, rewrite := range rewrites {
+		text = rewrite.pattern.ReplaceAllString(text, rewrite.replacement)
+	}
+	return text
+}
+
+func (s *SpeechNormalizerImpl) getNotationName(notation string) string {
+	switch notation {
+	case "Θ", "Theta":
+		return "Theta"
+	case "Ω", "Omega":
+		return "Omega"
+	default:
+		return "O"
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"testing"
+)
+
+func TestSpeechNormalizerNormalize(t *testing.T) {
+	speechNormalizer := NewSpeechNormalizer()
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "bold and link",
+			text:     "**Great** question, have a look at the [constraints](https://example.com/constraints).",
+			expected: "Great question, have a look at the constraints.",
+		},
+		{
+			name:     "heading and list",
+			text:     "## Plan\n- sort the array\n- scan it once",
+			expected: "Plan. sort the array. scan it once",
+		},
+		{
+			name:     "code block",
+			text:     "Something like this:\n```python\nreturn sorted(nums)\n```\nWhat do you think?",
+			expected: "Something like this: What do you think?",
+		},
+		{
+			name:     "Big-O with an exponent",
+			text:     "That runs in O(n^2) time.",
+			expected: "That runs in O of n squared time.",
+		},
+		{
+			name:     "Big-O with a logarithm",
+			text:     "Sorting costs O(n log n), can you get to O(n)?",
+			expected: "Sorting costs O of n log n, can you get to O of n?",
+		},
+		{
+			name:     "Big-O with a product",
+			text:     "A grid search is O(m*n).",
+			expected: "A grid search is O of m times n.",
+		},
+		{
+			name:     "indexing in a code span",
+			text:     "What is `nums[i]` when `i` is zero?",
+			expected: "What is nums at i when i is zero?",
+		},
+		{
+			name:     "nested indexing in a code span",
+			text:     "Look at `grid[r][c]` first.",
+			expected: "Look at grid at r, c first.",
+		},
+		{
+			name:     "call and arithmetic in a code span",
+			text:     "Why `len(nums) - 1`?",
+			expected: "Why len of nums minus 1?",
+		},
+		{
+			name:     "operators in a code span",
+			text:     "So `i += 1` until `left <= right` and `a != b`.",
+			expected: "So i plus equals 1 until left less than or equal to right and a not equal to b.",
+		},
+		{
+			name:     "division in a code span",
+			text:     "Try `mid = lo + (hi - lo) / 2` instead.",
+			expected: "Try mid equals lo plus (hi minus lo) divided by 2 instead.",
+		},
+		{
+			name:     "languages",
+			text:     "Are you using C++ or C#?",
+			expected: "Are you using C plus plus or C sharp?",
+		},
+		{
+			name:     "optional plural stays a word",
+			text:     "Which approach(es) did you consider?",
+			expected: "Which approach(es) did you consider?",
+		},
+		{
+			name:     "slash in prose",
+			text:     "The service runs 24/7, and/or it scales.",
+			expected: "The service runs 24/7, and/or it scales.",
+		},
+		{
+			name:     "hyphen and plus in prose",
+			text:     "It is a well-known trick after 5+ years of practice.",
+			expected: "It is a well-known trick after 5+ years of practice.",
+		},
+		{
+			name:     "exclamation in prose",
+			text:     "Nice! That works.",
+			expected: "Nice! That works.",
+		},
+		{
+			name:     "plain prose",
+			text:     "Can you walk me through your approach, and what happens when the input is empty?",
+			expected: "Can you walk me through your approach, and what happens when the input is empty?",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			normalized := speechNormalizer.Normalize(testCase.text)
+			if normalized != testCase.expected {
+				t.Errorf("normalized = %q, want %q", normalized, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type StudyPlanService interface {
+	// The study plan of the candidate of the interview is regenerated in the background
+	EnqueueStudyPlanUpdate(ctx context.Context, interviewID uint) error
+	UpdateStudyPlan(ctx context.Context, userID uint) error
+	GetStudyPlan(ctx context.Context, userID uint) (*model.StudyPlan, error)
+}
+
+func NewStudyPlanService(
+	llmConfig *config.LLMConfig,
+	aiUseCase AIUseCase,
+	studyPlanRepo repo.StudyPlanRepo,
+	interviewRepo repo.InterviewRepo,
+	reviewRepo repo.ReviewRepo,
+	rubricScoreRepo repo.RubricScoreRepo,
+	questionRepo repo.QuestionRepo,
+	messageQueueRepo repo.MessageQueueProducerRepo,
+	promptService PromptService,
+) StudyPlanService {
+	return &StudyPlanServiceImpl{
+		llmConfig:        llmConfig,
+		aiUseCase:        aiUseCase,
+		studyPlanRepo:    studyPlanRepo,
+		interviewRepo:    interviewRepo,
+		reviewRepo:       reviewRepo,
+		rubricScoreRepo:  rubricScoreRepo,
+		questionRepo:     questionRepo,
+		messageQueueRepo: messageQueueRepo,
+		promptService:    promptService,
+	}
+}
+
+type StudyPlanServiceImpl struct {
+	llmConfig        *config.LLMConfig
+	aiUseCase        AIUseCase
+	studyPlanRepo    repo.StudyPlanRepo
+	interviewRepo    repo.InterviewRepo
+	reviewRepo       repo.ReviewRepo
+	rubricScoreRepo  repo.RubricScoreRepo
+	questionRepo     repo.QuestionRepo
+	messageQueueRepo repo.MessageQueueProducerRepo
+	promptService    PromptService
+}
+
+type llmStudyPlan struct {
+	Summary              string                  `json:"summary"`
+	WeakTopics           []*llmStudyPlanTopic    `json:"weak_topics"`
+	RecommendedQuestions []*llmStudyPlanQuestion `json:"recommended_questions"`
+	Exercises            []string                `json:"exercises"`
+}
+
+// Validate implements StructuredReply, the same topic or question should not be repeated
+func (l *llmStudyPlan) Validate() []string {
+	violations := make([]string, 0)
+
+	seenDimensions := make(map[string]struct{})
+	for index, weakTopic := range l.WeakTopics {
+		if weakTopic == nil {
+			continue
+		}
+		if _, ok := seenDimensions[weakTopic.Dimension]; ok {
+			violations = append(violations, fmt.Sprintf("$.weak_topics[%d].dimension '%s' is repeated", index, weakTopic.Dimension))
+		}
+		seenDimensions[weakTopic.Dimension] = struct{}{}
+	}
+
+	seenQuestionIDs := make(map[string]struct{})
+	for index, recommendedQuestion := range l.RecommendedQuestions {
+		if recommendedQuestion == nil {
+			continue
+		}
+		if _, ok := seenQuestionIDs[recommendedQuestion.QuestionID]; ok {
+			violations = append(violations, fmt.Sprintf("$.recommended_questions[%d].question_id '%s' is repeated", index, recommendedQuestion.QuestionID))
+		}
+		seenQuestionIDs[recommendedQuestion.QuestionID] = struct{}{}
+	}
+
+	return violations
+}
+
+type llmStudyPlanTopic struct {
+	Dimension string `json:"dimension"`
+	Reason    string `json:"reason"`
+}
+
+type llmStudyPlanQuestion struct {
+	QuestionID string `json:"question_id"`
+	Reason     string `json:"reason"`
+}
+
+// reviewedInterview is a reviewed interview of the user together with what is needed to plan the next steps
+type reviewedInterview struct {
+	interview    *entity.Interview
+	question     *entity.Question
+	review       *entity.Review
+	rubricScores []*entity.RubricScore
+}
+
+// bankQuestion is a question from the question bank together with the weak topic that it practices
+type bankQuestion struct {
+	question  *entity.Question
+	dimension entity.RubricDimension
+}
+
+// EnqueueStudyPlanUpdate implements StudyPlanService.
+func (s *StudyPlanServiceImpl) EnqueueStudyPlanUpdate(ctx context.Context, interviewID uint) error {
+	interview, err := s.interviewRepo.GetByID(ctx, interviewID)
+	if err != nil {
+		return err
+	}
+
+	studyPlanMessage := &model.StudyPlanMessage{
+		UserID: interview.UserID,
+	}
+
+	data, err := json.Marshal(studyPlanMessage)
+	if err != nil {
+		return fmt.Errorf("unable to marshal study plan message for user id %d, %s: %w", interview.UserID, err, common.ErrInternalServerError)
+	}
+
+	if err := s.messageQueueRepo.Push(ctx, data, common.STUDY_PLAN_QUEUE); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStudyPlan implements StudyPlanService.
+func (s *StudyPlanServiceImpl) UpdateStudyPlan(ctx context.Context, userID uint) error {
+	reviewedInterviews, err := s.listReviewedInterviews(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if len(reviewedInterviews) == 0 {
+		return nil
+	}
+
+	averageScores := s.averageRubricScores(reviewedInterviews)
+
+	// Questions are supplied by the candidates, so they are only ever offered by their IDs and never by their descriptions
+	attemptedQuestions := s.listAttemptedQuestions(reviewedInterviews)
+
+	bankQuestions, err := s.listBankQuestions(ctx, s.listWeakestDimensions(averageScores), attemptedQuestions)
+	if err != nil {
+		return err
+	}
+
+	questionByExternalID := make(map[string]*entity.Question)
+	for _, question := range attemptedQuestions {
+		questionByExternalID[question.ExternalID] = question
+	}
+	for _, bankQuestion := range bankQuestions {
+		questionByExternalID[bankQuestion.question.ExternalID] = bankQuestion.question
+	}
+
+	dimensions := make([]string, 0)
+	for _, dimension := range entity.RUBRIC_DIMENSIONS {
+		dimensions = append(dimensions, string(dimension))
+	}
+
+	variables := model.NewPromptVariables().
+		SetRubric(dimensions, entity.RUBRIC_MAX_SCORE).
+		SetStudyPlanLimits(config.STUDY_PLAN_MAX_WEAK_TOPICS, config.STUDY_PLAN_MAX_RECOMMENDATIONS)
+
+	prompt, err := s.promptService.Render(ctx, model.STUDY_PLAN_PROMPT, model.NewPromptScope(), variables)
+	if err != nil {
+		return err
+	}
+
+	llmMessages := []*model.LLMMessage{
+		model.NewLLMMessage().
+			SetRole(model.SYSTEM).
+			SetContent(prompt.Content),
+		model.NewLLMMessage().
+			SetRole(model.USER).
+			SetContent(s.buildStudyPlanRecord(reviewedInterviews, averageScores, bankQuestions)),
+	}
+
+	reply := &llmStudyPlan{}
+	if err := s.aiUseCase.GenerateStructuredReply(ctx, llmMessages, s.llmConfig.Model, "study_plan", s.studyPlanResponseSchema(dimensions, questionByExternalID), reply); err != nil {
+		return err
+	}
+
+	weakTopics := make([]*entity.StudyPlanTopic, 0)
+	for _, llmWeakTopic := range reply.WeakTopics {
+		if llmWeakTopic == nil {
+			continue
+		}
+
+		dimension := entity.RubricDimension(llmWeakTopic.Dimension)
+		weakTopics = append(weakTopics, &entity.StudyPlanTopic{
+			Dimension:    dimension,
+			AverageScore: averageScores[dimension],
+			Reason:       llmWeakTopic.Reason,
+		})
+	}
+
+	recommendedQuestions := make([]*entity.StudyPlanQuestion, 0)
+	for _, llmRecommendedQuestion := range reply.RecommendedQuestions {
+		if llmRecommendedQuestion == nil {
+			continue
+		}
+
+		question, ok := questionByExternalID[llmRecommendedQuestion.QuestionID]
+		if !ok {
+			continue
+		}
+
+		recommendedQuestions = append(recommendedQuestions, &entity.StudyPlanQuestion{
+			QuestionID: question.ID,
+			ExternalID: question.ExternalID,
+			Reason:     llmRecommendedQuestion.Reason,
+		})
+	}
+
+	studyPlan, err := s.studyPlanRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return err
+	}
+
+	if !studyPlan.Exists() {
+		studyPlan = entity.NewStudyPlan().
+			SetUserID(userID)
+	}
+
+	studyPlan.
+		SetSummary(reply.Summary).
+		SetWeakTopics(weakTopics).
+		SetRecommendedQuestions(recommendedQuestions).
+		SetExercises(reply.Exercises).
+		SetModel(s.llmConfig.Model).
+		SetPromptVersion(prompt.Version).
+		SetReviewCount(uint(len(reviewedInterviews)))
+
+	if studyPlan.ID == 0 {
+		return s.studyPlanRepo.Create(ctx, studyPlan)
+	}
+
+	return s.studyPlanRepo.Update(ctx, studyPlan)
+}
+
+// GetStudyPlan implements StudyPlanService.
+func (s *StudyPlanServiceImpl) GetStudyPlan(ctx context.Context, userID uint) (*model.StudyPlan, error) {
+	studyPlan, err := s.studyPlanRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	studyPlanModel := model.NewStudyPlan().
+		SetSummary(studyPlan.Summary).
+		SetExercises(studyPlan.Exercises).
+		SetReviewCount(studyPlan.ReviewCount).
+		SetUpdateTimestampS(util.MillisToSeconds(studyPlan.UpdateTimestampMS))
+
+	for _, weakTopic := range studyPlan.WeakTopics {
+		if weakTopic == nil {
+			continue
+		}
+
+		studyPlanModel.AppendWeakTopic(model.NewStudyPlanTopic().
+			SetDimension(string(weakTopic.Dimension)).
+			SetAverageScore(weakTopic.AverageScore).
+			SetMaxScore(entity.RUBRIC_MAX_SCORE).
+			SetReason(weakTopic.Reason))
+	}
+
+	for _, recommendedQuestion := range studyPlan.RecommendedQuestions {
+		if recommendedQuestion == nil {
+			continue
+		}
+
+		studyPlanModel.AppendRecommendedQuestion(model.NewStudyPlanQuestion().
+			SetQuestionID(recommendedQuestion.ExternalID).
+			SetReason(recommendedQuestion.Reason))
+	}
+
+	return studyPlanModel, nil
+}
+
+// Only the displayed review of each interview is used, so that overrides from reviewers are taken into account
+func (s *StudyPlanServiceImpl) listReviewedInterviews(ctx context.Context, userID uint) ([]*reviewedInterview, error) {
+	interviews, _, err := s.interviewRepo.ListStartedInterviewsByUserID(ctx, userID, config.STUDY_PLAN_HISTORY_LIMIT, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewedInterviews := make([]*reviewedInterview, 0)
+	for _, interview := range interviews {
+		if !interview.HasEnded() || !interview.ReviewExists() {
+			continue
+		}
+
+		review, err := s.reviewRepo.GetByID(ctx, interview.GetReviewID())
+		if err != nil {
+			return nil, err
+		}
+
+		if !review.IsReviewed() {
+			continue
+		}
+
+		question, err := s.questionRepo.GetByID(ctx, interview.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+
+		rubricScores, err := s.rubricScoreRepo.ListByReviewID(ctx, review.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		reviewedInterviews = append(reviewedInterviews, &reviewedInterview{
+			interview:    interview,
+			question:     question,
+			review:       review,
+			rubricScores: rubricScores,
+		})
+	}
+
+	return reviewedInterviews, nil
+}
+
+// Deduplicated, in the order of the interviews
+func (s *StudyPlanServiceImpl) listAttemptedQuestions(reviewedInterviews []*reviewedInterview) []*entity.Question {
+	seenQuestionIDs := make(map[uint]struct{})
+	attemptedQuestions := make([]*entity.Question, 0)
+	for _, reviewedInterview := range reviewedInterviews {
+		if !reviewedInterview.question.Exists() {
+			continue
+		}
+		if _, ok := seenQuestionIDs[reviewedInterview.question.ID]; ok {
+			continue
+		}
+		seenQuestionIDs[reviewedInterview.question.ID] = struct{}{}
+		attemptedQuestions = append(attemptedQuestions, reviewedInterview.question)
+	}
+	return attemptedQuestions
+}
+
+// Dimensions without any score are left out
+func (s *StudyPlanServiceImpl) averageRubricScores(reviewedInterviews []*reviewedInterview) map[entity.RubricDimension]float64 {
+	totals := make(map[entity.RubricDimension]uint)
+	counts := make(map[entity.RubricDimension]uint)
+
+	for _, reviewedInterview := range reviewedInterviews {
+		for _, rubricScore := range reviewedInterview.rubricScores {
+			totals[rubricScore.Dimension] += rubricScore.Score
+			counts[rubricScore.Dimension]++
+		}
+	}
+
+	averageScores := make(map[entity.RubricDimension]float64)
+	for dimension, count := range counts {
+		averageScores[dimension] = math.Round(float64(totals[dimension])/float64(count)*10) / 10
+	}
+
+	return averageScores
+}
+
+// Weakest first, at most config.STUDY_PLAN_MAX_WEAK_TOPICS of them
+func (s *StudyPlanServiceImpl) listWeakestDimensions(averageScores map[entity.RubricDimension]float64) []entity.RubricDimension {
+	dimensions := make([]entity.RubricDimension, 0)
+	for _, dimension := range entity.RUBRIC_DIMENSIONS {
+		if _, ok := averageScores[dimension]; ok {
+			dimensions = append(dimensions, dimension)
+		}
+	}
+
+	slices.SortStableFunc(dimensions, func(a, b entity.RubricDimension) int {
+		return cmp.Compare(averageScores[a], averageScores[b])
+	})
+
+	return dimensions[:min(len(dimensions), int(config.STUDY_PLAN_MAX_WEAK_TOPICS))]
+}
+
+// The questions that practice a weak topic are the ones that candidates score the lowest on in that dimension,
+// the questions that the user has attempted are left out and no question is offered for two topics
+func (s *StudyPlanServiceImpl) listBankQuestions(ctx context.Context, weakestDimensions []entity.RubricDimension, attemptedQuestions []*entity.Question) ([]*bankQuestion, error) {
+	excludedIDs := make([]uint, 0)
+	for _, question := range attemptedQuestions {
+		excludedIDs = append(excludedIDs, question.ID)
+	}
+
+	bankQuestions := make([]*bankQuestion, 0)
+	for _, dimension := range weakestDimensions {
+		questions, err := s.questionRepo.ListHardestByRubricDimension(ctx, dimension, excludedIDs, config.STUDY_PLAN_QUESTIONS_PER_WEAK_TOPIC)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, question := range questions {
+			excludedIDs = append(excludedIDs, question.ID)
+			bankQuestions = append(bankQuestions, &bankQuestion{
+				question:  question,
+				dimension: dimension,
+			})
+		}
+	}
+
+	return bankQuestions, nil
+}
+
+func (s *StudyPlanServiceImpl) buildStudyPlanRecord(reviewedInterviews []*reviewedInterview, averageScores map[entity.RubricDimension]float64, bankQuestions []*bankQuestion) string {
+	var record strings.Builder
+
+	record.WriteString("Average rubric scores:\n")
+	for _, dimension := range entity.RUBRIC_DIMENSIONS {
+		if averageScore, ok := averageScores[dimension]; ok {
+			fmt.Fprintf(&record, "- %s: %.1f/%d\n", dimension, averageScore, entity.RUBRIC_MAX_SCORE)
+		}
+	}
+
+	record.WriteString("\nLatest interviews, most recent first:\n")
+	for _, reviewedInterview := range reviewedInterviews {
+		fmt.Fprintf(&record, "\n[question_id=%s score=%d passed=%t]\n", reviewedInterview.question.ExternalID, reviewedInterview.review.Score, reviewedInterview.review.Passed)
+		fmt.Fprintf(&record, "Feedback: %s\n", reviewedInterview.review.Feedback)
+		for _, rubricScore := range reviewedInterview.rubricScores {
+			fmt.Fprintf(&record, "- %s %d/%d: %s\n", rubricScore.Dimension, rubricScore.Score, entity.RUBRIC_MAX_SCORE, rubricScore.Justification)
+		}
+	}
+
+	if len(bankQuestions) > 0 {
+		record.WriteString("\nQuestions from the question bank that other candidates score the lowest on in a dimension:\n")
+		for _, bankQuestion := range bankQuestions {
+			fmt.Fprintf(&record, "- [question_id=%s dimension=%s]\n", bankQuestion.question.ExternalID, bankQuestion.dimension)
+		}
+	}
+
+	return record.String()
+}
+
+func (s *StudyPlanServiceImpl) studyPlanResponseSchema(dimensions []string, questionByExternalID map[string]*entity.Question) *model.JSONSchema {
+	questionIDs := make([]string, 0)
+	for externalID := range questionByExternalID {
+		questionIDs = append(questionIDs, externalID)
+	}
+	slices.Sort(questionIDs)
+
+	weakTopicSchema := model.NewJSONSchema(model.JSON_SCHEMA_OBJECT).
+		AddProperty("dimension", model.NewJSONSchema(model.JSON_SCHEMA_STRING).SetEnum(dimensions)).
+		AddProperty("reason", model.NewJSONSchema(model.JSON_SCHEMA_STRING)).
+		DisallowAdditionalProperties()
+
+	recommendedQuestionSchema := model.NewJSONSchema(model.JSON_SCHEMA_OBJECT).
+		AddProperty("question_id", model.NewJSONSchema(model.JSON_SCHEMA_STRING).SetEnum(questionIDs)).
+		AddProperty("reason", model.NewJSONSchema(model.JSON_SCHEMA_STRING)).
+		DisallowAdditionalProperties()
+
+	// An empty enum does not restrict anything, so no recommendation is allowed without a question
+	maxRecommendations := min(config.STUDY_PLAN_MAX_RECOMMENDATIONS, uint(len(questionIDs)))
+
+	return model.NewJSONSchema(model.JSON_SCHEMA_OBJECT).
+		AddProperty("summary", model.NewJSONSchema(model.JSON_SCHEMA_STRING)).
+		AddProperty("weak_topics", model.NewJSONSchema(model.JSON_SCHEMA_ARRAY).
+			SetItems(weakTopicSchema).
+			SetItemsRange(1, config.STUDY_PLAN_MAX_WEAK_TOPICS)).
+		AddProperty("recommended_questions", model.NewJSONSchema(model.JSON_SCHEMA_ARRAY).
+			SetItems(recommendedQuestionSchema).
+			SetItemsRange(0, maxRecommendations)).
+		AddProperty("exercises", model.NewJSONSchema(model.JSON_SCHEMA_ARRAY).
+			SetItems(model.NewJSONSchema(model.JSON_SCHEMA_STRING)).
+			SetItemsRange(1, config.STUDY_PLAN_MAX_EXERCISES)).
+		DisallowAdditionalProperties()
+}
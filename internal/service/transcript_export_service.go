@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type TranscriptExportService interface {
+	// The interview ID here is the UUID, only the candidate of the interview can export it
+	ExportTranscript(ctx context.Context, userID uint, interviewID string, format model.TranscriptExportFormat) (*model.TranscriptFile, error)
+}
+
+func NewTranscriptExportService(
+	interviewRepo repo.InterviewRepo,
+	questionRepo repo.QuestionRepo,
+	transcriptManager TranscriptManager,
+) TranscriptExportService {
+	return &TranscriptExportServiceImpl{
+		interviewRepo:     interviewRepo,
+		questionRepo:      questionRepo,
+		transcriptManager: transcriptManager,
+	}
+}
+
+type TranscriptExportServiceImpl struct {
+	interviewRepo     repo.InterviewRepo
+	questionRepo      repo.QuestionRepo
+	transcriptManager TranscriptManager
+}
+
+// ExportTranscript implements TranscriptExportService.
+func (t *TranscriptExportServiceImpl) ExportTranscript(ctx context.Context, userID uint, interviewID string, format model.TranscriptExportFormat) (*model.TranscriptFile, error) {
+	if !format.IsValid() {
+		return nil, fmt.Errorf("unsupported transcript export format '%s': %w", format, common.ErrBadRequest)
+	}
+
+	interview, err := t.interviewRepo.GetByUUID(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Interviews of other candidates are reported as missing so that their existence is not leaked
+	if interview.UserID != userID {
+		return nil, fmt.Errorf("interview not found: %w", common.ErrNotFound)
+	}
+
+	if !interview.HasStarted() {
+		return nil, fmt.Errorf("the interview has not started yet: %w", common.ErrBadRequest)
+	}
+
+	transcriptExport, err := t.buildTranscriptExport(ctx, interview)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriptFile := &model.TranscriptFile{
+		FileName: fmt.Sprintf("interview-%s", interview.UUID),
+	}
+
+	switch format {
+	case model.MARKDOWN_TRANSCRIPT_EXPORT:
+		transcriptFile.FileName += ".md"
+		transcriptFile.ContentType = "text/markdown; charset=utf-8"
+		transcriptFile.Content = []byte(t.renderMarkdown(transcriptExport))
+	case model.JSON_TRANSCRIPT_EXPORT:
+		content, err := json.MarshalIndent(transcriptExport, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal transcript export for interview %s, %s: %w", interviewID, err, common.ErrInternalServerError)
+		}
+		transcriptFile.FileName += ".json"
+		transcriptFile.ContentType = "application/json"
+		transcriptFile.Content = content
+	case model.VTT_TRANSCRIPT_EXPORT:
+		transcriptFile.FileName += ".vtt"
+		transcriptFile.ContentType = "text/vtt; charset=utf-8"
+		transcriptFile.Content = []byte(t.renderVTT(transcriptExport))
+	}
+
+	return transcriptFile, nil
+}
+
+// The system prompt is left out because it is an instruction to the LLM, not a part of the conversation
+func (t *TranscriptExportServiceImpl) buildTranscriptExport(ctx context.Context, interview *entity.Interview) (*model.TranscriptExport, error) {
+	question, err := t.questionRepo.GetByID(ctx, interview.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	transcripts, err := t.transcriptManager.GetTranscriptHistory(ctx, interview.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	startTimestampMS := util.FromPtr(interview.StartTimestampMS)
+
+	transcriptExport := model.NewTranscriptExport().
+		SetInterviewID(interview.UUID).
+		SetQuestionID(question.ExternalID).
+		SetStartTimestampS(interview.GetStartTimesampS()).
+		SetEndTimestampS(interview.GetEndTimestampS())
+
+	for _, transcript := range transcripts {
+		var speaker model.TranscriptSpeaker
+		switch transcript.Role {
+		case entity.ASSISTANT:
+			speaker = model.INTERVIEWER_SPEAKER
+		case entity.USER:
+			speaker = model.CANDIDATE_SPEAKER
+		default:
+			continue
+		}
+
+		audioURL, err := t.transcriptManager.GetAudioURL(ctx, transcript)
+		if err != nil {
+			return nil, err
+		}
+
+		turn := model.NewTranscriptTurn().
+			SetID(transcript.UUID).
+			SetSpeaker(speaker).
+			SetContent(transcript.Content).
+			SetOffsetMS(max(transcript.CreateTimestampMS-startTimestampMS, 0)).
+			SetAudioURL(audioURL)
+
+		transcriptExport.AppendTurn(turn)
+	}
+
+	finalCode, err := t.getFinalCode(ctx, interview)
+	if err != nil {
+		return nil, err
+	}
+	transcriptExport.SetFinalCode(finalCode)
+
+	return transcriptExport, nil
+}
+
+// The code on the interview is only saved in some flows, the latest snapshot is used when it is missing
+func (t *TranscriptExportServiceImpl) getFinalCode(ctx context.Context, interview *entity.Interview) (string, error) {
+	if interview.Code != "" {
+		return interview.Code, nil
+	}
+
+	codeSnapshots, err := t.transcriptManager.GetCodeSnapshots(ctx, interview.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(codeSnapshots) == 0 {
+		return "", nil
+	}
+
+	return codeSnapshots[len(codeSnapshots)-1].Code, nil
+}
+
+func (t *TranscriptExportServiceImpl) renderMarkdown(transcriptExport *model.TranscriptExport) string {
+	var markdown strings.Builder
+
+	fmt.Fprintf(&markdown, "# Interview transcript: %s\n\n", transcriptExport.QuestionID)
+	fmt.Fprintf(&markdown, "- Started: %s\n", util.ConvertUnixMilliToHumanReadableFormat(transcriptExport.StartTimestampS*1000))
+	if transcriptExport.EndTimestampS > 0 {
+		fmt.Fprintf(&markdown, "- Ended: %s\n", util.ConvertUnixMilliToHumanReadableFormat(transcriptExport.EndTimestampS*1000))
+	}
+
+	markdown.WriteString("\n## Conversation\n")
+	for _, turn := range transcriptExport.Turns {
+		fmt.Fprintf(&markdown, "\n**[%s] %s:** %s\n", t.formatClock(turn.OffsetMS), t.speakerLabel(turn.Speaker), strings.TrimSpace(turn.Content))
+		if turn.AudioURL != "" {
+			fmt.Fprintf(&markdown, "\n[Audio](%s)\n", turn.AudioURL)
+		}
+	}
+
+	if transcriptExport.FinalCode != "" {
+		// The fence has to be longer than any backtick run in the code so that the code cannot close it
+		fence := "```"
+		for strings.Contains(transcriptExport.FinalCode, fence) {
+			fence += "`"
+		}
+		fmt.Fprintf(&markdown, "\n## Final code\n\n%s\n%s\n%s\n", fence, strings.TrimRight(transcriptExport.FinalCode, "\n"), fence)
+	}
+
+	return markdown.String()
+}
+
+// Every cue lasts until the next turn starts, the audio links and final code are kept as NOTE blocks
+func (t *TranscriptExportServiceImpl) renderVTT(transcriptExport *model.TranscriptExport) string {
+	var vtt strings.Builder
+
+	vtt.WriteString("WEBVTT\n")
+
+	for index, turn := range transcriptExport.Turns {
+		endOffsetMS := turn.OffsetMS + config.TRANSCRIPT_EXPORT_LAST_CUE_DURATION.Milliseconds()
+		if index+1 < len(transcriptExport.Turns) {
+			endOffsetMS = transcriptExport.Turns[index+1].OffsetMS
+		}
+		endOffsetMS = max(endOffsetMS, turn.OffsetMS+config.TRANSCRIPT_EXPORT_MIN_CUE_DURATION.Milliseconds())
+
+		if turn.AudioURL != "" {
+			fmt.Fprintf(&vtt, "\nNOTE audio %s\n", turn.AudioURL)
+		}
+
+		fmt.Fprintf(&vtt, "\n%s\n%s --> %s\n<v %s>%s\n",
+			turn.ID,
+			t.formatVTTTimestamp(turn.OffsetMS),
+			t.formatVTTTimestamp(endOffsetMS),
+			t.speakerLabel(turn.Speaker),
+			t.escapeVTT(turn.Content),
+		)
+	}
+
+	if transcriptExport.FinalCode != "" {
+		// A NOTE ends at the first blank line, so blank lines in the code are dropped
+		lines := make([]string, 0)
+		for _, line := range strings.Split(t.escapeVTT(transcriptExport.FinalCode), "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		fmt.Fprintf(&vtt, "\nNOTE final code\n%s\n", strings.Join(lines, "\n"))
+	}
+
+	return vtt.String()
+}
+
+func (t *TranscriptExportServiceImpl) speakerLabel(speaker model.TranscriptSpeaker) string {
+	if speaker == model.INTERVIEWER_SPEAKER {
+		return "Interviewer"
+	}
+	return "Candidate"
+}
+
+// Cue text cannot contain "-->", a blank line, or unescaped "&" and "<"
+func (t *TranscriptExportServiceImpl) escapeVTT(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	text = strings.ReplaceAll(text, ">", "&gt;")
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// HH:MM:SS.mmm
+func (t *TranscriptExportServiceImpl) formatVTTTimestamp(offsetMS int64) string {
+	offset := time.Duration(offsetMS) * time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		int64(offset.Hours()),
+		int64(offset.Minutes())%60,
+		int64(offset.Seconds())%60,
+		offsetMS%1000,
+	)
+}
+
+// MM:SS
+func (t *TranscriptExportServiceImpl) formatClock(offsetMS int64) string {
+	offset := time.Duration(offsetMS) * time.Millisecond
+	return fmt.Sprintf("%02d:%02d", int64(offset.Minutes()), int64(offset.Seconds())%60)
+}
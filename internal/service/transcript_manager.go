@@ -2,24 +2,60 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
 	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+	"github.com/rs/zerolog/log"
 )
 
 type TranscriptManager interface {
 	FlushAndRemoveInterview(ctx context.Context, interviewID uint) error
 	FlushCandidate(ctx context.Context, interviewID uint) error
+	// The intent is the one that was classified from the sentence in the buffer
+	FlushCandidateWithIntent(ctx context.Context, interviewID uint, intent entity.Intent, confidence float64) error
+	// The sentence is kept for the review but never sent to the interviewer, it is for the sentences that tried to inject instructions
+	FlushCandidateExcludedFromContext(ctx context.Context, interviewID uint, intent entity.Intent, confidence float64) error
 	WriteCandidate(ctx context.Context, interviewID uint, chunk string) error
-	WriteInterviewer(ctx context.Context, interviewID uint, message, url string) error
+	// The prompt is the template that the message was generated from, it is nil for canned phrases
+	WriteInterviewer(ctx context.Context, interviewID uint, message string, audioLocation *model.FileLocation, prompt *model.RenderedPrompt) (*entity.Transcript, error)
+	// The position of an interviewer transcript among the replies of the interviewer in its interview, starting from 1
+	GetInterviewerSequence(ctx context.Context, transcript *entity.Transcript) (uint, error)
+	// The URL is the streaming route of the backend signed for common.PRESIGNED_URL_EXPIRY_DURATION, so that audio elements
+	// and exported files can load it without a session. It is empty when there is no audio
+	GetAudioURL(ctx context.Context, transcript *entity.Transcript) (string, error)
+	// Checks the expiry and signature of a URL from GetAudioURL
+	VerifyAudioURLSignature(transcript *entity.Transcript, expiresAtS int64, signature string) error
+	// The caller has to close the content
+	OpenAudio(ctx context.Context, transcript *entity.Transcript) (*model.FileContent, error)
+	// The transcript ID here is the UUID
+	GetTranscript(ctx context.Context, transcriptID string) (*entity.Transcript, error)
+	// Parses the location out of the legacy presigned URLs, returns the number of transcripts that are migrated
+	MigrateAudioLocations(ctx context.Context) (uint, error)
+	// A code snapshot is only persisted when the code differs from the previous snapshot
+	WriteCode(ctx context.Context, interviewID uint, code string) error
 	// This sets up the system prompt for the LLM
-	PrepareInterviewer(ctx context.Context, interviewID uint, prompt string) error
+	PrepareInterviewer(ctx context.Context, interviewID uint, prompt *model.RenderedPrompt) error
 	GetTranscriptHistory(ctx context.Context, interviewID uint) ([]*entity.Transcript, error)
-	GetTranscriptHistoryInLLMMessageFormat(ctx context.Context, interviewID uint) ([]*model.LLMMessage, error)
+	// Older turns are summarized when the history does not fit in the context budget of the model,
+	// the system prompt and the latest code are always kept
+	// An empty llmModel uses the context budget of the default model
+	GetTranscriptHistoryInLLMMessageFormat(ctx context.Context, interviewID uint, llmModel string) ([]*model.LLMMessage, error)
+	// Rebuilds the history that the interviewer had before the transcript was written, with the code of that time.
+	// Nothing is summarized or saved, the oldest turns are dropped instead when the history does not fit.
+	// The stored system prompt is replaced when another one is given
+	GetTranscriptHistoryBefore(ctx context.Context, interviewID, transcriptID uint, systemPrompt *model.RenderedPrompt, llmModel string) ([]*model.LLMMessage, error)
+	GetCodeSnapshots(ctx context.Context, interviewID uint) ([]*entity.CodeSnapshot, error)
 	HasSufficientWordsInBuffer(ctx context.Context, interviewID uint) (bool, error)
 	GetSentenceInBuffer(ctx context.Context, interviewID uint) string
 	// Returns the size of the hashmap
@@ -27,25 +63,50 @@ type TranscriptManager interface {
 }
 
 func NewTranscriptManager(
+	llmConfig *config.LLMConfig,
+	aiUseCase AIUseCase,
 	transcriptRepo repo.TranscriptRepo,
+	codeSnapshotRepo repo.CodeSnapshotRepo,
+	fileRepo repo.FileRepo,
+	objectStorageConfig *config.ObjectStorageConfig,
+	promptService PromptService,
 ) TranscriptManager {
 	return &TranscriptManagerImpl{
-		transcriptRepo: transcriptRepo,
-		bufferMap:      make(map[uint]*strings.Builder),
+		llmConfig:           llmConfig,
+		objectStorageConfig: objectStorageConfig,
+		aiUseCase:           aiUseCase,
+		transcriptRepo:      transcriptRepo,
+		codeSnapshotRepo:    codeSnapshotRepo,
+		fileRepo:            fileRepo,
+		promptService:       promptService,
+		bufferMap:           make(map[uint]*strings.Builder),
+		latestCodeMap:       make(map[uint]string),
 	}
 }
 
 type TranscriptManagerImpl struct {
-	transcriptRepo repo.TranscriptRepo
-	bufferMap      map[uint]*strings.Builder
+	llmConfig           *config.LLMConfig
+	objectStorageConfig *config.ObjectStorageConfig
+	aiUseCase           AIUseCase
+	transcriptRepo      repo.TranscriptRepo
+	codeSnapshotRepo    repo.CodeSnapshotRepo
+	fileRepo            repo.FileRepo
+	promptService       PromptService
+	// Every interview streams on its own goroutine and the timers write to the same buffers, so both maps
+	// and the buffers in them are only touched while holding the lock
+	mu        sync.RWMutex
+	bufferMap map[uint]*strings.Builder
+	// Caches the latest code snapshot so that the DB is not queried for every chunk
+	latestCodeMap map[uint]string
 }
 
 // PrepareInterviewer implements TranscriptManager.
-func (t *TranscriptManagerImpl) PrepareInterviewer(ctx context.Context, interviewID uint, prompt string) error {
+func (t *TranscriptManagerImpl) PrepareInterviewer(ctx context.Context, interviewID uint, prompt *model.RenderedPrompt) error {
 	transcript := entity.NewTranscript().
 		SetRole(entity.SYSTEM).
-		SetContent(prompt).
-		SetInterviewID(interviewID)
+		SetContent(prompt.Content).
+		SetInterviewID(interviewID).
+		SetPrompt(prompt)
 
 	if err := t.transcriptRepo.Create(ctx, transcript); err != nil {
 		return err
@@ -54,119 +115,551 @@ func (t *TranscriptManagerImpl) PrepareInterviewer(ctx context.Context, intervie
 	return nil
 }
 
-func (t *TranscriptManagerImpl) GetTranscriptHistoryInLLMMessageFormat(ctx context.Context, interviewID uint) ([]*model.LLMMessage, error) {
+// GetTranscriptHistoryInLLMMessageFormat implements TranscriptManager.
+func (t *TranscriptManagerImpl) GetTranscriptHistoryInLLMMessageFormat(ctx context.Context, interviewID uint, llmModel string) ([]*model.LLMMessage, error) {
 	transcriptHistory, err := t.transcriptRepo.ListByInterviewIDAsc(ctx, interviewID)
 	if err != nil {
 		return nil, err
 	}
 
-	llmMessages := make([]*model.LLMMessage, 0)
+	codeMessage, err := t.getLatestCodeMessage(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.buildLLMMessages(ctx, interviewID, transcriptHistory, codeMessage, llmModel, true)
+}
+
+// GetTranscriptHistoryBefore implements TranscriptManager.
+func (t *TranscriptManagerImpl) GetTranscriptHistoryBefore(ctx context.Context, interviewID, transcriptID uint, systemPrompt *model.RenderedPrompt, llmModel string) ([]*model.LLMMessage, error) {
+	transcriptHistory, err := t.transcriptRepo.ListByInterviewIDAsc(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *entity.Transcript
+	earlierTranscripts := make([]*entity.Transcript, 0)
+	systemPromptReplaced := false
+	for _, transcript := range transcriptHistory {
+		if transcript.ID == transcriptID {
+			target = transcript
+			break
+		}
+		if transcript.Role == entity.SYSTEM && systemPrompt.Exists() {
+			if !systemPromptReplaced {
+				earlierTranscripts = append(earlierTranscripts, entity.NewTranscript().
+					SetRole(entity.SYSTEM).
+					SetContent(systemPrompt.Content).
+					SetInterviewID(interviewID).
+					SetPrompt(systemPrompt))
+				systemPromptReplaced = true
+			}
+			continue
+		}
+		earlierTranscripts = append(earlierTranscripts, transcript)
+	}
+
+	if !target.Exists() {
+		return nil, fmt.Errorf("transcript id %d is not part of interview id %d: %w", transcriptID, interviewID, common.ErrNotFound)
+	}
+
+	codeSnapshots, err := t.codeSnapshotRepo.ListByInterviewIDAsc(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	var code string
+	for _, codeSnapshot := range codeSnapshots {
+		if codeSnapshot.CreateTimestampMS > target.CreateTimestampMS {
+			break
+		}
+		code = codeSnapshot.Code
+	}
+
+	return t.buildLLMMessages(ctx, interviewID, earlierTranscripts, t.newCodeMessage(code), llmModel, false)
+}
+
+// The turns that are not covered by the latest summary are summarized only when summarizing is allowed,
+// otherwise the oldest turns are dropped
+func (t *TranscriptManagerImpl) buildLLMMessages(
+	ctx context.Context,
+	interviewID uint,
+	transcriptHistory []*entity.Transcript,
+	codeMessage *model.LLMMessage,
+	llmModel string,
+	allowSummary bool,
+) ([]*model.LLMMessage, error) {
+	// Only the latest summary is needed as every summary includes the one before it
+	systemMessages := make([]*model.LLMMessage, 0)
+	var latestSummary *entity.Transcript
 	for _, transcript := range transcriptHistory {
-		llmMessages = append(llmMessages, transcript.ToLLMMessage())
+		switch transcript.Role {
+		case entity.SYSTEM:
+			systemMessages = append(systemMessages, transcript.ToLLMMessage())
+		case entity.SUMMARY:
+			latestSummary = transcript
+		}
+	}
+
+	turns := make([]*entity.Transcript, 0)
+	for _, transcript := range transcriptHistory {
+		if !transcript.IsConversationTurn() || transcript.ExcludedFromContext {
+			continue
+		}
+		if latestSummary.Exists() && transcript.ID <= util.FromPtr(latestSummary.SummarizedUpToID) {
+			continue
+		}
+		turns = append(turns, transcript)
+	}
+
+	if llmModel == "" {
+		llmModel = t.llmConfig.Model
+	}
+
+	budget := int(t.llmConfig.GetContextBudget(llmModel)) - config.CONTEXT_WINDOW_REPLY_RESERVE
+	fixedTokens := model.EstimateTokens(systemMessages) + codeMessage.EstimateTokens()
+
+	if allowSummary && fixedTokens+t.estimateTokens(latestSummary, turns) > budget && len(turns) > config.CONTEXT_WINDOW_MIN_RECENT_TURNS {
+		// Half of what is left is freed up so that the summary is not redone on every reply
+		keptCount := t.countRecentTurnsWithin(turns, (budget-fixedTokens-t.estimateTokens(latestSummary, nil))/2)
+
+		summary, err := t.summarize(ctx, interviewID, latestSummary, turns[:len(turns)-keptCount])
+		if err != nil {
+			log.Error().Err(err).Uint("interview_id", interviewID).Msg("unable to summarize the transcript, dropping the oldest turns instead")
+		} else {
+			latestSummary = summary
+			turns = turns[len(turns)-keptCount:]
+		}
+	}
+
+	// This is the fallback when the summary cannot be made or is still too long, the oldest turns are dropped
+	for fixedTokens+t.estimateTokens(latestSummary, turns) > budget && len(turns) > config.CONTEXT_WINDOW_MIN_RECENT_TURNS {
+		turns = turns[1:]
+	}
+
+	llmMessages := make([]*model.LLMMessage, 0)
+	llmMessages = append(llmMessages, systemMessages...)
+	if latestSummary.Exists() {
+		llmMessages = append(llmMessages, latestSummary.ToLLMMessage())
+	}
+	for _, turn := range turns {
+		llmMessages = append(llmMessages, turn.ToLLMMessage())
+	}
+	if codeMessage != nil {
+		llmMessages = append(llmMessages, codeMessage)
 	}
 
 	return llmMessages, nil
 }
 
+func (t *TranscriptManagerImpl) estimateTokens(summary *entity.Transcript, turns []*entity.Transcript) int {
+	tokens := 0
+	if summary.Exists() {
+		tokens += summary.ToLLMMessage().EstimateTokens()
+	}
+	for _, turn := range turns {
+		tokens += turn.ToLLMMessage().EstimateTokens()
+	}
+	return tokens
+}
+
+// Counts from the latest turn backwards, but never goes below the minimum
+func (t *TranscriptManagerImpl) countRecentTurnsWithin(turns []*entity.Transcript, budget int) int {
+	count := 0
+	tokens := 0
+	for index := len(turns) - 1; index >= 0; index-- {
+		tokens += turns[index].ToLLMMessage().EstimateTokens()
+		if tokens > budget {
+			break
+		}
+		count++
+	}
+
+	return min(max(count, config.CONTEXT_WINDOW_MIN_RECENT_TURNS), len(turns))
+}
+
+// The previous summary is folded into the new one, which is stored so that the turns are only summarized once
+func (t *TranscriptManagerImpl) summarize(ctx context.Context, interviewID uint, previousSummary *entity.Transcript, turns []*entity.Transcript) (*entity.Transcript, error) {
+	if len(turns) == 0 {
+		return previousSummary, nil
+	}
+
+	var record strings.Builder
+	if previousSummary.Exists() {
+		fmt.Fprintf(&record, "Summary so far:\n%s\n\n", previousSummary.Content)
+	}
+	record.WriteString("Conversation:\n")
+	for _, turn := range turns {
+		if turn.Role == entity.ASSISTANT {
+			fmt.Fprintf(&record, "Interviewer: %s\n", turn.Content)
+			continue
+		}
+		fmt.Fprintf(&record, "Candidate: %s\n", model.DelimitUntrustedContent(model.CANDIDATE_SPEECH_TAG, turn.Content))
+	}
+
+	prompt, err := t.promptService.Render(ctx, model.TRANSCRIPT_SUMMARY_PROMPT, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	llmMessages := []*model.LLMMessage{
+		model.NewLLMMessage().
+			SetRole(model.SYSTEM).
+			SetContent(prompt.Content),
+		model.NewLLMMessage().
+			SetRole(model.USER).
+			SetContent(record.String()),
+	}
+
+	content, err := t.aiUseCase.GenerateTextReply(ctx, llmMessages, "")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := entity.NewSummaryTranscript().
+		SetContent(strings.TrimSpace(content)).
+		SetInterviewID(interviewID).
+		SetSummarizedUpToID(turns[len(turns)-1].ID).
+		SetPrompt(prompt)
+
+	if err := t.transcriptRepo.Create(ctx, summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// Returns nil when the candidate has not written any code yet
+func (t *TranscriptManagerImpl) getLatestCodeMessage(ctx context.Context, interviewID uint) (*model.LLMMessage, error) {
+	latestCode, ok := t.getCachedLatestCode(interviewID)
+	if !ok {
+		latestCodeSnapshot, err := t.codeSnapshotRepo.GetLatestByInterviewID(ctx, interviewID)
+		if err != nil && !errors.Is(err, common.ErrNotFound) {
+			return nil, err
+		}
+		if latestCodeSnapshot.Exists() {
+			latestCode = latestCodeSnapshot.Code
+		}
+	}
+
+	return t.newCodeMessage(latestCode), nil
+}
+
+// Returns nil when there is no code
+func (t *TranscriptManagerImpl) newCodeMessage(code string) *model.LLMMessage {
+	if strings.TrimSpace(code) == "" {
+		return nil
+	}
+
+	return model.NewLLMMessage().
+		SetRole(model.SYSTEM).
+		SetContent("The latest code of the candidate:\n" + model.DelimitUntrustedContent(model.CANDIDATE_CODE_TAG, code))
+}
+
 // FlushAndRemoveInterview implements TranscriptManager.
 func (t *TranscriptManagerImpl) FlushAndRemoveInterview(ctx context.Context, interviewID uint) error {
 	if err := t.FlushCandidate(ctx, interviewID); err != nil {
 		return err
 	}
 
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	delete(t.bufferMap, interviewID)
+	delete(t.latestCodeMap, interviewID)
 	return nil
 }
 
+// WriteCode implements TranscriptManager.
+func (t *TranscriptManagerImpl) WriteCode(ctx context.Context, interviewID uint, code string) error {
+	if strings.TrimSpace(code) == "" {
+		return nil
+	}
+
+	latestCode, ok := t.getCachedLatestCode(interviewID)
+	if !ok {
+		latestCodeSnapshot, err := t.codeSnapshotRepo.GetLatestByInterviewID(ctx, interviewID)
+		if err != nil && !errors.Is(err, common.ErrNotFound) {
+			return err
+		}
+		if latestCodeSnapshot.Exists() {
+			latestCode = latestCodeSnapshot.Code
+		}
+	}
+
+	if latestCode == code {
+		t.setCachedLatestCode(interviewID, code)
+		return nil
+	}
+
+	codeSnapshot := entity.NewCodeSnapshot().
+		SetInterviewID(interviewID).
+		SetCode(code)
+
+	if err := t.codeSnapshotRepo.Create(ctx, codeSnapshot); err != nil {
+		return err
+	}
+
+	t.setCachedLatestCode(interviewID, code)
+	return nil
+}
+
+func (t *TranscriptManagerImpl) getCachedLatestCode(interviewID uint) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	latestCode, ok := t.latestCodeMap[interviewID]
+	return latestCode, ok
+}
+
+func (t *TranscriptManagerImpl) setCachedLatestCode(interviewID uint, code string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.latestCodeMap[interviewID] = code
+}
+
+// GetCodeSnapshots implements TranscriptManager.
+func (t *TranscriptManagerImpl) GetCodeSnapshots(ctx context.Context, interviewID uint) ([]*entity.CodeSnapshot, error) {
+	return t.codeSnapshotRepo.ListByInterviewIDAsc(ctx, interviewID)
+}
+
 // GetManagerInfo implements TranscriptManager.
 func (t *TranscriptManagerImpl) GetManagerInfo() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
 	return uint(len(t.bufferMap))
 }
 
 // GetSentenceInBuffer implements TranscriptManager.
 func (t *TranscriptManagerImpl) GetSentenceInBuffer(ctx context.Context, interviewID uint) string {
-	t.initialiseBuffer(interviewID)
-	buffer, ok := t.bufferMap[interviewID]
-	if !ok {
-		return ""
-	}
-
-	return strings.ToLower(strings.TrimSpace(buffer.String()))
+	return strings.ToLower(strings.TrimSpace(t.readBuffer(interviewID)))
 }
 
 // WordsInBuffer implements TranscriptManager.
 func (t *TranscriptManagerImpl) HasSufficientWordsInBuffer(ctx context.Context, interviewID uint) (bool, error) {
-	t.initialiseBuffer(interviewID)
-	buffer, ok := t.bufferMap[interviewID]
-	if !ok {
-		return false, fmt.Errorf("no buffer exists for interview id %d: %w", interviewID, common.ErrInternalServerError)
-	}
-
-	if buffer.Len() > 30 {
-		return true, nil
-	}
-
-	return false, nil
+	return len(t.readBuffer(interviewID)) > 30, nil
 }
 
 func (t *TranscriptManagerImpl) GetTranscriptHistory(ctx context.Context, interviewID uint) ([]*entity.Transcript, error) {
 	return t.transcriptRepo.ListByInterviewIDAsc(ctx, interviewID)
 }
 
-func (t *TranscriptManagerImpl) WriteInterviewer(ctx context.Context, interviewID uint, chunk, url string) error {
+func (t *TranscriptManagerImpl) WriteInterviewer(ctx context.Context, interviewID uint, chunk string, audioLocation *model.FileLocation, prompt *model.RenderedPrompt) (*entity.Transcript, error) {
 	transcript := entity.NewInterviewerTranscript().
 		SetContent(strings.TrimSpace(chunk)).
 		SetInterviewID(interviewID).
-		SetURL(url)
+		SetAudioLocation(audioLocation).
+		SetPrompt(prompt)
 
 	err := t.transcriptRepo.Create(ctx, transcript)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return transcript, nil
+}
+
+// GetInterviewerSequence implements TranscriptManager.
+func (t *TranscriptManagerImpl) GetInterviewerSequence(ctx context.Context, transcript *entity.Transcript) (uint, error) {
+	// The IDs only grow, so the sequence stays the same no matter when it is read
+	return t.transcriptRepo.CountByInterviewIDAndRoleUpToID(ctx, transcript.InterviewID, entity.ASSISTANT, transcript.ID)
 }
 
 func (t *TranscriptManagerImpl) FlushCandidate(ctx context.Context, interviewID uint) error {
-	t.initialiseBuffer(interviewID)
-	buffer, ok := t.bufferMap[interviewID]
-	if !ok {
-		return fmt.Errorf("no buffer exists for interview id %d: %w", interviewID, common.ErrInternalServerError)
-	}
+	return t.FlushCandidateWithIntent(ctx, interviewID, entity.NO_INTENT, 0)
+}
+
+// FlushCandidateWithIntent implements TranscriptManager.
+func (t *TranscriptManagerImpl) FlushCandidateWithIntent(ctx context.Context, interviewID uint, intent entity.Intent, confidence float64) error {
+	return t.flushCandidate(ctx, interviewID, intent, confidence, false)
+}
+
+// FlushCandidateExcludedFromContext implements TranscriptManager.
+func (t *TranscriptManagerImpl) FlushCandidateExcludedFromContext(ctx context.Context, interviewID uint, intent entity.Intent, confidence float64) error {
+	return t.flushCandidate(ctx, interviewID, intent, confidence, true)
+}
+
+func (t *TranscriptManagerImpl) flushCandidate(ctx context.Context, interviewID uint, intent entity.Intent, confidence float64, excludedFromContext bool) error {
+	// The buffer is emptied before the write so that the lock is not held across the DB call
+	content := t.takeBuffer(interviewID)
 
 	// Nothing to flush into DB
-	if len(buffer.String()) == 0 {
+	if len(content) == 0 {
 		return nil
 	}
 
 	trancript := entity.NewCandidateTranscript().
-		SetContent(strings.TrimSpace(buffer.String())).
-		SetInterviewID(interviewID)
+		SetContent(strings.TrimSpace(content)).
+		SetInterviewID(interviewID).
+		SetIntent(intent, confidence).
+		SetExcludedFromContext(excludedFromContext)
 
 	err := t.transcriptRepo.Create(ctx, trancript)
 	if err != nil {
+		t.restoreBuffer(interviewID, content)
 		return err
 	}
 
-	buffer.Reset()
 	return nil
 }
 
-func (t *TranscriptManagerImpl) initialiseBuffer(interviewID uint) {
-	_, ok := t.bufferMap[interviewID]
+func (t *TranscriptManagerImpl) readBuffer(interviewID uint) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	buffer, ok := t.bufferMap[interviewID]
 	if !ok {
-		t.bufferMap[interviewID] = &strings.Builder{}
+		return ""
 	}
+	return buffer.String()
 }
 
-// Write implements TranscriptManager.
-func (t *TranscriptManagerImpl) WriteCandidate(ctx context.Context, interviewID uint, chunk string) error {
-	t.initialiseBuffer(interviewID)
+func (t *TranscriptManagerImpl) appendToBuffer(interviewID uint, chunk string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buffer, ok := t.bufferMap[interviewID]
+	if !ok {
+		buffer = &strings.Builder{}
+		t.bufferMap[interviewID] = buffer
+	}
+	buffer.WriteString(chunk)
+}
+
+// Returns the content of the buffer and leaves it empty
+func (t *TranscriptManagerImpl) takeBuffer(interviewID uint) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	buffer, ok := t.bufferMap[interviewID]
 	if !ok {
-		return fmt.Errorf("buffer is not initialised: %w", common.ErrInternalServerError)
+		return ""
+	}
+	content := buffer.String()
+	buffer.Reset()
+	return content
+}
+
+// Puts back what could not be flushed, ahead of whatever was written in the meantime
+func (t *TranscriptManagerImpl) restoreBuffer(interviewID uint, content string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buffer, ok := t.bufferMap[interviewID]
+	if !ok {
+		buffer = &strings.Builder{}
+		t.bufferMap[interviewID] = buffer
+	}
+	rest := buffer.String()
+	buffer.Reset()
+	buffer.WriteString(content + rest)
+}
+
+// Write implements TranscriptManager.
+func (t *TranscriptManagerImpl) WriteCandidate(ctx context.Context, interviewID uint, chunk string) error {
+	t.appendToBuffer(interviewID, " "+chunk)
+	return nil
+}
+
+// GetAudioURL implements TranscriptManager.
+func (t *TranscriptManagerImpl) GetAudioURL(ctx context.Context, transcript *entity.Transcript) (string, error) {
+	audioLocation, err := t.getAudioLocation(transcript)
+	if err != nil {
+		return "", err
+	}
+
+	if !audioLocation.Exists() {
+		return "", nil
+	}
+
+	expiresAtS := time.Now().Add(common.PRESIGNED_URL_EXPIRY_DURATION).Unix()
+
+	signedURL, err := url.JoinPath(t.objectStorageConfig.PublicBaseURL, fmt.Sprintf(config.TRANSCRIPT_AUDIO_URL_PATH_FORMAT, transcript.UUID))
+	if err != nil {
+		return "", fmt.Errorf("unable to build audio url for transcript %s, %s: %w", transcript.UUID, err, common.ErrInternalServerError)
+	}
+
+	query := url.Values{}
+	query.Set(config.SIGNED_FILE_URL_EXPIRES_QUERY_KEY, strconv.FormatInt(expiresAtS, 10))
+	query.Set(config.SIGNED_FILE_URL_SIGNATURE_QUERY_KEY, util.SignHMAC(t.objectStorageConfig.SigningSecret, transcript.GetAudioSigningPayload(expiresAtS)))
+
+	return signedURL + "?" + query.Encode(), nil
+}
+
+// VerifyAudioURLSignature implements TranscriptManager.
+func (t *TranscriptManagerImpl) VerifyAudioURLSignature(transcript *entity.Transcript, expiresAtS int64, signature string) error {
+	if !util.VerifyHMAC(t.objectStorageConfig.SigningSecret, transcript.GetAudioSigningPayload(expiresAtS), signature) {
+		return fmt.Errorf("invalid signature for the audio of transcript %s: %w", transcript.UUID, common.ErrUnauthorized)
+	}
+
+	if time.Now().Unix() > expiresAtS {
+		return fmt.Errorf("signed url for the audio of transcript %s has expired: %w", transcript.UUID, common.ErrUnauthorized)
 	}
 
-	buffer.WriteString(" " + chunk)
 	return nil
 }
+
+// OpenAudio implements TranscriptManager.
+func (t *TranscriptManagerImpl) OpenAudio(ctx context.Context, transcript *entity.Transcript) (*model.FileContent, error) {
+	audioLocation, err := t.getAudioLocation(transcript)
+	if err != nil {
+		return nil, err
+	}
+
+	if !audioLocation.Exists() {
+		return nil, fmt.Errorf("transcript %s has no audio: %w", transcript.UUID, common.ErrNotFound)
+	}
+
+	return t.fileRepo.Open(ctx, audioLocation)
+}
+
+// GetTranscript implements TranscriptManager.
+func (t *TranscriptManagerImpl) GetTranscript(ctx context.Context, transcriptID string) (*entity.Transcript, error) {
+	return t.transcriptRepo.GetByUUID(ctx, transcriptID)
+}
+
+// Transcripts that have not been migrated yet still have the location in the expired URL
+func (t *TranscriptManagerImpl) getAudioLocation(transcript *entity.Transcript) (*model.FileLocation, error) {
+	audioLocation := transcript.GetAudioLocation()
+	if audioLocation.Exists() || !transcript.HasLegacyURL() {
+		return audioLocation, nil
+	}
+
+	return t.fileRepo.GetLocationFromURL(*transcript.URL)
+}
+
+// MigrateAudioLocations implements TranscriptManager.
+func (t *TranscriptManagerImpl) MigrateAudioLocations(ctx context.Context) (uint, error) {
+	var migratedCount uint
+	var afterID uint
+
+	for {
+		transcripts, err := t.transcriptRepo.ListWithLegacyURL(ctx, afterID, config.AUDIO_LOCATION_MIGRATION_BATCH_SIZE)
+		if err != nil {
+			return migratedCount, err
+		}
+
+		if len(transcripts) == 0 {
+			return migratedCount, nil
+		}
+
+		for _, transcript := range transcripts {
+			afterID = transcript.ID
+
+			// URLs that cannot be parsed are left as they are so that the rest can still be migrated
+			location, err := t.fileRepo.GetLocationFromURL(*transcript.URL)
+			if err != nil {
+				continue
+			}
+
+			transcript.SetAudioLocation(location)
+			if err := t.transcriptRepo.Update(ctx, transcript); err != nil {
+				return migratedCount, err
+			}
+
+			migratedCount++
+		}
+	}
+}
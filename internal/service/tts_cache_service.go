@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ahleongzc/leetcode-live-backend/internal/common"
+	"github.com/ahleongzc/leetcode-live-backend/internal/config"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/entity"
+	"github.com/ahleongzc/leetcode-live-backend/internal/domain/model"
+	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
+	"github.com/ahleongzc/leetcode-live-backend/internal/util"
+)
+
+type TTSCacheService interface {
+	// Identical speech is only synthesized and uploaded once, every later request reuses the stored audio
+	Synthesize(ctx context.Context, text, instruction string) (*model.FileLocation, error)
+}
+
+func NewTTSCacheService(
+	ttsConfig *config.TTSConfig,
+	aiUseCase AIUseCase,
+	fileRepo repo.FileRepo,
+	ttsCacheEntryRepo repo.TTSCacheEntryRepo,
+) TTSCacheService {
+	return &TTSCacheServiceImpl{
+		ttsConfig:         ttsConfig,
+		aiUseCase:         aiUseCase,
+		fileRepo:          fileRepo,
+		ttsCacheEntryRepo: ttsCacheEntryRepo,
+		index:             util.NewLRUCache[string, *model.FileLocation](config.TTS_CACHE_LRU_CAPACITY),
+	}
+}
+
+type TTSCacheServiceImpl struct {
+	ttsConfig         *config.TTSConfig
+	aiUseCase         AIUseCase
+	fileRepo          repo.FileRepo
+	ttsCacheEntryRepo repo.TTSCacheEntryRepo
+	// The in memory index in front of the one in Postgres, keyed by the hash
+	index *util.LRUCache[string, *model.FileLocation]
+}
+
+// Synthesize implements TTSCacheService.
+func (t *TTSCacheServiceImpl) Synthesize(ctx context.Context, text, instruction string) (*model.FileLocation, error) {
+	hash := t.hash(text, instruction)
+
+	if location, ok := t.index.Get(hash); ok {
+		return location, nil
+	}
+
+	ttsCacheEntry, err := t.ttsCacheEntryRepo.GetByHash(ctx, hash)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return nil, err
+	}
+
+	if ttsCacheEntry.Exists() {
+		location := ttsCacheEntry.GetAudioLocation()
+		t.index.Put(hash, location)
+		return location, nil
+	}
+
+	reader, err := t.aiUseCase.GenerateSpeechReply(ctx, text, instruction)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key only depends on the hash, so a concurrent synthesis of the same text overwrites it with the same audio
+	location, err := t.fileRepo.Upload(ctx, fmt.Sprintf("%s/%s.mp3", config.TTS_CACHE_KEY_PREFIX, hash), reader, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ttsCacheEntry = entity.NewTTSCacheEntry().
+		SetHash(hash).
+		SetProvider(t.ttsConfig.Provider).
+		SetModel(t.ttsConfig.Model).
+		SetVoice(t.ttsConfig.Voice).
+		SetText(text).
+		SetAudioLocation(location)
+
+	if err := t.ttsCacheEntryRepo.Create(ctx, ttsCacheEntry); err != nil {
+		return nil, err
+	}
+
+	t.index.Put(hash, location)
+
+	return location, nil
+}
+
+// The instruction is trimmed so that the indentation of the prompt literals does not change the hash
+func (t *TTSCacheServiceImpl) hash(text, instruction string) string {
+	parts := []string{
+		t.ttsConfig.Provider,
+		t.ttsConfig.Model,
+		t.ttsConfig.Voice,
+		t.ttsConfig.Language,
+		strings.TrimSpace(instruction),
+		strings.TrimSpace(text),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -18,6 +18,7 @@ type UserService interface {
 	RegisterNewUser(ctx context.Context, email, password string) error
 	GetUserProfile(ctx context.Context, userID uint) (*model.UserProfile, error)
 	GetUserSetting(ctx context.Context, userID uint) (*entity.Setting, error)
+	GrantRole(ctx context.Context, email string, role entity.UserRole) error
 }
 
 func NewUserService(
@@ -35,6 +36,26 @@ type UserServiceImpl struct {
 	settingRepo repo.SettingRepo
 }
 
+// GrantRole implements UserService.
+func (u *UserServiceImpl) GrantRole(ctx context.Context, email string, role entity.UserRole) error {
+	if !isValidRole(role) {
+		return fmt.Errorf("invalid role %s, must be one of %s, %s or %s: %w", role, entity.CANDIDATE_ROLE, entity.REVIEWER_ROLE, entity.ADMIN_ROLE, common.ErrBadRequest)
+	}
+
+	user, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	user.SetRole(role)
+
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetUserSetting implements UserService.
 func (u *UserServiceImpl) GetUserSetting(ctx context.Context, userID uint) (*entity.Setting, error) {
 	user, err := u.userRepo.GetByID(ctx, userID)
@@ -108,7 +129,8 @@ func (u *UserServiceImpl) RegisterNewUser(ctx context.Context, email, password s
 		SetSettingID(settingID).
 		SetEmail(email).
 		SetPassword(hashedPassword).
-		SetUsername(email)
+		SetUsername(email).
+		SetRole(entity.CANDIDATE_ROLE)
 
 	if err := u.userRepo.Create(ctx, user); err != nil {
 		return err
@@ -128,6 +150,10 @@ func (u *UserServiceImpl) createDefaultSetting(ctx context.Context) (uint, error
 	return settingID, nil
 }
 
+func isValidRole(role entity.UserRole) bool {
+	return role == entity.CANDIDATE_ROLE || role == entity.REVIEWER_ROLE || role == entity.ADMIN_ROLE
+}
+
 func isValidPassword(password string) bool {
 	return len(password) >= 8 && len(password) <= 20
 }
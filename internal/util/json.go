@@ -19,17 +19,94 @@ func (j JSONPayload) Add(key string, value any) {
 }
 
 func StringToJSON(input string, dst any) error {
-	start := strings.Index(input, "{")
-	end := strings.LastIndex(input, "}")
-
-	if start == -1 || end == -1 || start > end {
-		return fmt.Errorf("invalid JSON format: missing or misplaced '{' or '}', the string is %s: %w", input, common.ErrInternalServerError)
+	jsonString, err := ExtractJSON(input)
+	if err != nil {
+		return err
 	}
 
-	jsonString := input[start : end+1]
 	if err := json.Unmarshal([]byte(jsonString), dst); err != nil {
 		return fmt.Errorf("failed to unmarshal JSON, %s: %w", err, common.ErrInternalServerError)
 	}
 
 	return nil
 }
+
+// ExtractJSON returns the first JSON object in the input. LLMs tend to wrap the object in a markdown
+// fence or add prose around it, so a fenced block is preferred and the object is found by matching braces.
+func ExtractJSON(input string) (string, error) {
+	if fenced, ok := extractFencedBlock(input); ok {
+		if jsonString, ok := extractBalancedObject(fenced); ok {
+			return jsonString, nil
+		}
+	}
+
+	if jsonString, ok := extractBalancedObject(input); ok {
+		return jsonString, nil
+	}
+
+	return "", fmt.Errorf("invalid JSON format: no complete JSON object found, the string is %s: %w", input, common.ErrInternalServerError)
+}
+
+func extractFencedBlock(input string) (string, bool) {
+	const fence = "```"
+
+	start := strings.Index(input, fence)
+	if start == -1 {
+		return "", false
+	}
+
+	// Skip the language tag, e.g. ```json
+	content := input[start+len(fence):]
+	if newline := strings.Index(content, "\n"); newline != -1 {
+		content = content[newline+1:]
+	}
+
+	end := strings.Index(content, fence)
+	if end == -1 {
+		return "", false
+	}
+
+	return content[:end], true
+}
+
+// Braces inside strings are ignored, so that a feedback like "use a {} literal" does not end the object early
+func extractBalancedObject(input string) (string, bool) {
+	start := strings.Index(input, "{")
+	if start == -1 {
+		return "", false
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := start; i < len(input); i++ {
+		char := input[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case char == '\\':
+				escaped = true
+			case char == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch char {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return input[start : i+1], true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        string
+		expectedJSON string
+		expectError  bool
+	}{
+		{
+			name:         "unfenced object",
+			input:        `{"score": 80, "passed": true}`,
+			expectedJSON: `{"score": 80, "passed": true}`,
+		},
+		{
+			name:         "fenced object with a language tag",
+			input:        "Here is the review:\n```json\n{\"score\": 80}\n```\nLet me know if you need more.",
+			expectedJSON: `{"score": 80}`,
+		},
+		{
+			name:         "fenced object without a language tag",
+			input:        "```\n{\"score\": 80}\n```",
+			expectedJSON: `{"score": 80}`,
+		},
+		{
+			name:         "fence without an object falls back to the unfenced object",
+			input:        "```\nno object here\n```\n{\"score\": 80}",
+			expectedJSON: `{"score": 80}`,
+		},
+		{
+			name:         "leading and trailing prose",
+			input:        `Sure! {"score": 80} I hope this helps, {"ignored": true}`,
+			expectedJSON: `{"score": 80}`,
+		},
+		{
+			name:         "braces inside strings",
+			input:        `{"feedback": "use a {} literal and close it with }"} trailing`,
+			expectedJSON: `{"feedback": "use a {} literal and close it with }"}`,
+		},
+		{
+			name:         "escaped quotes inside strings",
+			input:        `{"feedback": "say \"}\" out loud"}`,
+			expectedJSON: `{"feedback": "say \"}\" out loud"}`,
+		},
+		{
+			name:         "nested objects and arrays",
+			input:        `{"rubric": [{"dimension": "coding", "score": 4}], "meta": {"model": "x"}} done`,
+			expectedJSON: `{"rubric": [{"dimension": "coding", "score": 4}], "meta": {"model": "x"}}`,
+		},
+		{
+			name:        "truncated object",
+			input:       `{"score": 80, "feedback": "the answer was`,
+			expectError: true,
+		},
+		{
+			name:        "no object",
+			input:       "I am unable to review this interview.",
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			jsonString, err := ExtractJSON(testCase.input)
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", jsonString)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("extract: %v", err)
+			}
+
+			if jsonString != testCase.expectedJSON {
+				t.Errorf("json = %q, want %q", jsonString, testCase.expectedJSON)
+			}
+		})
+	}
+}
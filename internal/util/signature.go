@@ -0,0 +1,19 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Returns the hex encoded HMAC-SHA256 of the payload
+func SignHMAC(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Compares in constant time so that the signature cannot be guessed byte by byte
+func VerifyHMAC(secret, payload, signature string) bool {
+	return hmac.Equal([]byte(SignHMAC(secret, payload)), []byte(signature))
+}
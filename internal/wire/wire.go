@@ -13,7 +13,6 @@ import (
 	rpchandler "github.com/ahleongzc/leetcode-live-backend/internal/handler/rpc_handler"
 	"github.com/ahleongzc/leetcode-live-backend/internal/handler/rpc_handler/interceptor"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
-	"github.com/ahleongzc/leetcode-live-backend/internal/repo/cloudflare"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo/fasttext"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo/http"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo/postgres"
@@ -27,12 +26,16 @@ func InitializeApplication() (*app.Application, error) {
 	wire.Build(
 		// Consumer
 		consumer.NewReviewConsumer,
+		consumer.NewStudyPlanConsumer,
 
 		// HTTP Handler
 		httphandler.NewAuthHandler,
+		httphandler.NewAdminHandler,
+		httphandler.NewReviewerHandler,
 		httphandler.NewHealthHandler,
 		httphandler.NewInterviewHandler,
 		httphandler.NewUserHandler,
+		httphandler.NewFileHandler,
 
 		// RPC Handler
 		rpchandler.NewProxyHandler,
@@ -43,13 +46,26 @@ func InitializeApplication() (*app.Application, error) {
 		service.NewInterviewService,
 		service.NewQuestionService,
 		service.NewReviewService,
+		service.NewReviewerService,
+		service.NewStudyPlanService,
+		service.NewTranscriptExportService,
 		service.NewTranscriptManager,
+		service.NewIntentSampleService,
+		service.NewFileService,
+		service.NewTTSCacheService,
+		service.NewSpeechNormalizer,
+		service.NewCannedPhraseService,
+		service.NewPromptService,
+		service.NewExperimentService,
+		service.NewReplyService,
+		service.NewGuardService,
 
 		// Use case
 		service.NewAIUseCase,
 
 		// Repo
 		repo.NewReviewRepo,
+		repo.NewTransactionRepo,
 		repo.NewSettingRepo,
 		repo.NewQuestionRepo,
 		repo.NewSessionRepo,
@@ -61,6 +77,19 @@ func InitializeApplication() (*app.Application, error) {
 		repo.NewTTSRepo,
 		repo.NewInMemoryCallbackQueueRepo,
 		repo.NewIntentClassificationRepo,
+		repo.NewIntentSampleRepo,
+		repo.NewRubricScoreRepo,
+		repo.NewFeedbackItemRepo,
+		repo.NewCodeSnapshotRepo,
+		repo.NewTranscriptAnnotationRepo,
+		repo.NewAuditLogRepo,
+		repo.NewStudyPlanRepo,
+		repo.NewInterviewEventRepo,
+		repo.NewTTSCacheEntryRepo,
+		repo.NewPromptTemplateRepo,
+		repo.NewExperimentRepo,
+		repo.NewExperimentVariantRepo,
+		repo.NewGuardActivationRepo,
 		wire.NewSet(
 			repo.NewMessageQueueRepo,
 			wire.Bind(new(repo.MessageQueueProducerRepo), new(repo.MessageQueueRepo)),
@@ -85,11 +114,9 @@ func InitializeApplication() (*app.Application, error) {
 		// Zerolog
 		zerolog.NewZerologLogger,
 
-		// Cloudflare
-		cloudflare.NewCloudflareR2ObjectStorageClient,
-
 		// Config
 		config.LoadLLMConfig,
+		config.LoadInjectionConfig,
 		config.LoadDatabaseConfig,
 		config.LoadObjectStorageConfig,
 		config.LoadTTSConfig,
@@ -115,3 +142,64 @@ func InitializeApplication() (*app.Application, error) {
 	)
 	return &app.Application{}, nil
 }
+
+func InitializeCLI() (*app.CLI, error) {
+	wire.Build(
+		// Service
+		service.NewIntentSampleService,
+		service.NewReviewService,
+		service.NewTranscriptManager,
+		service.NewPromptService,
+		service.NewReplyService,
+		service.NewGuardService,
+		service.NewRegressionService,
+		service.NewUserService,
+
+		// Use case
+		service.NewAIUseCase,
+
+		// Repo
+		repo.NewIntentSampleRepo,
+		repo.NewReviewRepo,
+		repo.NewTransactionRepo,
+		repo.NewAuditLogRepo,
+		repo.NewInterviewRepo,
+		repo.NewQuestionRepo,
+		repo.NewTranscriptRepo,
+		repo.NewRubricScoreRepo,
+		repo.NewFeedbackItemRepo,
+		repo.NewCodeSnapshotRepo,
+		repo.NewLLMRepo,
+		repo.NewTTSRepo,
+		repo.NewFileRepo,
+		repo.NewPromptTemplateRepo,
+		repo.NewGuardActivationRepo,
+		repo.NewUserRepo,
+		repo.NewSettingRepo,
+		wire.NewSet(
+			repo.NewMessageQueueRepo,
+			wire.Bind(new(repo.MessageQueueProducerRepo), new(repo.MessageQueueRepo)),
+		),
+
+		// HTTP
+		http.NewHTTPCLient,
+
+		// Postgres
+		postgres.NewPostgresDatabase,
+
+		// Zerolog
+		zerolog.NewZerologLogger,
+
+		// Config
+		config.LoadDatabaseConfig,
+		config.LoadLLMConfig,
+		config.LoadInjectionConfig,
+		config.LoadTTSConfig,
+		config.LoadMessageQueueConfig,
+		config.LoadObjectStorageConfig,
+
+		// CLI
+		app.NewCLI,
+	)
+	return &app.CLI{}, nil
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/ahleongzc/leetcode-live-backend/internal/handler/rpc_handler"
 	"github.com/ahleongzc/leetcode-live-backend/internal/handler/rpc_handler/interceptor"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo"
-	"github.com/ahleongzc/leetcode-live-backend/internal/repo/cloudflare"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo/fasttext"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo/http"
 	"github.com/ahleongzc/leetcode-live-backend/internal/repo/postgres"
@@ -43,12 +42,20 @@ func InitializeApplication() (*app.Application, error) {
 	middlewareMiddleware := middleware.NewMiddleware(authService, logger)
 	httpServerConfig := config.LoadHTTPServerConfig()
 	authHandler := httphandler.NewAuthHandler(authService)
+	intentSampleRepo := repo.NewIntentSampleRepo(db)
+	intentSampleService := service.NewIntentSampleService(intentSampleRepo)
 	settingRepo := repo.NewSettingRepo(db)
 	userService := service.NewUserService(userRepo, settingRepo)
-	userHandler := httphandler.NewUserHandler(userService)
 	transcriptRepo := repo.NewTranscriptRepo(db)
-	transcriptManager := service.NewTranscriptManager(transcriptRepo)
-	healthHandler := httphandler.NewHealthHandler(transcriptManager)
+	codeSnapshotRepo := repo.NewCodeSnapshotRepo(db)
+	objectStorageConfig, err := config.LoadObjectStorageConfig()
+	if err != nil {
+		return nil, err
+	}
+	fileRepo, err := repo.NewFileRepo(objectStorageConfig)
+	if err != nil {
+		return nil, err
+	}
 	websocketConfig := config.LoadWebsocketConfig()
 	ttsConfig, err := config.LoadTTSConfig()
 	if err != nil {
@@ -68,24 +75,39 @@ func InitializeApplication() (*app.Application, error) {
 		return nil, err
 	}
 	aiUseCase := service.NewAIUseCase(ttsRepo, llmRepo)
+	promptTemplateRepo := repo.NewPromptTemplateRepo(db)
+	promptService := service.NewPromptService(promptTemplateRepo)
+	transcriptManager := service.NewTranscriptManager(llmConfig, aiUseCase, transcriptRepo, codeSnapshotRepo, fileRepo, objectStorageConfig, promptService)
+	healthHandler := httphandler.NewHealthHandler(transcriptManager)
 	reviewRepo := repo.NewReviewRepo(db)
-	reviewService := service.NewReviewService(aiUseCase, reviewRepo, interviewRepo, transcriptManager)
+	rubricScoreRepo := repo.NewRubricScoreRepo(db)
+	feedbackItemRepo := repo.NewFeedbackItemRepo(db)
 	questionRepo := repo.NewQuestionRepo(db)
-	questionService := service.NewQuestionService(questionRepo)
-	objectStorageConfig, err := config.LoadObjectStorageConfig()
-	if err != nil {
-		return nil, err
-	}
-	s3Client, err := cloudflare.NewCloudflareR2ObjectStorageClient(objectStorageConfig)
+	messageQueueConfig, err := config.LoadMessageQueueConfig()
 	if err != nil {
 		return nil, err
 	}
-	fileRepo := repo.NewFileRepo(s3Client, objectStorageConfig)
-	messageQueueConfig, err := config.LoadMessageQueueConfig()
+	messageQueueRepo := repo.NewMessageQueueRepo(messageQueueConfig)
+	injectionConfig, err := config.LoadInjectionConfig()
 	if err != nil {
 		return nil, err
 	}
-	messageQueueRepo := repo.NewMessageQueueRepo(messageQueueConfig)
+	guardActivationRepo := repo.NewGuardActivationRepo(db)
+	guardService := service.NewGuardService(injectionConfig, aiUseCase, promptService, guardActivationRepo)
+	auditLogRepo := repo.NewAuditLogRepo(db)
+	transactionRepo := repo.NewTransactionRepo(db)
+	reviewService := service.NewReviewService(llmConfig, aiUseCase, reviewRepo, interviewRepo, questionRepo, rubricScoreRepo, feedbackItemRepo, messageQueueRepo, transcriptManager, promptService, guardService, auditLogRepo, transactionRepo)
+	experimentRepo := repo.NewExperimentRepo(db)
+	experimentVariantRepo := repo.NewExperimentVariantRepo(db)
+	experimentService := service.NewExperimentService(experimentRepo, experimentVariantRepo, interviewRepo, reviewRepo, transcriptRepo, transactionRepo)
+	questionService := service.NewQuestionService(questionRepo)
+	adminHandler := httphandler.NewAdminHandler(intentSampleService, reviewService, experimentService, questionService)
+	transcriptAnnotationRepo := repo.NewTranscriptAnnotationRepo(db)
+	reviewerService := service.NewReviewerService(reviewService, reviewRepo, interviewRepo, transcriptRepo, rubricScoreRepo, feedbackItemRepo, transcriptAnnotationRepo, auditLogRepo, userRepo, transactionRepo)
+	reviewerHandler := httphandler.NewReviewerHandler(reviewerService, reviewService)
+	studyPlanRepo := repo.NewStudyPlanRepo(db)
+	studyPlanService := service.NewStudyPlanService(llmConfig, aiUseCase, studyPlanRepo, interviewRepo, reviewRepo, rubricScoreRepo, questionRepo, messageQueueRepo, promptService)
+	userHandler := httphandler.NewUserHandler(userService, studyPlanService)
 	intentClassificationConfig, err := config.LoadIntentClassificationConfig()
 	if err != nil {
 		return nil, err
@@ -95,14 +117,24 @@ func InitializeApplication() (*app.Application, error) {
 		return nil, err
 	}
 	intentClassificationRepo := repo.NewIntentClassificationRepo(fastTextPool)
-	interviewService := service.NewInterviewService(aiUseCase, userService, authService, reviewService, questionService, transcriptManager, fileRepo, reviewRepo, questionRepo, interviewRepo, messageQueueRepo, intentClassificationRepo)
-	interviewHandler := httphandler.NewInterviewHandler(websocketConfig, authService, interviewService, logger)
-	httpServer := app.NewHTTPServer(logger, middlewareMiddleware, httpServerConfig, authHandler, userHandler, healthHandler, interviewHandler)
+	interviewEventRepo := repo.NewInterviewEventRepo(db)
+	ttsCacheEntryRepo := repo.NewTTSCacheEntryRepo(db)
+	ttsCacheService := service.NewTTSCacheService(ttsConfig, aiUseCase, fileRepo, ttsCacheEntryRepo)
+	speechNormalizer := service.NewSpeechNormalizer()
+	cannedPhraseService := service.NewCannedPhraseService(ttsCacheService, promptService)
+	replyService := service.NewReplyService(aiUseCase, promptService, guardService, questionRepo)
+	interviewService := service.NewInterviewService(replyService, userService, authService, reviewService, questionService, transcriptManager, intentSampleService, ttsCacheService, speechNormalizer, cannedPhraseService, promptService, experimentService, guardService, reviewRepo, rubricScoreRepo, feedbackItemRepo, questionRepo, interviewRepo, interviewEventRepo, messageQueueRepo, intentClassificationRepo)
+	transcriptExportService := service.NewTranscriptExportService(interviewRepo, questionRepo, transcriptManager)
+	interviewHandler := httphandler.NewInterviewHandler(websocketConfig, authService, interviewService, transcriptExportService, logger)
+	fileService := service.NewFileService(objectStorageConfig, fileRepo)
+	fileHandler := httphandler.NewFileHandler(fileService)
+	httpServer := app.NewHTTPServer(logger, middlewareMiddleware, httpServerConfig, authHandler, adminHandler, reviewerHandler, userHandler, healthHandler, interviewHandler, fileHandler)
 	rpcServerConfig := config.LoadRPCServerConfig()
 	proxyHandler := rpchandler.NewProxyHandler(authService, interviewService)
 	interceptorInterceptor := interceptor.NewInterceptor(logger)
 	rpcServer := app.NewRPCServer(logger, rpcServerConfig, proxyHandler, interceptorInterceptor)
-	reviewConsumer := consumer.NewReviewConsumer(reviewService, messageQueueRepo, logger)
+	reviewConsumer := consumer.NewReviewConsumer(reviewService, studyPlanService, messageQueueRepo, logger)
+	studyPlanConsumer := consumer.NewStudyPlanConsumer(studyPlanService, messageQueueRepo, logger)
 	houseKeeper := background.NewHouseKeeper(sessionRepo, logger)
 	inMemoryQueueConfig, err := config.LoadInMemoryQueueConfig()
 	if err != nil {
@@ -110,6 +142,77 @@ func InitializeApplication() (*app.Application, error) {
 	}
 	inMemoryCallbackQueueRepo := repo.NewInMemoryCallbackQueueRepo(inMemoryQueueConfig)
 	workerPool := background.NewWorkerPool(inMemoryCallbackQueueRepo, logger)
-	application := app.NewApplication(httpServer, rpcServer, reviewConsumer, houseKeeper, workerPool)
+	application := app.NewApplication(httpServer, rpcServer, reviewConsumer, studyPlanConsumer, houseKeeper, workerPool, cannedPhraseService, logger)
 	return application, nil
 }
+
+func InitializeCLI() (*app.CLI, error) {
+	logger := zerolog.NewZerologLogger()
+	databaseConfig, err := config.LoadDatabaseConfig()
+	if err != nil {
+		return nil, err
+	}
+	db, err := postgres.NewPostgresDatabase(databaseConfig)
+	if err != nil {
+		return nil, err
+	}
+	intentSampleRepo := repo.NewIntentSampleRepo(db)
+	intentSampleService := service.NewIntentSampleService(intentSampleRepo)
+	llmConfig, err := config.LoadLLMConfig()
+	if err != nil {
+		return nil, err
+	}
+	ttsConfig, err := config.LoadTTSConfig()
+	if err != nil {
+		return nil, err
+	}
+	client := http.NewHTTPCLient()
+	ttsRepo, err := repo.NewTTSRepo(ttsConfig, client)
+	if err != nil {
+		return nil, err
+	}
+	llmRepo, err := repo.NewLLMRepo(llmConfig, client)
+	if err != nil {
+		return nil, err
+	}
+	aiUseCase := service.NewAIUseCase(ttsRepo, llmRepo)
+	reviewRepo := repo.NewReviewRepo(db)
+	interviewRepo := repo.NewInterviewRepo(db)
+	questionRepo := repo.NewQuestionRepo(db)
+	rubricScoreRepo := repo.NewRubricScoreRepo(db)
+	feedbackItemRepo := repo.NewFeedbackItemRepo(db)
+	messageQueueConfig, err := config.LoadMessageQueueConfig()
+	if err != nil {
+		return nil, err
+	}
+	messageQueueRepo := repo.NewMessageQueueRepo(messageQueueConfig)
+	transcriptRepo := repo.NewTranscriptRepo(db)
+	codeSnapshotRepo := repo.NewCodeSnapshotRepo(db)
+	objectStorageConfig, err := config.LoadObjectStorageConfig()
+	if err != nil {
+		return nil, err
+	}
+	fileRepo, err := repo.NewFileRepo(objectStorageConfig)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepo := repo.NewPromptTemplateRepo(db)
+	promptService := service.NewPromptService(promptTemplateRepo)
+	transcriptManager := service.NewTranscriptManager(llmConfig, aiUseCase, transcriptRepo, codeSnapshotRepo, fileRepo, objectStorageConfig, promptService)
+	injectionConfig, err := config.LoadInjectionConfig()
+	if err != nil {
+		return nil, err
+	}
+	guardActivationRepo := repo.NewGuardActivationRepo(db)
+	guardService := service.NewGuardService(injectionConfig, aiUseCase, promptService, guardActivationRepo)
+	auditLogRepo := repo.NewAuditLogRepo(db)
+	transactionRepo := repo.NewTransactionRepo(db)
+	reviewService := service.NewReviewService(llmConfig, aiUseCase, reviewRepo, interviewRepo, questionRepo, rubricScoreRepo, feedbackItemRepo, messageQueueRepo, transcriptManager, promptService, guardService, auditLogRepo, transactionRepo)
+	replyService := service.NewReplyService(aiUseCase, promptService, guardService, questionRepo)
+	regressionService := service.NewRegressionService(llmConfig, aiUseCase, replyService, promptService, transcriptManager, interviewRepo, questionRepo)
+	userRepo := repo.NewUserRepo(db)
+	settingRepo := repo.NewSettingRepo(db)
+	userService := service.NewUserService(userRepo, settingRepo)
+	cli := app.NewCLI(logger, intentSampleService, reviewService, transcriptManager, regressionService, userService)
+	return cli, nil
+}
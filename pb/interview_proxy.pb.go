@@ -71,13 +71,20 @@ func (Source) EnumDescriptor() ([]byte, []int) {
 }
 
 type InterviewMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Source        Source                 `protobuf:"varint,1,opt,name=source,proto3,enum=Source" json:"source,omitempty"`
-	InterviewId   uint64                 `protobuf:"varint,2,opt,name=interview_id,json=interviewId,proto3" json:"interview_id,omitempty"`
-	Chunk         *string                `protobuf:"bytes,3,opt,name=chunk,proto3,oneof" json:"chunk,omitempty"`
-	Code          *string                `protobuf:"bytes,4,opt,name=code,proto3,oneof" json:"code,omitempty"`
-	Url           *string                `protobuf:"bytes,5,opt,name=url,proto3,oneof" json:"url,omitempty"`
-	End           bool                   `protobuf:"varint,6,opt,name=end,proto3" json:"end,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      Source                 `protobuf:"varint,1,opt,name=source,proto3,enum=Source" json:"source,omitempty"`
+	InterviewId uint64                 `protobuf:"varint,2,opt,name=interview_id,json=interviewId,proto3" json:"interview_id,omitempty"`
+	Chunk       *string                `protobuf:"bytes,3,opt,name=chunk,proto3,oneof" json:"chunk,omitempty"`
+	Code        *string                `protobuf:"bytes,4,opt,name=code,proto3,oneof" json:"code,omitempty"`
+	Url         *string                `protobuf:"bytes,5,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	End         bool                   `protobuf:"varint,6,opt,name=end,proto3" json:"end,omitempty"`
+	// Only set on server messages, the reply as text for captions and for when the audio cannot be played
+	Text      *string `protobuf:"bytes,7,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	MessageId *string `protobuf:"bytes,8,opt,name=message_id,json=messageId,proto3,oneof" json:"message_id,omitempty"`
+	// The position of the reply among the replies of the interviewer in the interview, starting from 1
+	Sequence *uint64 `protobuf:"varint,9,opt,name=sequence,proto3,oneof" json:"sequence,omitempty"`
+	// The UUID of the transcript that the reply is written to
+	TranscriptId  *string `protobuf:"bytes,10,opt,name=transcript_id,json=transcriptId,proto3,oneof" json:"transcript_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -154,6 +161,34 @@ func (x *InterviewMessage) GetEnd() bool {
 	return false
 }
 
+func (x *InterviewMessage) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *InterviewMessage) GetMessageId() string {
+	if x != nil && x.MessageId != nil {
+		return *x.MessageId
+	}
+	return ""
+}
+
+func (x *InterviewMessage) GetSequence() uint64 {
+	if x != nil && x.Sequence != nil {
+		return *x.Sequence
+	}
+	return 0
+}
+
+func (x *InterviewMessage) GetTranscriptId() string {
+	if x != nil && x.TranscriptId != nil {
+		return *x.TranscriptId
+	}
+	return ""
+}
+
 type VerificationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InterviewId   *uint64                `protobuf:"varint,1,opt,name=interview_id,json=interviewId,proto3,oneof" json:"interview_id,omitempty"`
@@ -406,17 +441,27 @@ var File_pb_interview_proxy_proto protoreflect.FileDescriptor
 
 const file_pb_interview_proxy_proto_rawDesc = "" +
 	"\n" +
-	"\x18pb/interview_proxy.proto\"\xce\x01\n" +
+	"\x18pb/interview_proxy.proto\"\x8d\x03\n" +
 	"\x10InterviewMessage\x12\x1f\n" +
 	"\x06source\x18\x01 \x01(\x0e2\a.SourceR\x06source\x12!\n" +
 	"\finterview_id\x18\x02 \x01(\x04R\vinterviewId\x12\x19\n" +
 	"\x05chunk\x18\x03 \x01(\tH\x00R\x05chunk\x88\x01\x01\x12\x17\n" +
 	"\x04code\x18\x04 \x01(\tH\x01R\x04code\x88\x01\x01\x12\x15\n" +
 	"\x03url\x18\x05 \x01(\tH\x02R\x03url\x88\x01\x01\x12\x10\n" +
-	"\x03end\x18\x06 \x01(\bR\x03endB\b\n" +
+	"\x03end\x18\x06 \x01(\bR\x03end\x12\x17\n" +
+	"\x04text\x18\a \x01(\tH\x03R\x04text\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"message_id\x18\b \x01(\tH\x04R\tmessageId\x88\x01\x01\x12\x1f\n" +
+	"\bsequence\x18\t \x01(\x04H\x05R\bsequence\x88\x01\x01\x12(\n" +
+	"\rtranscript_id\x18\n" +
+	" \x01(\tH\x06R\ftranscriptId\x88\x01\x01B\b\n" +
 	"\x06_chunkB\a\n" +
 	"\x05_codeB\x06\n" +
-	"\x04_url\"O\n" +
+	"\x04_urlB\a\n" +
+	"\x05_textB\r\n" +
+	"\v_message_idB\v\n" +
+	"\t_sequenceB\x10\n" +
+	"\x0e_transcript_id\"O\n" +
 	"\x14VerificationResponse\x12&\n" +
 	"\finterview_id\x18\x01 \x01(\x04H\x00R\vinterviewId\x88\x01\x01B\x0f\n" +
 	"\r_interview_id\".\n" +
@@ -34,6 +34,8 @@ type InterviewProxyClient interface {
 	JoinInterview(ctx context.Context, in *JoinInterviewRequest, opts ...grpc.CallOption) (*JoinInterviewResponse, error)
 	PauseInterview(ctx context.Context, in *PauseInterviewRequest, opts ...grpc.CallOption) (*PauseInterviewResponse, error)
 	// Bidirectional streaming
+	// Right after JoinInterview, the proxy sends a message with only the interview ID. The server replies to it with the
+	// opening of the interview before the candidate says anything, and the time warnings are sent on the same stream
 	ProcessIncomingMessage(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[InterviewMessage, InterviewMessage], error)
 }
 
@@ -97,6 +99,8 @@ type InterviewProxyServer interface {
 	JoinInterview(context.Context, *JoinInterviewRequest) (*JoinInterviewResponse, error)
 	PauseInterview(context.Context, *PauseInterviewRequest) (*PauseInterviewResponse, error)
 	// Bidirectional streaming
+	// Right after JoinInterview, the proxy sends a message with only the interview ID. The server replies to it with the
+	// opening of the interview before the candidate says anything, and the time warnings are sent on the same stream
 	ProcessIncomingMessage(grpc.BidiStreamingServer[InterviewMessage, InterviewMessage]) error
 	mustEmbedUnimplementedInterviewProxyServer()
 }